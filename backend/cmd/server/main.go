@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // user time zones work without system zoneinfo
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -52,7 +53,6 @@ func main() {
 	r.Use(middleware.RequestID)
 	r.Use(apimiddleware.NewLogging(logger)) // Use our custom zap-based logging middleware
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
 
 	// Configure CORS
 	logger.Info("CORS configuration",
@@ -0,0 +1,328 @@
+// Package anomaly flags expenses that stand out from the user's own history
+// and raises a warning explaining why.
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/budget"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/notify"
+)
+
+const (
+	// historyYears is how far back a transaction's norms are drawn from.
+	historyYears = 1
+	// minSamples is the fewest earlier expenses a category or payee norm is
+	// built from.
+	minSamples = 5
+	// outlierDeviations is how many standard deviations above the mean an
+	// amount must be to stand out.
+	outlierDeviations = 3
+	// outlierMultiple is how many times the mean an amount must also be, so
+	// spending that hardly varies does not flag small changes.
+	outlierMultiple = 2
+	// largePercentile is the share of all expenses a first payment to a
+	// payee must exceed to be large.
+	largePercentile = 0.9
+	// minPercentileSamples is the fewest expenses largePercentile is taken
+	// over.
+	minPercentileSamples = 10
+	// duplicateWindow is how close together two identical charges must be
+	// to look like one charged twice.
+	duplicateWindow = time.Hour
+)
+
+// Finding is one reason a transaction looks unusual.
+type Finding struct {
+	Kind        db.AnomalyKind `json:"kind"`
+	Explanation string         `json:"explanation"`
+}
+
+// Check looks for anomalies in t and records them.
+//
+// Each reason notifies at most once per transaction: it is recorded in
+// transaction_anomalies, and only newly found reasons raise a warning, one
+// per check listing them all. Reasons that no longer apply after an edit are
+// cleared.
+//
+// Run it with transaction-scoped queries after a transaction is saved so
+// the flags commit with it, then dispatch the returned notification, if
+// any, once committed.
+func Check(ctx context.Context, q *db.Queries, t db.Transaction) (*db.Notification, error) {
+	user, err := q.GetUser(ctx, t.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	c := &checker{q: q, user: user, t: t, amount: t.BaseAmount.Rat()}
+	c.loc, err = time.LoadLocation(user.Timezone)
+	if err != nil {
+		c.loc = time.UTC
+	}
+	if t.PayeeID.Valid {
+		p, err := q.GetPayee(ctx, db.GetPayeeParams{ID: t.PayeeID, UserID: t.UserID})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get payee: %w", err)
+		}
+		c.payee = p.Name
+	}
+
+	findings, err := c.detect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[db.AnomalyKind]bool)
+	var fresh []Finding
+	for _, f := range findings {
+		found[f.Kind] = true
+		inserted, err := q.CreateTransactionAnomaly(ctx, db.CreateTransactionAnomalyParams{
+			TransactionID: t.ID,
+			Kind:          f.Kind,
+			UserID:        t.UserID,
+			Explanation:   f.Explanation,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record anomaly: %w", err)
+		}
+		if inserted > 0 {
+			fresh = append(fresh, f)
+		}
+	}
+	existing, err := q.ListTransactionAnomalies(ctx, t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list anomalies: %w", err)
+	}
+	for _, a := range existing {
+		if found[a.Kind] {
+			continue
+		}
+		if err := q.DeleteTransactionAnomaly(ctx, db.DeleteTransactionAnomalyParams{TransactionID: t.ID, Kind: a.Kind}); err != nil {
+			return nil, fmt.Errorf("failed to clear anomaly: %w", err)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil, nil
+	}
+
+	n, err := notify.Create(ctx, q, c.message(fresh))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fresh {
+		if err := q.SetTransactionAnomalyNotification(ctx, db.SetTransactionAnomalyNotificationParams{
+			TransactionID:  t.ID,
+			Kind:           f.Kind,
+			NotificationID: n.ID,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to link anomaly notification: %w", err)
+		}
+	}
+	return &n, nil
+}
+
+// checker holds what the checks on one transaction share.
+type checker struct {
+	q    *db.Queries
+	user db.User
+	loc  *time.Location
+	t    db.Transaction
+	// amount is the transaction's amount in the user's currency.
+	amount *big.Rat
+	// payee is the name of the transaction's payee, if any.
+	payee string
+}
+
+// detect explains what, if anything, is unusual about the transaction.
+// Only expenses are checked.
+func (c *checker) detect(ctx context.Context) ([]Finding, error) {
+	if c.t.Type != db.TransactionTypeExpense {
+		return nil, nil
+	}
+	var findings []Finding
+	for _, check := range []func(context.Context) (*Finding, error){c.categoryOutlier, c.payeeOutlier, c.newPayee, c.duplicate} {
+		f, err := check(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			findings = append(findings, *f)
+		}
+	}
+	return findings, nil
+}
+
+// history is the window of earlier expenses norms are drawn from.
+func (c *checker) history() (start, end pgtype.Timestamptz) {
+	at := c.t.Date.Time
+	return pgtype.Timestamptz{Time: at.AddDate(-historyYears, 0, 0), Valid: true}, pgtype.Timestamptz{Time: at, Valid: true}
+}
+
+// stats returns the number and mean of the earlier expenses matching the
+// category or payee filter, and whether the amount is an outlier among them.
+func (c *checker) stats(ctx context.Context, categoryID, payeeID pgtype.UUID) (int64, *big.Rat, bool, error) {
+	start, end := c.history()
+	s, err := c.q.GetExpenseStats(ctx, db.GetExpenseStatsParams{
+		UserID:      c.t.UserID,
+		ExcludeID:   c.t.ID,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		CategoryID:  categoryID,
+		PayeeID:     payeeID,
+	})
+	if err != nil {
+		return 0, nil, false, fmt.Errorf("failed to get expense statistics: %w", err)
+	}
+	mean := s.Mean.Rat()
+	if s.SampleSize < minSamples || mean.Sign() <= 0 {
+		return s.SampleSize, mean, false, nil
+	}
+	// amount > mean + k·stddev and amount >= m·mean.
+	limit := new(big.Rat).Mul(s.Stddev.Rat(), big.NewRat(outlierDeviations, 1))
+	limit.Add(limit, mean)
+	multiple := new(big.Rat).Mul(mean, big.NewRat(outlierMultiple, 1))
+	return s.SampleSize, mean, c.amount.Cmp(limit) > 0 && c.amount.Cmp(multiple) >= 0, nil
+}
+
+func (c *checker) categoryOutlier(ctx context.Context) (*Finding, error) {
+	if !c.t.CategoryID.Valid {
+		return nil, nil
+	}
+	n, mean, outlier, err := c.stats(ctx, c.t.CategoryID, pgtype.UUID{})
+	if err != nil || !outlier {
+		return nil, err
+	}
+	category, err := c.q.GetCategory(ctx, db.GetCategoryParams{ID: c.t.CategoryID, UserID: c.t.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return &Finding{
+		Kind: db.AnomalyKindCategoryOutlier,
+		Explanation: fmt.Sprintf("%s is far above your usual %s spending: your %d %s expenses over the past year averaged %s.",
+			c.money(c.amount), category.Name, n, category.Name, c.money(mean)),
+	}, nil
+}
+
+func (c *checker) payeeOutlier(ctx context.Context) (*Finding, error) {
+	if !c.t.PayeeID.Valid {
+		return nil, nil
+	}
+	n, mean, outlier, err := c.stats(ctx, pgtype.UUID{}, c.t.PayeeID)
+	if err != nil || !outlier {
+		return nil, err
+	}
+	return &Finding{
+		Kind: db.AnomalyKindPayeeOutlier,
+		Explanation: fmt.Sprintf("%s is far above what you usually pay %s: your %d payments to them over the past year averaged %s.",
+			c.money(c.amount), c.payee, n, c.money(mean)),
+	}, nil
+}
+
+// newPayee flags a first payment to a payee that is larger than most of the
+// user's expenses.
+func (c *checker) newPayee(ctx context.Context) (*Finding, error) {
+	if !c.t.PayeeID.Valid {
+		return nil, nil
+	}
+	earlier, err := c.q.CountEarlierPayeeTransactions(ctx, db.CountEarlierPayeeTransactionsParams{
+		UserID:    c.t.UserID,
+		PayeeID:   c.t.PayeeID,
+		ExcludeID: c.t.ID,
+		Before:    c.t.Date,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count payee transactions: %w", err)
+	}
+	if earlier > 0 {
+		return nil, nil
+	}
+	start, end := c.history()
+	p, err := c.q.GetExpensePercentile(ctx, db.GetExpensePercentileParams{
+		Fraction:    largePercentile,
+		UserID:      c.t.UserID,
+		ExcludeID:   c.t.ID,
+		PeriodStart: start,
+		PeriodEnd:   end,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expense percentile: %w", err)
+	}
+	large := p.Amount.Rat()
+	if p.SampleSize < minPercentileSamples || c.amount.Cmp(large) <= 0 {
+		return nil, nil
+	}
+	return &Finding{
+		Kind: db.AnomalyKindNewPayee,
+		Explanation: fmt.Sprintf("This is your first payment to %s, and at %s it is larger than %d%% of your expenses, which are %s or less.",
+			c.payee, c.money(c.amount), int(largePercentile*100), c.money(large)),
+	}, nil
+}
+
+// duplicate flags a charge of the same amount to the same payee, or with
+// the same description, moments before or after this one.
+func (c *checker) duplicate(ctx context.Context) (*Finding, error) {
+	description := strings.TrimSpace(c.t.Description.String)
+	if !c.t.PayeeID.Valid && description == "" {
+		return nil, nil
+	}
+	at := c.t.Date.Time
+	other, err := c.q.FindDuplicateExpense(ctx, db.FindDuplicateExpenseParams{
+		UserID:      c.t.UserID,
+		ExcludeID:   c.t.ID,
+		Amount:      c.t.Amount,
+		Currency:    c.t.Currency,
+		WindowStart: pgtype.Timestamptz{Time: at.Add(-duplicateWindow), Valid: true},
+		WindowEnd:   pgtype.Timestamptz{Time: at.Add(duplicateWindow), Valid: true},
+		PayeeID:     c.t.PayeeID,
+		Description: description,
+		At:          c.t.Date,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate expense: %w", err)
+	}
+	return &Finding{
+		Kind: db.AnomalyKindDuplicate,
+		Explanation: fmt.Sprintf("An expense of %s to %s was also recorded at %s, so this may be a duplicate charge.",
+			c.money(c.amount), c.subject(), other.Date.Time.In(c.loc).Format("15:04 on Mon 2 Jan 2006")),
+	}, nil
+}
+
+// subject names what the transaction was for: its payee, else its
+// description.
+func (c *checker) subject() string {
+	if c.payee != "" {
+		return c.payee
+	}
+	return fmt.Sprintf("%q", strings.TrimSpace(c.t.Description.String))
+}
+
+func (c *checker) money(r *big.Rat) string {
+	return c.user.CurrencySymbol + budget.FormatAmount(r)
+}
+
+func (c *checker) message(findings []Finding) notify.Message {
+	explanations := make([]string, len(findings))
+	for i, f := range findings {
+		explanations[i] = f.Explanation
+	}
+	title := "Unusual expense of " + c.money(c.amount)
+	if c.payee != "" || strings.TrimSpace(c.t.Description.String) != "" {
+		title += " to " + c.subject()
+	}
+	return notify.Message{
+		UserID:  c.t.UserID,
+		Type:    db.NotificationTypeWarning,
+		Title:   title,
+		Message: strings.Join(explanations, " "),
+	}
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/fx"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+type AccountHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewAccountHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *AccountHandler {
+	return &AccountHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type accountRequest struct {
+	Name string `json:"name"`
+	// Type defaults to bank.
+	Type db.AccountType `json:"type"`
+	// OpeningBalance is what the account held before the first transaction
+	// recorded against it; it may be negative, e.g. for a credit card.
+	OpeningBalance money.Decimal `json:"openingBalance"`
+	Archived       bool          `json:"archived"`
+	// Currency defaults to the user's. It is set when the account is
+	// created and ignored on update.
+	Currency string `json:"currency"`
+}
+
+// accountResponse is an account with its current balance.
+type accountResponse struct {
+	db.Account
+	Balance money.Decimal `json:"balance"`
+}
+
+func (req *accountRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		return errors.New("name must be between 1 and 100 characters")
+	}
+	switch req.Type {
+	case "":
+		req.Type = db.AccountTypeBank
+	case db.AccountTypeCash, db.AccountTypeBank, db.AccountTypeMobileMoney, db.AccountTypeSavings, db.AccountTypeCreditCard, db.AccountTypeOther:
+	default:
+		return errors.New("type must be cash, bank, mobile_money, savings, credit_card or other")
+	}
+	if !req.OpeningBalance.Valid() {
+		req.OpeningBalance = money.New(0, money.Scale)
+	}
+	if !req.OpeningBalance.InRange() {
+		return errors.New("openingBalance is too large")
+	}
+	req.Currency = strings.ToUpper(strings.TrimSpace(req.Currency))
+	if req.Currency != "" && !fx.ValidCurrency(req.Currency) {
+		return errors.New("currency must be a three-letter currency code")
+	}
+	return nil
+}
+
+// respondAccount writes account with its balance as of now.
+func (h *AccountHandler) respondAccount(w http.ResponseWriter, r *http.Request, status int, account db.Account) {
+	balance, err := h.queries.GetAccountBalance(r.Context(), db.GetAccountBalanceParams{
+		AsOf:   pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		ID:     account.ID,
+		UserID: account.UserID,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get account balance", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get account balance")
+		return
+	}
+
+	respondJSON(w, status, accountResponse{Account: account, Balance: balance})
+}
+
+func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req accountRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Currency == "" {
+		user, err := h.queries.GetUser(r.Context(), userID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		if err != nil {
+			h.logger.Error("Failed to get user", zap.Error(err))
+			respondError(w, http.StatusInternalServerError, "failed to create account")
+			return
+		}
+		req.Currency = user.Currency
+	}
+	if !req.OpeningBalance.Fits(req.Currency) {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("openingBalance has more decimal places than %s allows", req.Currency))
+		return
+	}
+
+	account, err := h.queries.CreateAccount(r.Context(), db.CreateAccountParams{
+		ID:             utils.NewUUID(),
+		UserID:         userID,
+		Name:           req.Name,
+		Type:           req.Type,
+		OpeningBalance: req.OpeningBalance,
+		Currency:       req.Currency,
+	})
+	if isUniqueViolation(err) {
+		respondError(w, http.StatusConflict, "an account with this name already exists")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to create account", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create account")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, accountResponse{Account: account, Balance: account.OpeningBalance})
+}
+
+func (h *AccountHandler) GetAccountByID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	accountID, err := uuidParam(r, "accountID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	account, err := h.queries.GetAccount(r.Context(), db.GetAccountParams{ID: accountID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "account not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get account", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get account")
+		return
+	}
+
+	h.respondAccount(w, r, http.StatusOK, account)
+}
+
+// ListAccountsByUserID lists the user's accounts with their current
+// balances, open accounts first.
+func (h *AccountHandler) ListAccountsByUserID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	accounts, err := h.queries.ListAccountBalances(r.Context(), db.ListAccountBalancesParams{
+		AsOf:   pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		UserID: userID,
+	})
+	if err != nil {
+		h.logger.Error("Failed to list accounts", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list accounts")
+		return
+	}
+	if accounts == nil {
+		accounts = []db.ListAccountBalancesRow{}
+	}
+
+	respondJSON(w, http.StatusOK, accounts)
+}
+
+func (h *AccountHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	accountID, err := uuidParam(r, "accountID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req accountRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	existing, err := h.queries.GetAccount(r.Context(), db.GetAccountParams{ID: accountID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "account not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get account", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update account")
+		return
+	}
+	if !req.OpeningBalance.Fits(existing.Currency) {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("openingBalance has more decimal places than %s allows", existing.Currency))
+		return
+	}
+
+	account, err := h.queries.UpdateAccount(r.Context(), db.UpdateAccountParams{
+		ID:             accountID,
+		UserID:         userID,
+		Name:           req.Name,
+		Type:           req.Type,
+		OpeningBalance: req.OpeningBalance,
+		Archived:       req.Archived,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "account not found")
+		return
+	}
+	if isUniqueViolation(err) {
+		respondError(w, http.StatusConflict, "an account with this name already exists")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update account", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update account")
+		return
+	}
+
+	h.respondAccount(w, r, http.StatusOK, account)
+}
+
+// DeleteAccount removes an account. Its transactions, bills and recurring
+// transactions are kept, no longer linked to any account.
+func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	accountID, err := uuidParam(r, "accountID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.queries.DeleteAccount(r.Context(), db.DeleteAccountParams{ID: accountID, UserID: userID}); err != nil {
+		h.logger.Error("Failed to delete account", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete account")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
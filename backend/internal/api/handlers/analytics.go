@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/budget"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"go.uber.org/zap"
+)
+
+const (
+	maxAnalyticsBuckets  = 400
+	defaultRollingWindow = 3
+	maxRollingWindow     = 52
+	defaultHeatmapDays   = 90
+)
+
+// AnalyticsHandler serves chart data: spending over time, period-over-period
+// comparisons and a day-of-week heatmap. Buckets are zero-filled and laid
+// out in the user's time zone.
+type AnalyticsHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewAnalyticsHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+// granularity is the size of a spending bucket.
+type granularity struct {
+	name string
+	// of returns the bucket containing a time.
+	of func(time.Time, *time.Location) budget.Period
+	// defaultBuckets is how many buckets, up to the current one, are shown
+	// when no from_date is given.
+	defaultBuckets int
+}
+
+func parseGranularity(r *http.Request) (granularity, error) {
+	switch v := r.URL.Query().Get("granularity"); v {
+	case "day":
+		return granularity{v, budget.DayOf, 30}, nil
+	case "week":
+		return granularity{v, budget.WeekOf, 12}, nil
+	case "", "month":
+		return granularity{"month", budget.MonthOf, 12}, nil
+	case "cycle":
+		startDay := 1
+		if s := r.URL.Query().Get("cycle_start_day"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 || n > 28 {
+				return granularity{}, errors.New("cycle_start_day must be between 1 and 28")
+			}
+			startDay = n
+		}
+		of := func(t time.Time, loc *time.Location) budget.Period { return budget.CycleOf(t, loc, startDay) }
+		return granularity{v, of, 12}, nil
+	}
+	return granularity{}, errors.New("granularity must be day, week, month or cycle")
+}
+
+// queryDate reads an optional YYYY-MM-DD query parameter as midnight in loc.
+func queryDate(r *http.Request, name string, loc *time.Location) (time.Time, bool, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, false, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, v, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%s must be a YYYY-MM-DD date", name)
+	}
+	return t, true, nil
+}
+
+// buckets lays out the buckets from the one containing from to the one
+// containing to, returning their starts and the end of the last.
+func (g granularity) buckets(from, to time.Time, loc *time.Location) ([]pgtype.Timestamptz, time.Time, error) {
+	var starts []pgtype.Timestamptz
+	b := g.of(from, loc)
+	for ; !b.Start.After(to); b = g.of(b.End, loc) {
+		if len(starts) == maxAnalyticsBuckets {
+			return nil, time.Time{}, fmt.Errorf("range spans more than %d buckets", maxAnalyticsBuckets)
+		}
+		starts = append(starts, pgtype.Timestamptz{Time: b.Start, Valid: true})
+	}
+	if len(starts) == 0 {
+		return nil, time.Time{}, errors.New("from_date must not be after to_date")
+	}
+	return starts, b.Start, nil
+}
+
+// location returns the user's time zone, writing an error response if the
+// user cannot be loaded.
+func (h *AnalyticsHandler) location(w http.ResponseWriter, r *http.Request, userID pgtype.UUID) (*time.Location, bool) {
+	user, err := h.queries.GetUser(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get user")
+		return nil, false
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return loc, true
+}
+
+type spendingCategory struct {
+	ID    pgtype.UUID `json:"id"`
+	Name  string      `json:"name"`
+	Color string      `json:"color"`
+}
+
+type spendingCategoryAmount struct {
+	CategoryID pgtype.UUID   `json:"categoryId"`
+	Amount     money.Decimal `json:"amount"`
+}
+
+type spendingBucket struct {
+	Start          time.Time                `json:"start"`
+	End            time.Time                `json:"end"`
+	Total          money.Decimal            `json:"total"`
+	Change         money.Decimal            `json:"change"`
+	ChangePercent  money.Decimal            `json:"changePercent"`
+	RollingAverage money.Decimal            `json:"rollingAverage"`
+	Categories     []spendingCategoryAmount `json:"categories"`
+}
+
+type spendingTrendResponse struct {
+	Granularity string             `json:"granularity"`
+	Start       time.Time          `json:"start"`
+	End         time.Time          `json:"end"`
+	Window      int                `json:"window"`
+	Categories  []spendingCategory `json:"categories"`
+	Buckets     []spendingBucket   `json:"buckets"`
+}
+
+// SpendingTrend returns expenses per bucket, overall and per category, with
+// each bucket's change on the one before and a rolling average over the last
+// window buckets. Uncategorized spending has a null category ID.
+func (h *AnalyticsHandler) SpendingTrend(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	g, err := parseGranularity(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	window := defaultRollingWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		window, err = strconv.Atoi(v)
+		if err != nil || window < 1 || window > maxRollingWindow {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("window must be between 1 and %d", maxRollingWindow))
+			return
+		}
+	}
+	categoryIDs, err := parseUUIDList(r.URL.Query().Get("category_id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	loc, ok := h.location(w, r, userID)
+	if !ok {
+		return
+	}
+	to, _, err := queryDate(r, "to_date", loc)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if to.IsZero() {
+		to = time.Now().In(loc)
+	}
+	from, ok, err := queryDate(r, "from_date", loc)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !ok {
+		from = g.of(to, loc).Start
+		for range g.defaultBuckets - 1 {
+			from = g.of(from.Add(-time.Nanosecond), loc).Start
+		}
+	}
+	starts, end, err := g.buckets(from, to, loc)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if categoryIDs == nil {
+		categoryIDs = []pgtype.UUID{}
+	}
+	periodEnd := pgtype.Timestamptz{Time: end, Valid: true}
+
+	totals, err := h.queries.ListSpendingTotals(r.Context(), db.ListSpendingTotalsParams{
+		PeriodEnd:       periodEnd,
+		BucketStarts:    starts,
+		UserID:          userID,
+		CategoryIds:     categoryIDs,
+		WindowPreceding: int32(window - 1),
+	})
+	if err != nil {
+		h.logger.Error("Failed to list spending totals", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get spending trend")
+		return
+	}
+	rows, err := h.queries.ListSpendingBuckets(r.Context(), db.ListSpendingBucketsParams{
+		PeriodEnd:    periodEnd,
+		BucketStarts: starts,
+		UserID:       userID,
+		CategoryIds:  categoryIDs,
+	})
+	if err != nil {
+		h.logger.Error("Failed to list spending buckets", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get spending trend")
+		return
+	}
+
+	resp := spendingTrendResponse{
+		Granularity: g.name,
+		Start:       starts[0].Time,
+		End:         end,
+		Window:      window,
+		Categories:  []spendingCategory{},
+		Buckets:     make([]spendingBucket, 0, len(totals)),
+	}
+	index := make(map[int64]int, len(totals))
+	for _, t := range totals {
+		index[t.BucketStart.Time.Unix()] = len(resp.Buckets)
+		resp.Buckets = append(resp.Buckets, spendingBucket{
+			Start:          t.BucketStart.Time.In(loc),
+			End:            t.BucketEnd.Time.In(loc),
+			Total:          t.Total,
+			Change:         t.Change,
+			ChangePercent:  t.ChangePercent,
+			RollingAverage: t.RollingAverage,
+			Categories:     []spendingCategoryAmount{},
+		})
+	}
+	// Every bucket lists the same categories in the same order, so the
+	// first bucket's rows give the legend.
+	for _, row := range rows {
+		i, ok := index[row.BucketStart.Time.Unix()]
+		if !ok {
+			continue
+		}
+		if i == 0 {
+			resp.Categories = append(resp.Categories, spendingCategory{ID: row.CategoryID, Name: row.CategoryName, Color: row.CategoryColor})
+		}
+		resp.Buckets[i].Categories = append(resp.Buckets[i].Categories, spendingCategoryAmount{CategoryID: row.CategoryID, Amount: row.Amount})
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+type comparisonResponse struct {
+	Period        string                         `json:"period"`
+	CurrentStart  time.Time                      `json:"currentStart"`
+	CurrentEnd    time.Time                      `json:"currentEnd"`
+	PreviousStart time.Time                      `json:"previousStart"`
+	PreviousEnd   time.Time                      `json:"previousEnd"`
+	Categories    []db.ListCategoryComparisonRow `json:"categories"`
+}
+
+// CompareSpending compares each category's expenses in a period (as for the
+// summary endpoint) with the period before it.
+func (h *AnalyticsHandler) CompareSpending(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	loc, ok := h.location(w, r, userID)
+	if !ok {
+		return
+	}
+	name := r.URL.Query().Get("period")
+	current, err := parseSummaryPeriod(name, time.Now(), loc)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if name == "" {
+		name = "month"
+	}
+	previous := current.previous(loc)
+
+	rows, err := h.queries.ListCategoryComparison(r.Context(), db.ListCategoryComparisonParams{
+		CurrentStart:  pgtype.Timestamptz{Time: current.Start, Valid: true},
+		CurrentEnd:    pgtype.Timestamptz{Time: current.End, Valid: true},
+		PreviousStart: pgtype.Timestamptz{Time: previous.Start, Valid: true},
+		PreviousEnd:   pgtype.Timestamptz{Time: previous.End, Valid: true},
+		UserID:        userID,
+	})
+	if err != nil {
+		h.logger.Error("Failed to compare spending", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to compare spending")
+		return
+	}
+	if rows == nil {
+		rows = []db.ListCategoryComparisonRow{}
+	}
+
+	respondJSON(w, http.StatusOK, comparisonResponse{
+		Period:        name,
+		CurrentStart:  current.Start,
+		CurrentEnd:    current.End,
+		PreviousStart: previous.Start,
+		PreviousEnd:   previous.End,
+		Categories:    rows,
+	})
+}
+
+type heatmapResponse struct {
+	Start time.Time                   `json:"start"`
+	End   time.Time                   `json:"end"`
+	Cells []db.ListSpendingHeatmapRow `json:"cells"`
+}
+
+// SpendingHeatmap returns expense counts and totals for each day of the week
+// (1 = Monday) and hour of the day, over the last 90 days by default.
+func (h *AnalyticsHandler) SpendingHeatmap(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	loc, ok := h.location(w, r, userID)
+	if !ok {
+		return
+	}
+	to, ok, err := queryDate(r, "to_date", loc)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !ok {
+		to = budget.DayOf(time.Now(), loc).Start
+	}
+	end := to.AddDate(0, 0, 1)
+	start, ok, err := queryDate(r, "from_date", loc)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !ok {
+		start = end.AddDate(0, 0, -defaultHeatmapDays)
+	}
+	if !start.Before(end) {
+		respondError(w, http.StatusBadRequest, "from_date must not be after to_date")
+		return
+	}
+
+	cells, err := h.queries.ListSpendingHeatmap(r.Context(), db.ListSpendingHeatmapParams{
+		UserID:      userID,
+		PeriodStart: pgtype.Timestamptz{Time: start, Valid: true},
+		PeriodEnd:   pgtype.Timestamptz{Time: end, Valid: true},
+		Tz:          loc.String(),
+	})
+	if err != nil {
+		h.logger.Error("Failed to get spending heatmap", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get spending heatmap")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, heatmapResponse{Start: start, End: end, Cells: cells})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"go.uber.org/zap"
+)
+
+// AnomalyHandler lists the transactions flagged as unusual when they were
+// saved.
+type AnomalyHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewAnomalyHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *AnomalyHandler {
+	return &AnomalyHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+// ListAnomaliesByUserID lists each reason a transaction was flagged, most
+// recently flagged first.
+func (h *AnomalyHandler) ListAnomaliesByUserID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, offset, err := pagination(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	anomalies, err := h.queries.ListAnomaliesByUser(r.Context(), db.ListAnomaliesByUserParams{
+		UserID:    userID,
+		RowLimit:  limit,
+		RowOffset: offset,
+	})
+	if err != nil {
+		h.logger.Error("Failed to list anomalies", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list anomalies")
+		return
+	}
+	if anomalies == nil {
+		anomalies = []db.ListAnomaliesByUserRow{}
+	}
+
+	respondJSON(w, http.StatusOK, anomalies)
+}
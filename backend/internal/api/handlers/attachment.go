@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/storage"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// allowedAttachmentTypes are the sniffed content types accepted as receipts.
+var allowedAttachmentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+var (
+	errQuotaExceeded   = errors.New("storage quota exceeded")
+	errStoreAttachment = errors.New("store attachment")
+)
+
+type AttachmentHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	storage storage.Storage
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewAttachmentHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, store storage.Storage) *AttachmentHandler {
+	return &AttachmentHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		storage: store,
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+// UploadAttachment stores the multipart "file" field against a transaction.
+// The content type is sniffed from the file itself rather than trusted from
+// the client, and uploads that would exceed the user's quota are rejected.
+func (h *AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	transactionID, err := uuidParam(r, "transactionID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	maxBytes := h.config.Storage.MaxUploadBytes
+
+	_, err = h.queries.GetTransaction(r.Context(), db.GetTransactionParams{ID: transactionID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get transaction", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get transaction")
+		return
+	}
+
+	// Leave room for the multipart framing around the file itself.
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+1024*1024)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "a file must be uploaded in the \"file\" form field")
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "failed to read uploaded file")
+		return
+	}
+	if int64(len(content)) > maxBytes {
+		respondError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("file must be at most %d MB", maxBytes/(1024*1024)))
+		return
+	}
+	if len(content) == 0 {
+		respondError(w, http.StatusBadRequest, "file is empty")
+		return
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(content))
+	ext, ok := allowedAttachmentTypes[contentType]
+	if !ok {
+		respondError(w, http.StatusUnsupportedMediaType, "only JPEG, PNG, GIF, WebP and PDF files are accepted")
+		return
+	}
+
+	id := utils.NewUUID()
+	key := fmt.Sprintf("attachments/%s/%s%s", userID.String(), id.String(), ext)
+	size := int64(len(content))
+	stored := false
+	var attachment db.Attachment
+	// The user's row lock serializes concurrent uploads, so the usage read
+	// here still holds when the new attachment is inserted.
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		if err := qtx.LockUser(r.Context(), userID); err != nil {
+			return fmt.Errorf("lock user: %w", err)
+		}
+		used, err := qtx.SumAttachmentSizeByUser(r.Context(), userID)
+		if err != nil {
+			return fmt.Errorf("read storage usage: %w", err)
+		}
+		if used+size > h.config.Storage.UserQuotaBytes {
+			return errQuotaExceeded
+		}
+
+		if err := h.storage.Put(r.Context(), key, bytes.NewReader(content), size, contentType); err != nil {
+			return fmt.Errorf("%w: %w", errStoreAttachment, err)
+		}
+		stored = true
+
+		attachment, err = qtx.CreateAttachment(r.Context(), db.CreateAttachmentParams{
+			ID:            id,
+			UserID:        userID,
+			TransactionID: transactionID,
+			FileName:      sanitizeFileName(header.Filename, ext),
+			ContentType:   contentType,
+			SizeBytes:     size,
+			StorageKey:    key,
+		})
+		return err
+	})
+	if errors.Is(err, errQuotaExceeded) {
+		respondError(w, http.StatusInsufficientStorage, "storage quota exceeded")
+		return
+	}
+	if err != nil {
+		if stored {
+			if err := h.storage.Delete(r.Context(), key); err != nil {
+				h.logger.Warn("Failed to clean up stored attachment", zap.String("key", key), zap.Error(err))
+			}
+		}
+		if errors.Is(err, errStoreAttachment) {
+			h.logger.Error("Failed to store attachment", zap.Error(err))
+			respondError(w, http.StatusInternalServerError, "failed to store attachment")
+			return
+		}
+		h.logger.Error("Failed to create attachment", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create attachment")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, attachment)
+}
+
+func (h *AttachmentHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	transactionID, err := uuidParam(r, "transactionID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	attachments, err := h.queries.ListAttachmentsByTransaction(r.Context(), db.ListAttachmentsByTransactionParams{
+		TransactionID: transactionID,
+		UserID:        userID,
+	})
+	if err != nil {
+		h.logger.Error("Failed to list attachments", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list attachments")
+		return
+	}
+	if attachments == nil {
+		attachments = []db.Attachment{}
+	}
+
+	respondJSON(w, http.StatusOK, attachments)
+}
+
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	transactionID, err := uuidParam(r, "transactionID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	attachmentID, err := uuidParam(r, "attachmentID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	attachment, err := h.queries.GetAttachment(r.Context(), db.GetAttachmentParams{
+		ID:            attachmentID,
+		TransactionID: transactionID,
+		UserID:        userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "attachment not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get attachment", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get attachment")
+		return
+	}
+
+	body, err := h.storage.Get(r.Context(), attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		respondError(w, http.StatusNotFound, "attachment file is missing")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to read attachment", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to read attachment")
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, body); err != nil {
+		h.logger.Warn("Failed to stream attachment", zap.Error(err))
+	}
+}
+
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	transactionID, err := uuidParam(r, "transactionID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	attachmentID, err := uuidParam(r, "attachmentID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	attachment, err := h.queries.DeleteAttachment(r.Context(), db.DeleteAttachmentParams{
+		ID:            attachmentID,
+		TransactionID: transactionID,
+		UserID:        userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "attachment not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to delete attachment", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete attachment")
+		return
+	}
+	if err := h.storage.Delete(r.Context(), attachment.StorageKey); err != nil {
+		h.logger.Warn("Failed to delete stored attachment", zap.String("key", attachment.StorageKey), zap.Error(err))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sanitizeFileName keeps the base of a client-supplied file name, dropping
+// path components and control characters, and falls back to a generic name.
+func sanitizeFileName(name, ext string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		name = "receipt" + ext
+	}
+	// Keep the end, with the extension, cutting on a character boundary.
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[len(runes)-255:])
+	}
+	return name
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/auth"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyPasswordHash is compared against when no user has the email, so
+// unknown and known emails take as long to reject.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("30budget"), bcrypt.DefaultCost)
+	return hash
+})
+
+// AuthHandler issues access tokens, such as the one the event stream requires.
+type AuthHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewAuthHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *AuthHandler {
+	return &AuthHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type tokenResponse struct {
+	AccessToken string       `json:"access_token"`
+	TokenType   string       `json:"token_type"`
+	ExpiresIn   int          `json:"expires_in"`
+	User        userResponse `json:"user"`
+}
+
+type userResponse struct {
+	ID    pgtype.UUID `json:"id"`
+	Name  string      `json:"name"`
+	Email string      `json:"email"`
+}
+
+// Login checks an email and password and returns an access token for the
+// user that expires after JWT_EXPIRES_IN.
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.Email = strings.TrimSpace(req.Email)
+	if req.Email == "" || req.Password == "" {
+		respondError(w, http.StatusBadRequest, "email and password are required")
+		return
+	}
+
+	user, err := h.queries.GetUserByEmail(r.Context(), req.Email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(req.Password))
+		respondError(w, http.StatusUnauthorized, "invalid email or password")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to log in")
+		return
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		respondError(w, http.StatusUnauthorized, "invalid email or password")
+		return
+	}
+
+	token, err := auth.NewAccessToken(h.config.JWT.Secret, user.ID.String(), h.config.JWT.ExpiresIn)
+	if err != nil {
+		h.logger.Error("Failed to sign access token", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to log in")
+		return
+	}
+	respondJSON(w, http.StatusOK, tokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(h.config.JWT.ExpiresIn.Seconds()),
+		User:        userResponse{ID: user.ID, Name: user.Name, Email: user.Email},
+	})
+}
@@ -0,0 +1,445 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/bill"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultUpcomingDays = 30
+	maxUpcomingDays     = 366
+	defaultRemindDays   = 3
+)
+
+var errUnknownTransaction = errors.New("transaction not found")
+
+type BillHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewBillHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *BillHandler {
+	return &BillHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type billRequest struct {
+	Name       string        `json:"name"`
+	Amount     money.Decimal `json:"amount"`
+	CategoryID pgtype.UUID   `json:"categoryId"`
+	PayeeID    pgtype.UUID   `json:"payeeId"`
+	AccountID  pgtype.UUID   `json:"accountId"`
+	// Frequency defaults to monthly; once makes a one-off bill due on
+	// StartDate.
+	Frequency     db.RecurrenceFrequency `json:"frequency"`
+	IntervalCount int32                  `json:"intervalCount"`
+	StartDate     pgtype.Date            `json:"startDate"`
+	EndDate       pgtype.Date            `json:"endDate"`
+	// RemindDaysBefore defaults to 3; 0 reminds on the due date itself.
+	RemindDaysBefore *int32 `json:"remindDaysBefore"`
+}
+
+type payBillRequest struct {
+	// TransactionID optionally links the transaction that paid the bill.
+	TransactionID pgtype.UUID `json:"transactionId"`
+}
+
+type billPaymentResponse struct {
+	Payment db.BillPayment `json:"payment"`
+	Bill    db.Bill        `json:"bill"`
+}
+
+type upcomingBillsResponse struct {
+	Overdue  []bill.Occurrence `json:"overdue"`
+	Upcoming []bill.Occurrence `json:"upcoming"`
+	// Truncated is set when there were more than bill.MaxOccurrences.
+	Truncated bool `json:"truncated"`
+}
+
+// normalize fills in defaults and validates the request.
+func (req *billRequest) normalize() error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		return errors.New("name is required and must be at most 100 characters")
+	}
+	if !req.Amount.Valid() || req.Amount.Sign() <= 0 {
+		return errors.New("amount must be a positive number")
+	}
+	if !req.Amount.InRange() {
+		return errors.New("amount is too large")
+	}
+	if req.Frequency == "" {
+		req.Frequency = db.RecurrenceFrequencyMonthly
+	}
+	if req.IntervalCount == 0 {
+		req.IntervalCount = 1
+	}
+	if req.RemindDaysBefore == nil {
+		days := int32(defaultRemindDays)
+		req.RemindDaysBefore = &days
+	}
+	if *req.RemindDaysBefore < 0 || *req.RemindDaysBefore > 60 {
+		return errors.New("remindDaysBefore must be between 0 and 60")
+	}
+	return req.rule().Validate()
+}
+
+func (req billRequest) rule() recurrence.Rule {
+	return recurrence.New(req.Frequency, req.IntervalCount, req.StartDate, req.EndDate)
+}
+
+// checkLinks verifies that the bill's optional category, payee and account
+// belong to the user.
+func (h *BillHandler) checkLinks(r *http.Request, userID pgtype.UUID, req billRequest) error {
+	if req.CategoryID.Valid {
+		if _, err := h.queries.GetCategory(r.Context(), db.GetCategoryParams{ID: req.CategoryID, UserID: userID}); err != nil {
+			return errors.New("category not found")
+		}
+	}
+	if req.PayeeID.Valid {
+		if _, err := h.queries.GetPayee(r.Context(), db.GetPayeeParams{ID: req.PayeeID, UserID: userID}); err != nil {
+			return errUnknownPayee
+		}
+	}
+	if req.AccountID.Valid {
+		if _, err := h.queries.GetAccount(r.Context(), db.GetAccountParams{ID: req.AccountID, UserID: userID}); err != nil {
+			return errors.New("account not found")
+		}
+	}
+	return nil
+}
+
+func (h *BillHandler) CreateBill(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req billRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.normalize(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.checkLinks(r, userID, req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	b, err := h.queries.CreateBill(r.Context(), db.CreateBillParams{
+		ID:               utils.NewUUID(),
+		UserID:           userID,
+		Name:             req.Name,
+		Amount:           req.Amount,
+		CategoryID:       req.CategoryID,
+		PayeeID:          req.PayeeID,
+		AccountID:        req.AccountID,
+		Frequency:        req.Frequency,
+		IntervalCount:    req.IntervalCount,
+		StartDate:        req.StartDate,
+		EndDate:          req.EndDate,
+		NextDueDate:      bill.FirstDue(req.rule()),
+		RemindDaysBefore: *req.RemindDaysBefore,
+	})
+	if err != nil {
+		h.logger.Error("Failed to create bill", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create bill")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, b)
+}
+
+func (h *BillHandler) GetBillByID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	billID, err := uuidParam(r, "billID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	b, err := h.queries.GetBill(r.Context(), db.GetBillParams{ID: billID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "bill not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get bill", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get bill")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, b)
+}
+
+func (h *BillHandler) ListBillsByUserID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bills, err := h.queries.ListBillsByUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list bills", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list bills")
+		return
+	}
+	if bills == nil {
+		bills = []db.Bill{}
+	}
+
+	respondJSON(w, http.StatusOK, bills)
+}
+
+// UpdateBill replaces a bill's details. A changed schedule takes effect from
+// the occurrence after the last one paid.
+func (h *BillHandler) UpdateBill(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	billID, err := uuidParam(r, "billID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req billRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.normalize(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.checkLinks(r, userID, req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var b db.Bill
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		b, err = qtx.UpdateBill(r.Context(), db.UpdateBillParams{
+			ID:               billID,
+			UserID:           userID,
+			Name:             req.Name,
+			Amount:           req.Amount,
+			CategoryID:       req.CategoryID,
+			PayeeID:          req.PayeeID,
+			AccountID:        req.AccountID,
+			Frequency:        req.Frequency,
+			IntervalCount:    req.IntervalCount,
+			StartDate:        req.StartDate,
+			EndDate:          req.EndDate,
+			RemindDaysBefore: *req.RemindDaysBefore,
+		})
+		if err != nil {
+			return err
+		}
+		b, err = bill.Refresh(r.Context(), qtx, b)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "bill not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update bill", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update bill")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, b)
+}
+
+func (h *BillHandler) DeleteBill(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	billID, err := uuidParam(r, "billID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.queries.DeleteBill(r.Context(), db.DeleteBillParams{ID: billID, UserID: userID}); err != nil {
+		h.logger.Error("Failed to delete bill", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete bill")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListUpcomingBills lists the user's unpaid bill occurrences: those overdue
+// and those due within the next days days (30 by default), counted in the
+// user's time zone.
+func (h *BillHandler) ListUpcomingBills(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	days := defaultUpcomingDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		days, err = strconv.Atoi(v)
+		if err != nil || days < 0 || days > maxUpcomingDays {
+			respondError(w, http.StatusBadRequest, "days must be an integer between 0 and 366")
+			return
+		}
+	}
+
+	user, err := h.queries.GetUser(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list upcoming bills")
+		return
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	today := recurrence.Day(time.Now().In(loc))
+	until := today.AddDate(0, 0, days)
+
+	bills, err := h.queries.ListOutstandingBills(r.Context(), db.ListOutstandingBillsParams{
+		UserID:    userID,
+		DueBefore: recurrence.Date(until),
+	})
+	if err != nil {
+		h.logger.Error("Failed to list upcoming bills", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list upcoming bills")
+		return
+	}
+
+	resp := upcomingBillsResponse{Overdue: []bill.Occurrence{}, Upcoming: []bill.Occurrence{}}
+	overdue, upcoming, truncated := bill.Upcoming(bills, today, until)
+	resp.Overdue = append(resp.Overdue, overdue...)
+	resp.Upcoming = append(resp.Upcoming, upcoming...)
+	resp.Truncated = truncated
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// PayBill marks the bill's next due occurrence as paid, optionally by one of
+// the user's transactions, and moves the bill on to its next due date.
+func (h *BillHandler) PayBill(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	billID, err := uuidParam(r, "billID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req payBillRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	var resp billPaymentResponse
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		b, err := qtx.GetBill(r.Context(), db.GetBillParams{ID: billID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		if req.TransactionID.Valid {
+			if _, err := qtx.GetTransaction(r.Context(), db.GetTransactionParams{ID: req.TransactionID, UserID: userID}); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return errUnknownTransaction
+				}
+				return err
+			}
+		}
+		resp.Payment, resp.Bill, err = bill.Pay(r.Context(), qtx, b, req.TransactionID)
+		return err
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		respondError(w, http.StatusNotFound, "bill not found")
+		return
+	case errors.Is(err, errUnknownTransaction):
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	case errors.Is(err, bill.ErrNothingDue):
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	case isUniqueViolation(err):
+		respondError(w, http.StatusConflict, "transaction already pays a bill")
+		return
+	case err != nil:
+		h.logger.Error("Failed to pay bill", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to pay bill")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, resp)
+}
+
+// ListBillPayments lists the occurrences of a bill that have been paid,
+// latest first.
+func (h *BillHandler) ListBillPayments(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	billID, err := uuidParam(r, "billID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	payments, err := h.queries.ListBillPayments(r.Context(), db.ListBillPaymentsParams{BillID: billID, UserID: userID})
+	if err != nil {
+		h.logger.Error("Failed to list bill payments", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list bill payments")
+		return
+	}
+	if payments == nil {
+		payments = []db.BillPayment{}
+	}
+
+	respondJSON(w, http.StatusOK, payments)
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/bill"
+	"github.com/nyunja/30budget/backend/internal/budget"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/ical"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+	"go.uber.org/zap"
+)
+
+// feedAlarmHour is the hour of the day on which same-day reminders fire.
+const feedAlarmHour = 9
+
+// CalendarFeedHandler manages each user's secret iCalendar feed URL and
+// serves the feed itself, which calendar apps fetch without credentials.
+type CalendarFeedHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewCalendarFeedHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *CalendarFeedHandler {
+	return &CalendarFeedHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type calendarFeedResponse struct {
+	// URL is only known when the token is issued; afterwards just the hash
+	// is stored.
+	URL            string             `json:"url,omitempty"`
+	CreatedAt      pgtype.Timestamptz `json:"createdAt"`
+	LastAccessedAt pgtype.Timestamptz `json:"lastAccessedAt"`
+}
+
+// newFeedToken returns a random URL-safe token and the hash to store for it.
+func newFeedToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashFeedToken(token), nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (h *CalendarFeedHandler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	feed, err := h.queries.GetCalendarFeed(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "calendar feed not enabled")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get calendar feed", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get calendar feed")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, calendarFeedResponse{CreatedAt: feed.CreatedAt, LastAccessedAt: feed.LastAccessedAt})
+}
+
+// RotateCalendarFeed issues a new feed URL, enabling the feed if needed.
+// Any previous URL stops working.
+func (h *CalendarFeedHandler) RotateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := h.queries.GetUser(r.Context(), userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create calendar feed")
+		return
+	}
+
+	token, hash, err := newFeedToken()
+	if err != nil {
+		h.logger.Error("Failed to generate calendar feed token", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create calendar feed")
+		return
+	}
+	feed, err := h.queries.UpsertCalendarFeed(r.Context(), db.UpsertCalendarFeedParams{UserID: userID, TokenHash: hash})
+	if err != nil {
+		h.logger.Error("Failed to save calendar feed", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create calendar feed")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, calendarFeedResponse{
+		URL:       strings.TrimSuffix(h.config.App.APIURL, "/") + "/api/v1/calendar/" + token + ".ics",
+		CreatedAt: feed.CreatedAt,
+	})
+}
+
+// RevokeCalendarFeed disables the feed; its URL stops working.
+func (h *CalendarFeedHandler) RevokeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := h.queries.DeleteCalendarFeed(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to revoke calendar feed", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to revoke calendar feed")
+		return
+	}
+	if deleted == 0 {
+		respondError(w, http.StatusNotFound, "calendar feed not enabled")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ServeCalendarFeed renders the bills and recurring transactions of the
+// user owning the token in the URL.
+func (h *CalendarFeedHandler) ServeCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.queries.GetCalendarFeedByTokenHash(r.Context(), hashFeedToken(chi.URLParam(r, "token")))
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "calendar feed not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get calendar feed", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get calendar feed")
+		return
+	}
+
+	cal, err := h.calendar(r, feed.UserID)
+	if err != nil {
+		h.logger.Error("Failed to build calendar feed", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to build calendar feed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	w.WriteHeader(http.StatusOK)
+	if err := cal.Write(w); err != nil {
+		h.logger.Warn("Failed to write calendar feed", zap.Error(err))
+	}
+}
+
+func (h *CalendarFeedHandler) calendar(r *http.Request, userID pgtype.UUID) (ical.Calendar, error) {
+	user, err := h.queries.GetUser(r.Context(), userID)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	bills, err := h.queries.ListBillsByUser(r.Context(), userID)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	recurring, err := h.queries.ListRecurringTransactionsByUser(r.Context(), userID)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+
+	cal := ical.Calendar{Name: "30Budget"}
+	amount := func(n money.Decimal) string {
+		return user.CurrencySymbol + budget.FormatAmount(n.Rat())
+	}
+	for _, b := range bills {
+		cal.Events = append(cal.Events, ical.Event{
+			UID:     "bill-" + b.ID.String() + "@30budget",
+			Summary: fmt.Sprintf("%s due (%s)", b.Name, amount(b.Amount)),
+			Rule:    bill.Rule(b),
+			Updated: b.UpdatedAt.Time,
+			Alarm:   &ical.Alarm{Before: time.Duration(b.RemindDaysBefore) * 24 * time.Hour, AtHour: feedAlarmHour},
+		})
+	}
+	for _, rt := range recurring {
+		summary := fmt.Sprintf("%s (%s)", rt.Description, amount(rt.Amount))
+		if rt.Type == db.TransactionTypeIncome {
+			summary = "Payday: " + summary
+		}
+		cal.Events = append(cal.Events, ical.Event{
+			UID:     "recurring-" + rt.ID.String() + "@30budget",
+			Summary: summary,
+			Rule:    recurrence.New(rt.Frequency, rt.IntervalCount, rt.StartDate, rt.EndDate),
+			Updated: rt.UpdatedAt.Time,
+			Alarm:   &ical.Alarm{AtHour: feedAlarmHour},
+		})
+	}
+	return cal, nil
+}
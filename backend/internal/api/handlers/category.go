@@ -1,54 +1,240 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/budget"
 	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/notify"
+	"github.com/nyunja/30budget/backend/internal/utils"
 	"go.uber.org/zap"
 )
 
 type CategoryHandler struct {
-	dbPool *pgxpool.Pool
-	config *config.Config
-	logger *zap.Logger
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	notify  *notify.Dispatcher
+	config  *config.Config
+	logger  *zap.Logger
 }
 
-func NewCategoryHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *CategoryHandler {
+func NewCategoryHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, dispatcher *notify.Dispatcher) *CategoryHandler {
 	return &CategoryHandler{
-		dbPool: dbPool,
-		config: cfg,
-		logger: logger,
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		notify:  dispatcher,
+		config:  cfg,
+		logger:  logger,
 	}
 }
 
+type categoryRequest struct {
+	Name        string             `json:"name"`
+	Color       string             `json:"color"`
+	Type        db.TransactionType `json:"type"`
+	BudgetLimit money.Decimal      `json:"budgetLimit"`
+}
+
+func (req *categoryRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 255 {
+		return errors.New("name must be between 1 and 255 characters")
+	}
+	if req.Color == "" {
+		req.Color = "#CCCCCC"
+	}
+	if !colorPattern.MatchString(req.Color) {
+		return errors.New("color must be a hex color such as #FFAA00")
+	}
+	if req.Type != db.TransactionTypeIncome && req.Type != db.TransactionTypeExpense {
+		return errors.New("type must be income or expense")
+	}
+	if req.BudgetLimit.Valid() && req.BudgetLimit.Sign() < 0 {
+		return errors.New("budgetLimit must not be negative")
+	}
+	if !req.BudgetLimit.InRange() {
+		return errors.New("budgetLimit is too large")
+	}
+	return nil
+}
+
 func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "CreateCategory not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req categoryRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category, err := h.queries.CreateCategory(r.Context(), db.CreateCategoryParams{
+		ID:          utils.NewUUID(),
+		UserID:      userID,
+		Name:        req.Name,
+		Color:       req.Color,
+		Type:        req.Type,
+		BudgetLimit: req.BudgetLimit,
+	})
+	if isUniqueViolation(err) {
+		respondError(w, http.StatusConflict, "a category with this name already exists")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to create category", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create category")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, category)
 }
 
 func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "GetCategoryByID not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	categoryID, err := uuidParam(r, "categoryID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category, err := h.queries.GetCategory(r.Context(), db.GetCategoryParams{ID: categoryID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "category not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get category", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get category")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, category)
 }
 
 func (h *CategoryHandler) ListCategoriesByUserID(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "ListCategoriesByUserID not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	categories, err := h.queries.ListCategoriesByUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list categories", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list categories")
+		return
+	}
+	if categories == nil {
+		categories = []db.Category{}
+	}
+
+	respondJSON(w, http.StatusOK, categories)
 }
 
+// UpdateCategory updates a category and re-checks its budget thresholds for
+// the current period, since a changed limit can cross or uncross them.
 func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "UpdateCategory not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	categoryID, err := uuidParam(r, "categoryID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req categoryRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var category db.Category
+	var status *budget.Status
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		category, err = qtx.UpdateCategory(r.Context(), db.UpdateCategoryParams{
+			ID:          categoryID,
+			UserID:      userID,
+			Name:        req.Name,
+			Color:       req.Color,
+			Type:        req.Type,
+			BudgetLimit: req.BudgetLimit,
+		})
+		if err != nil {
+			return err
+		}
+		loc, err := budget.Location(r.Context(), qtx, userID)
+		if err != nil {
+			return err
+		}
+		status, err = budget.EvaluateCategory(r.Context(), qtx, userID, categoryID, time.Now(), loc, h.config.App.BudgetAlertThresholds)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "category not found")
+		return
+	}
+	if isUniqueViolation(err) {
+		respondError(w, http.StatusConflict, "a category with this name already exists")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update category", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update category")
+		return
+	}
+	if status != nil {
+		publishBudgetStatuses(r.Context(), h.notify, userID, []*budget.Status{status})
+	}
+
+	respondJSON(w, http.StatusOK, category)
 }
 
 func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "DeleteCategory not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	categoryID, err := uuidParam(r, "categoryID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := h.queries.DeleteCategory(r.Context(), db.DeleteCategoryParams{ID: categoryID, UserID: userID})
+	if err != nil {
+		h.logger.Error("Failed to delete category", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete category")
+		return
+	}
+	if deleted == 0 {
+		respondError(w, http.StatusNotFound, "category not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
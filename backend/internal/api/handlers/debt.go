@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/debt"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// DebtHandler serves the user's debts and plans for paying them off.
+type DebtHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewDebtHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *DebtHandler {
+	return &DebtHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type debtRequest struct {
+	Name    string        `json:"name"`
+	Balance money.Decimal `json:"balance"`
+	// Apr is the annual percentage rate, e.g. 18.5; it defaults to 0.
+	Apr money.Decimal `json:"apr"`
+	// MinimumPayment is due every month; it defaults to 0.
+	MinimumPayment money.Decimal `json:"minimumPayment"`
+	DueDay         int32         `json:"dueDay"`
+}
+
+// nonNegative reports whether n is a number of at least zero that fits an
+// amount column.
+func nonNegative(n money.Decimal) bool {
+	return n.Valid() && n.Sign() >= 0 && n.InRange()
+}
+
+func (req *debtRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		return errors.New("name must be between 1 and 100 characters")
+	}
+	if !nonNegative(req.Balance) {
+		return errors.New("balance must be a number from 0 to below 10^16")
+	}
+	if !req.Apr.Valid() {
+		req.Apr = money.New(0, 0)
+	}
+	if !nonNegative(req.Apr) || req.Apr.Rat().Cmp(big.NewRat(1000, 1)) >= 0 {
+		return errors.New("apr must be a percentage from 0 to below 1000")
+	}
+	if !req.MinimumPayment.Valid() {
+		req.MinimumPayment = money.New(0, money.Scale)
+	}
+	if !nonNegative(req.MinimumPayment) {
+		return errors.New("minimumPayment must be a number from 0 to below 10^16")
+	}
+	if req.DueDay < 1 || req.DueDay > 31 {
+		return errors.New("dueDay must be between 1 and 31")
+	}
+	return nil
+}
+
+func (h *DebtHandler) CreateDebt(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req debtRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	d, err := h.queries.CreateDebt(r.Context(), db.CreateDebtParams{
+		ID:             utils.NewUUID(),
+		UserID:         userID,
+		Name:           req.Name,
+		Balance:        req.Balance,
+		Apr:            req.Apr,
+		MinimumPayment: req.MinimumPayment,
+		DueDay:         req.DueDay,
+	})
+	if isUniqueViolation(err) {
+		respondError(w, http.StatusConflict, "a debt with this name already exists")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to create debt", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create debt")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, d)
+}
+
+func (h *DebtHandler) GetDebtByID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	debtID, err := uuidParam(r, "debtID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	d, err := h.queries.GetDebt(r.Context(), db.GetDebtParams{ID: debtID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "debt not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get debt", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get debt")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, d)
+}
+
+func (h *DebtHandler) ListDebtsByUserID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	debts, err := h.queries.ListDebtsByUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list debts", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list debts")
+		return
+	}
+	if debts == nil {
+		debts = []db.Debt{}
+	}
+
+	respondJSON(w, http.StatusOK, debts)
+}
+
+func (h *DebtHandler) UpdateDebt(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	debtID, err := uuidParam(r, "debtID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req debtRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	d, err := h.queries.UpdateDebt(r.Context(), db.UpdateDebtParams{
+		ID:             debtID,
+		UserID:         userID,
+		Name:           req.Name,
+		Balance:        req.Balance,
+		Apr:            req.Apr,
+		MinimumPayment: req.MinimumPayment,
+		DueDay:         req.DueDay,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "debt not found")
+		return
+	}
+	if isUniqueViolation(err) {
+		respondError(w, http.StatusConflict, "a debt with this name already exists")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update debt", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update debt")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, d)
+}
+
+func (h *DebtHandler) DeleteDebt(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	debtID, err := uuidParam(r, "debtID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.queries.DeleteDebt(r.Context(), db.DeleteDebtParams{ID: debtID, UserID: userID}); err != nil {
+		h.logger.Error("Failed to delete debt", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete debt")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PlanPayoff simulates paying every debt off with the minimum payments plus
+// extra_payment each month, putting the extra towards debts in strategy
+// order: avalanche (the default), snowball, or custom following order, a
+// comma-separated list of debt IDs.
+func (h *DebtHandler) PlanPayoff(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	strategy := debt.Strategy(query.Get("strategy"))
+	switch strategy {
+	case "":
+		strategy = debt.Avalanche
+	case debt.Avalanche, debt.Snowball, debt.Custom:
+	default:
+		respondError(w, http.StatusBadRequest, "strategy must be avalanche, snowball or custom")
+		return
+	}
+	extra := money.New(0, money.Scale)
+	if s := query.Get("extra_payment"); s != "" {
+		if extra, err = money.Parse(s); err != nil || !nonNegative(extra) {
+			respondError(w, http.StatusBadRequest, "extra_payment must be a number from 0 to below 10^16")
+			return
+		}
+	}
+	order, err := parseUUIDList(query.Get("order"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if strategy == debt.Custom && len(order) == 0 {
+		respondError(w, http.StatusBadRequest, "order is required for the custom strategy")
+		return
+	}
+
+	user, err := h.queries.GetUser(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to plan payoff")
+		return
+	}
+	if !extra.Fits(user.Currency) {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("extra_payment has more decimal places than %s allows", user.Currency))
+		return
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	debts, err := h.queries.ListDebtsByUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list debts", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to plan payoff")
+		return
+	}
+	known := make(map[pgtype.UUID]bool, len(debts))
+	for _, d := range debts {
+		known[d.ID] = true
+	}
+	for _, id := range order {
+		if !known[id] {
+			respondError(w, http.StatusBadRequest, "order lists a debt that was not found")
+			return
+		}
+	}
+
+	plan, err := debt.Simulate(debts, strategy, order, extra.Rat(), time.Now().In(loc))
+	if errors.Is(err, debt.ErrNeverPaidOff) {
+		respondError(w, http.StatusBadRequest, err.Error()+"; raise the minimum or extra payments")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to plan payoff", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to plan payoff")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, plan)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/auth"
+	"github.com/nyunja/30budget/backend/internal/budget"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/events"
+	"github.com/nyunja/30budget/backend/internal/notify"
+	"go.uber.org/zap"
+)
+
+// keepAliveInterval spaces out comment lines that keep idle streams from
+// being closed by proxies.
+const keepAliveInterval = 25 * time.Second
+
+type EventHandler struct {
+	hub    *events.Hub
+	config *config.Config
+	logger *zap.Logger
+}
+
+func NewEventHandler(cfg *config.Config, logger *zap.Logger, hub *events.Hub) *EventHandler {
+	return &EventHandler{
+		hub:    hub,
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// StreamEvents streams the user's events as Server-Sent Events. Clients that
+// reconnect with Last-Event-ID receive the events they missed; if those are
+// no longer available a "reset" event tells them to refetch instead.
+func (h *EventHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if authUserID, ok := auth.UserID(r.Context()); !ok || authUserID != userID.String() {
+		respondError(w, http.StatusForbidden, "cannot stream another user's events")
+		return
+	}
+	rc := http.NewResponseController(w)
+	// Streams outlive the server's write timeout.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Debug("Could not clear write deadline for event stream", zap.Error(err))
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+	sub, missed, resumed := h.hub.Subscribe(userID.String(), lastEventID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if !resumed {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, e := range missed {
+		writeEvent(w, e)
+	}
+	if err := rc.Flush(); err != nil {
+		h.logger.Warn("Event stream does not support flushing", zap.Error(err))
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.C:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and resumes.
+				return
+			}
+			writeEvent(w, e)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e events.Event) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Data)
+}
+
+// publishBudgetStatuses pushes committed budget evaluations, and the alerts
+// they raised, to the user.
+func publishBudgetStatuses(ctx context.Context, dispatcher *notify.Dispatcher, userID pgtype.UUID, statuses []*budget.Status) {
+	for _, s := range statuses {
+		dispatcher.Publish(ctx, userID, events.TypeBudget, s)
+		if s.Notification != nil {
+			dispatcher.Dispatch(ctx, *s.Notification)
+		}
+	}
+}
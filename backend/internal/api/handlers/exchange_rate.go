@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/fx"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+	"go.uber.org/zap"
+)
+
+// maxRatesImportBytes caps an uploaded rates CSV.
+const maxRatesImportBytes = 5 << 20
+
+// ExchangeRateHandler serves exchange rates and lets operators import them.
+type ExchangeRateHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	rates   *fx.Converter
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewExchangeRateHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, rates *fx.Converter) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		rates:   rates,
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type exchangeRateResponse struct {
+	From string        `json:"from"`
+	To   string        `json:"to"`
+	Date pgtype.Date   `json:"date"`
+	Rate money.Decimal `json:"rate"`
+}
+
+// GetExchangeRate returns what one unit of from bought in to on date, today
+// in UTC by default.
+func (h *ExchangeRateHandler) GetExchangeRate(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, to := strings.ToUpper(query.Get("from")), strings.ToUpper(query.Get("to"))
+	if !fx.ValidCurrency(from) || !fx.ValidCurrency(to) {
+		respondError(w, http.StatusBadRequest, "from and to must be three-letter currency codes")
+		return
+	}
+	day, ok, err := queryDate(r, "date", time.UTC)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !ok {
+		day = time.Now().UTC()
+	}
+
+	rate, err := h.rates.Rate(r.Context(), h.queries, from, to, day)
+	if errors.Is(err, fx.ErrNoRate) {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get exchange rate", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get exchange rate")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, exchangeRateResponse{
+		From: from,
+		To:   to,
+		Date: recurrence.Date(day),
+		Rate: fx.Decimal(rate),
+	})
+}
+
+// ImportExchangeRates stores the rates in a CSV request body with the
+// columns date, base, quote and rate, replacing any already stored for the
+// same pair and date.
+func (h *ExchangeRateHandler) ImportExchangeRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := fx.ReadCSV(http.MaxBytesReader(w, r.Body, maxRatesImportBytes))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid rates CSV: "+err.Error())
+		return
+	}
+
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		return fx.Import(r.Context(), h.queries.WithTx(tx), rates, "import")
+	})
+	if err != nil {
+		h.logger.Error("Failed to import exchange rates", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to import exchange rates")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int{"imported": len(rates)})
+}
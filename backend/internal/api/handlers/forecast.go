@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/bill"
+	"github.com/nyunja/30budget/backend/internal/budget"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/forecast"
+	"github.com/nyunja/30budget/backend/internal/fx"
+	"go.uber.org/zap"
+)
+
+// spendRateDays is how many past days the daily discretionary spend rate is
+// averaged over.
+const spendRateDays = 90
+
+// ForecastHandler projects the user's balances to the end of the current
+// period.
+type ForecastHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	rates   *fx.Converter
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewForecastHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, rates *fx.Converter) *ForecastHandler {
+	return &ForecastHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		rates:   rates,
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type forecastResponse struct {
+	PeriodStart time.Time         `json:"periodStart"`
+	PeriodEnd   time.Time         `json:"periodEnd"`
+	Accounts    []forecast.Series `json:"accounts"`
+	Total       forecast.Series   `json:"total"`
+}
+
+// parseForecastPeriod reads the period query parameter: month (the
+// default), week, or cycle starting on cycle_start_day.
+func parseForecastPeriod(r *http.Request) (func(time.Time, *time.Location) budget.Period, error) {
+	switch r.URL.Query().Get("period") {
+	case "", "month":
+		return budget.MonthOf, nil
+	case "week":
+		return budget.WeekOf, nil
+	case "cycle":
+		startDay := 1
+		if s := r.URL.Query().Get("cycle_start_day"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 || n > 28 {
+				return nil, errors.New("cycle_start_day must be between 1 and 28")
+			}
+			startDay = n
+		}
+		return func(t time.Time, loc *time.Location) budget.Period { return budget.CycleOf(t, loc, startDay) }, nil
+	}
+	return nil, errors.New("period must be month, week or cycle")
+}
+
+// GetForecast projects the end-of-day balance of each open account, and of
+// money recorded against no account, for every remaining day of the
+// period. Projections start from current balances and apply unpaid bills,
+// recurring transactions and the average daily discretionary spend of the
+// last 90 days.
+func (h *ForecastHandler) GetForecast(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	periodOf, err := parseForecastPeriod(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	user, err := h.queries.GetUser(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get forecast")
+		return
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	now := time.Now().In(loc)
+	period := periodOf(now, loc)
+	today := budget.DayOf(now, loc).Start
+	last := period.End.In(loc).AddDate(0, 0, -1)
+
+	accounts, err := h.accounts(r, user, now, today)
+	if errors.Is(err, fx.ErrNoRate) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get account balances", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get forecast")
+		return
+	}
+	bills, err := h.queries.ListBillsByUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list bills", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get forecast")
+		return
+	}
+	overdue, upcoming, _ := bill.Upcoming(bills, today, last)
+	recurring, err := h.queries.ListRecurringTransactionsByUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list recurring transactions", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get forecast")
+		return
+	}
+	in := forecast.Inputs{
+		Today:     today,
+		Last:      last,
+		Bills:     append(overdue, upcoming...),
+		Recurring: recurring,
+	}
+
+	// Money outside any account only gets a projection of its own when
+	// something refers to it, or when the user has no accounts at all.
+	unassigned := accounts[len(accounts)-1]
+	if len(accounts) > 1 && !unassignedInUse(unassigned, in) {
+		accounts = accounts[:len(accounts)-1]
+	}
+
+	series, total := forecast.Project(accounts, in)
+	respondJSON(w, http.StatusOK, forecastResponse{
+		PeriodStart: period.Start,
+		PeriodEnd:   period.End,
+		Accounts:    series,
+		Total:       total,
+	})
+}
+
+// accounts returns the user's open accounts followed by the money recorded
+// against none, each with its current balance and daily spend rate in the
+// user's currency.
+func (h *ForecastHandler) accounts(r *http.Request, user db.User, now, today time.Time) ([]forecast.Account, error) {
+	asOf := pgtype.Timestamptz{Time: now, Valid: true}
+	balances, err := h.queries.ListAccountBalances(r.Context(), db.ListAccountBalancesParams{AsOf: asOf, UserID: user.ID})
+	if err != nil {
+		return nil, err
+	}
+	unassigned, err := h.queries.GetUnassignedBalance(r.Context(), db.GetUnassignedBalanceParams{UserID: user.ID, AsOf: asOf})
+	if err != nil {
+		return nil, err
+	}
+
+	// Average over whole days before today, or since the user signed up if
+	// that is more recent.
+	since := today.AddDate(0, 0, -spendRateDays)
+	if joined := budget.DayOf(user.CreatedAt.Time, today.Location()).Start; user.CreatedAt.Valid && joined.After(since) {
+		since = joined
+	}
+	days := int(today.Sub(since).Round(24*time.Hour).Hours() / 24)
+	rates := map[pgtype.UUID]*big.Rat{}
+	if days > 0 {
+		spend, err := h.queries.ListDiscretionarySpend(r.Context(), db.ListDiscretionarySpendParams{
+			UserID:      user.ID,
+			PeriodStart: pgtype.Timestamptz{Time: since, Valid: true},
+			PeriodEnd:   pgtype.Timestamptz{Time: today, Valid: true},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range spend {
+			rates[s.AccountID] = new(big.Rat).Quo(s.Total.Rat(), new(big.Rat).SetInt64(int64(days)))
+		}
+	}
+	rate := func(id pgtype.UUID) *big.Rat {
+		if r, ok := rates[id]; ok {
+			return r
+		}
+		return new(big.Rat)
+	}
+
+	var accounts []forecast.Account
+	for _, b := range balances {
+		if b.Archived {
+			continue
+		}
+		balance, err := h.rates.Convert(r.Context(), h.queries, b.Balance.Rat(), b.Currency, user.Currency, today)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, forecast.Account{ID: b.ID, Name: b.Name, Balance: balance, DailySpend: rate(b.ID)})
+	}
+	return append(accounts, forecast.Account{Name: "Unassigned", Balance: unassigned.Rat(), DailySpend: rate(pgtype.UUID{})}), nil
+}
+
+// unassignedInUse reports whether any money or schedule is outside an
+// account.
+func unassignedInUse(a forecast.Account, in forecast.Inputs) bool {
+	if a.Balance.Sign() != 0 || a.DailySpend.Sign() != 0 {
+		return true
+	}
+	for _, o := range in.Bills {
+		if !o.AccountID.Valid {
+			return true
+		}
+	}
+	for _, rt := range in.Recurring {
+		if !rt.AccountID.Valid {
+			return true
+		}
+	}
+	return false
+}
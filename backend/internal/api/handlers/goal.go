@@ -0,0 +1,511 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/goal"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/notify"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+var errContributionFromTransaction = errors.New("contribution was made by a transaction; edit or delete the transaction instead")
+
+// GoalHandler serves savings goals and the contributions made towards them.
+type GoalHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	notify  *notify.Dispatcher
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewGoalHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, dispatcher *notify.Dispatcher) *GoalHandler {
+	return &GoalHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		notify:  dispatcher,
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type goalRequest struct {
+	Name         string        `json:"name"`
+	TargetAmount money.Decimal `json:"targetAmount"`
+	TargetDate   pgtype.Date   `json:"targetDate"`
+	// AccountID or CategoryID, but not both, optionally link transactions
+	// to the goal: transactions in the category count towards it, as do
+	// income into the account and, negatively, expenses out of it.
+	AccountID  pgtype.UUID `json:"accountId"`
+	CategoryID pgtype.UUID `json:"categoryId"`
+}
+
+type contributionRequest struct {
+	// Amount moved into the goal; a negative amount withdraws from it.
+	Amount money.Decimal `json:"amount"`
+	// Date defaults to now.
+	Date pgtype.Timestamptz `json:"date"`
+	Note pgtype.Text        `json:"note"`
+}
+
+// goalResponse is a goal with its progress.
+type goalResponse struct {
+	db.Goal
+	Progress goal.Progress `json:"progress"`
+}
+
+type contributionResponse struct {
+	Contribution db.GoalContribution `json:"contribution"`
+	Goal         goalResponse        `json:"goal"`
+}
+
+func (req *goalRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		return errors.New("name must be between 1 and 100 characters")
+	}
+	if !req.TargetAmount.Valid() || req.TargetAmount.Sign() <= 0 {
+		return errors.New("targetAmount must be a positive number")
+	}
+	if !req.TargetAmount.InRange() {
+		return errors.New("targetAmount is too large")
+	}
+	if req.AccountID.Valid && req.CategoryID.Valid {
+		return errors.New("a goal can be linked to an account or a category, not both")
+	}
+	return nil
+}
+
+func (req *contributionRequest) validate() error {
+	if !req.Amount.Valid() || req.Amount.Sign() == 0 {
+		return errors.New("amount must be a non-zero number")
+	}
+	if !req.Amount.InRange() {
+		return errors.New("amount is too large")
+	}
+	if !req.Date.Valid {
+		req.Date = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	}
+	if req.Note.Valid {
+		req.Note.String = strings.TrimSpace(req.Note.String)
+		if len(req.Note.String) > 255 {
+			return errors.New("note must be at most 255 characters")
+		}
+	}
+	return nil
+}
+
+// checkLinks verifies that the goal's optional account and category belong
+// to the user.
+func (h *GoalHandler) checkLinks(r *http.Request, userID pgtype.UUID, req goalRequest) error {
+	if req.AccountID.Valid {
+		if _, err := h.queries.GetAccount(r.Context(), db.GetAccountParams{ID: req.AccountID, UserID: userID}); err != nil {
+			return errors.New("account not found")
+		}
+	}
+	if req.CategoryID.Valid {
+		if _, err := h.queries.GetCategory(r.Context(), db.GetCategoryParams{ID: req.CategoryID, UserID: userID}); err != nil {
+			return errors.New("category not found")
+		}
+	}
+	return nil
+}
+
+// now returns the current time in the user's time zone.
+func (h *GoalHandler) now(ctx context.Context, userID pgtype.UUID) (time.Time, error) {
+	user, err := h.queries.GetUser(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return time.Now().In(loc), nil
+}
+
+// withProgress adds g's progress as of now.
+func (h *GoalHandler) withProgress(ctx context.Context, g db.Goal) (goalResponse, error) {
+	saved, err := h.queries.GetGoalSaved(ctx, g.ID)
+	if err != nil {
+		return goalResponse{}, err
+	}
+	now, err := h.now(ctx, g.UserID)
+	if err != nil {
+		return goalResponse{}, err
+	}
+	return goalResponse{Goal: g, Progress: goal.Evaluate(g, saved.Rat(), now)}, nil
+}
+
+func (h *GoalHandler) respondGoal(w http.ResponseWriter, r *http.Request, status int, g db.Goal) {
+	resp, err := h.withProgress(r.Context(), g)
+	if err != nil {
+		h.logger.Error("Failed to get goal progress", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get goal progress")
+		return
+	}
+
+	respondJSON(w, status, resp)
+}
+
+func (h *GoalHandler) CreateGoal(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req goalRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.checkLinks(r, userID, req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	g, err := h.queries.CreateGoal(r.Context(), db.CreateGoalParams{
+		ID:           utils.NewUUID(),
+		UserID:       userID,
+		Name:         req.Name,
+		TargetAmount: req.TargetAmount,
+		TargetDate:   req.TargetDate,
+		AccountID:    req.AccountID,
+		CategoryID:   req.CategoryID,
+	})
+	if err != nil {
+		h.logger.Error("Failed to create goal", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create goal")
+		return
+	}
+
+	h.respondGoal(w, r, http.StatusCreated, g)
+}
+
+func (h *GoalHandler) GetGoalByID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	goalID, err := uuidParam(r, "goalID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	g, err := h.queries.GetGoal(r.Context(), db.GetGoalParams{ID: goalID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "goal not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get goal", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get goal")
+		return
+	}
+
+	h.respondGoal(w, r, http.StatusOK, g)
+}
+
+// ListGoalsByUserID lists goals with their progress, open goals first.
+func (h *GoalHandler) ListGoalsByUserID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	now, err := h.now(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list goals")
+		return
+	}
+
+	rows, err := h.queries.ListGoalsByUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list goals", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list goals")
+		return
+	}
+	goals := make([]goalResponse, len(rows))
+	for i, row := range rows {
+		g := db.Goal{
+			ID:           row.ID,
+			UserID:       row.UserID,
+			Name:         row.Name,
+			TargetAmount: row.TargetAmount,
+			TargetDate:   row.TargetDate,
+			AccountID:    row.AccountID,
+			CategoryID:   row.CategoryID,
+			AchievedAt:   row.AchievedAt,
+			CreatedAt:    row.CreatedAt,
+			UpdatedAt:    row.UpdatedAt,
+		}
+		goals[i] = goalResponse{Goal: g, Progress: goal.Evaluate(g, row.Saved.Rat(), now)}
+	}
+
+	respondJSON(w, http.StatusOK, goals)
+}
+
+// UpdateGoal changes a goal. A new account or category link applies to
+// transactions saved from now on; earlier contributions stay.
+func (h *GoalHandler) UpdateGoal(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	goalID, err := uuidParam(r, "goalID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req goalRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.checkLinks(r, userID, req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// A new target can reach or un-reach milestones.
+	var g db.Goal
+	var reached *db.Notification
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		g, err = qtx.UpdateGoal(r.Context(), db.UpdateGoalParams{
+			ID:           goalID,
+			UserID:       userID,
+			Name:         req.Name,
+			TargetAmount: req.TargetAmount,
+			TargetDate:   req.TargetDate,
+			AccountID:    req.AccountID,
+			CategoryID:   req.CategoryID,
+		})
+		if err != nil {
+			return err
+		}
+		if reached, err = goal.Refresh(r.Context(), qtx, g); err != nil {
+			return err
+		}
+		g, err = qtx.GetGoal(r.Context(), db.GetGoalParams{ID: goalID, UserID: userID})
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "goal not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update goal", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update goal")
+		return
+	}
+	if reached != nil {
+		h.notify.Dispatch(r.Context(), *reached)
+	}
+
+	h.respondGoal(w, r, http.StatusOK, g)
+}
+
+func (h *GoalHandler) DeleteGoal(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	goalID, err := uuidParam(r, "goalID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.queries.DeleteGoal(r.Context(), db.DeleteGoalParams{ID: goalID, UserID: userID}); err != nil {
+		h.logger.Error("Failed to delete goal", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete goal")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListContributions lists the contributions made towards a goal, newest
+// first.
+func (h *GoalHandler) ListContributions(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	goalID, err := uuidParam(r, "goalID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := h.queries.GetGoal(r.Context(), db.GetGoalParams{ID: goalID, UserID: userID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "goal not found")
+			return
+		}
+		h.logger.Error("Failed to get goal", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list contributions")
+		return
+	}
+
+	contributions, err := h.queries.ListGoalContributions(r.Context(), db.ListGoalContributionsParams{GoalID: goalID, UserID: userID})
+	if err != nil {
+		h.logger.Error("Failed to list contributions", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list contributions")
+		return
+	}
+	if contributions == nil {
+		contributions = []db.GoalContribution{}
+	}
+
+	respondJSON(w, http.StatusOK, contributions)
+}
+
+// CreateContribution records a transfer into (or out of) a goal that is
+// not a transaction.
+func (h *GoalHandler) CreateContribution(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	goalID, err := uuidParam(r, "goalID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req contributionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var contribution db.GoalContribution
+	var g db.Goal
+	var reached *db.Notification
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		g, err = qtx.GetGoal(r.Context(), db.GetGoalParams{ID: goalID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		contribution, reached, err = goal.Contribute(r.Context(), qtx, g, req.Amount, req.Date, pgtype.UUID{}, req.Note)
+		if err != nil {
+			return err
+		}
+		g, err = qtx.GetGoal(r.Context(), db.GetGoalParams{ID: goalID, UserID: userID})
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "goal not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to record contribution", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to record contribution")
+		return
+	}
+	if reached != nil {
+		h.notify.Dispatch(r.Context(), *reached)
+	}
+
+	resp, err := h.withProgress(r.Context(), g)
+	if err != nil {
+		h.logger.Error("Failed to get goal progress", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get goal progress")
+		return
+	}
+	respondJSON(w, http.StatusCreated, contributionResponse{Contribution: contribution, Goal: resp})
+}
+
+// DeleteContribution removes a transfer. Contributions made by transactions
+// go when the transaction is deleted or no longer counts towards the goal.
+func (h *GoalHandler) DeleteContribution(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	goalID, err := uuidParam(r, "goalID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	contributionID, err := uuidParam(r, "contributionID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var reached *db.Notification
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		deleted, err := qtx.DeleteGoalContribution(r.Context(), db.DeleteGoalContributionParams{
+			ID:     contributionID,
+			GoalID: goalID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if deleted.TransactionID.Valid {
+			return errContributionFromTransaction
+		}
+		g, err := qtx.GetGoal(r.Context(), db.GetGoalParams{ID: goalID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		reached, err = goal.Refresh(r.Context(), qtx, g)
+		return err
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		respondError(w, http.StatusNotFound, "contribution not found")
+		return
+	case errors.Is(err, errContributionFromTransaction):
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		h.logger.Error("Failed to delete contribution", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete contribution")
+		return
+	}
+	if reached != nil {
+		h.notify.Dispatch(r.Context(), *reached)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
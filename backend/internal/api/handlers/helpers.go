@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+
+	// uniqueViolation is the Postgres error code for a unique constraint violation.
+	uniqueViolation = "23505"
+)
+
+var colorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// respondJSON writes v as a JSON response with the given status code.
+func respondJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// respondError writes a JSON error body of the form {"error": message}.
+func respondError(w http.ResponseWriter, status int, message string) {
+	respondJSON(w, status, map[string]string{"error": message})
+}
+
+// decodeJSON decodes the request body into v, rejecting unknown fields.
+func decodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// parseUUID parses a textual UUID into its pgtype form.
+func parseUUID(s string) (pgtype.UUID, error) {
+	var id pgtype.UUID
+	if err := id.Scan(s); err != nil || !id.Valid {
+		return pgtype.UUID{}, fmt.Errorf("invalid UUID %q", s)
+	}
+	return id, nil
+}
+
+// uuidParam reads and parses a UUID URL parameter.
+func uuidParam(r *http.Request, name string) (pgtype.UUID, error) {
+	return parseUUID(chi.URLParam(r, name))
+}
+
+// pagination reads the limit and offset query parameters.
+func pagination(r *http.Request) (limit, offset int32, err error) {
+	limit = defaultPageLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return 0, 0, errors.New("limit must be a positive integer")
+		}
+		limit = int32(min(n, maxPageLimit))
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+		offset = int32(n)
+	}
+	return limit, offset, nil
+}
+
+// queryTime reads an optional timestamp query parameter, accepting either
+// RFC 3339 or a plain YYYY-MM-DD date. With endOfDay set, a plain date is
+// read as the last instant of that day so that ranges are inclusive.
+func queryTime(r *http.Request, name string, endOfDay bool) (pgtype.Timestamptz, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return pgtype.Timestamptz{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return pgtype.Timestamptz{Time: t, Valid: true}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		if endOfDay {
+			t = t.Add(24*time.Hour - time.Nanosecond)
+		}
+		return pgtype.Timestamptz{Time: t, Valid: true}, nil
+	}
+	return pgtype.Timestamptz{}, fmt.Errorf("%s must be an RFC 3339 timestamp or YYYY-MM-DD date", name)
+}
+
+// parseUUIDList parses a comma-separated list of UUIDs, ignoring blanks.
+func parseUUIDList(s string) ([]pgtype.UUID, error) {
+	var ids []pgtype.UUID
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		id, err := parseUUID(part)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,504 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/fx"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/networth"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// defaultNetWorthDays is how many days of snapshots are returned when no
+// from_date is given.
+const defaultNetWorthDays = 365
+
+// NetWorthHandler serves the user's net worth and the items, such as
+// property, valued by hand that count towards it.
+type NetWorthHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	rates   *fx.Converter
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewNetWorthHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, rates *fx.Converter) *NetWorthHandler {
+	return &NetWorthHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		rates:   rates,
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type netWorthItemRequest struct {
+	Name string `json:"name"`
+	// Kind defaults to asset.
+	Kind db.NetWorthKind `json:"kind"`
+	// Value and ValuedOn are the item's first valuation, when creating it;
+	// ValuedOn defaults to today.
+	Value    money.Decimal `json:"value"`
+	ValuedOn pgtype.Date   `json:"valuedOn"`
+}
+
+type valuationRequest struct {
+	Value money.Decimal `json:"value"`
+	// ValuedOn defaults to today.
+	ValuedOn pgtype.Date `json:"valuedOn"`
+}
+
+// netWorthItemResponse is an item with its latest valuation up to today.
+type netWorthItemResponse struct {
+	db.NetWorthItem
+	Value    money.Decimal `json:"value"`
+	ValuedOn pgtype.Date   `json:"valuedOn"`
+}
+
+type netWorthResponse struct {
+	Current networth.Statement    `json:"current"`
+	History []db.NetWorthSnapshot `json:"history"`
+}
+
+func (req *netWorthItemRequest) validate(creating bool) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		return errors.New("name must be between 1 and 100 characters")
+	}
+	switch req.Kind {
+	case "":
+		req.Kind = db.NetWorthKindAsset
+	case db.NetWorthKindAsset, db.NetWorthKindLiability:
+	default:
+		return errors.New("kind must be asset or liability")
+	}
+	if creating && !nonNegative(req.Value) {
+		return errors.New("value must be a number from 0 to below 10^16")
+	}
+	return nil
+}
+
+// now returns the current time in the user's time zone.
+func (h *NetWorthHandler) now(ctx context.Context, userID pgtype.UUID) (time.Time, error) {
+	user, err := h.queries.GetUser(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return time.Now().In(loc), nil
+}
+
+// GetNetWorth returns today's net worth with what makes it up, and the
+// daily snapshots from from_date to to_date (the last year by default).
+func (h *NetWorthHandler) GetNetWorth(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	now, err := h.now(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get net worth")
+		return
+	}
+	to, ok, err := queryDate(r, "to_date", time.UTC)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !ok {
+		to = recurrence.Day(now)
+	}
+	from, ok, err := queryDate(r, "from_date", time.UTC)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !ok {
+		from = to.AddDate(0, 0, -defaultNetWorthDays)
+	}
+	if from.After(to) {
+		respondError(w, http.StatusBadRequest, "from_date must not be after to_date")
+		return
+	}
+
+	current, err := networth.Compute(r.Context(), h.queries, h.rates, userID, now, now)
+	if errors.Is(err, fx.ErrNoRate) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to compute net worth", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get net worth")
+		return
+	}
+	history, err := h.queries.ListNetWorthSnapshots(r.Context(), db.ListNetWorthSnapshotsParams{
+		UserID:   userID,
+		FromDate: recurrence.Date(from),
+		ToDate:   recurrence.Date(to),
+	})
+	if err != nil {
+		h.logger.Error("Failed to list net worth snapshots", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get net worth")
+		return
+	}
+	if history == nil {
+		history = []db.NetWorthSnapshot{}
+	}
+
+	respondJSON(w, http.StatusOK, netWorthResponse{Current: current, History: history})
+}
+
+// ListNetWorthItems lists items valued by hand with their latest valuation
+// up to today.
+func (h *NetWorthHandler) ListNetWorthItems(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	now, err := h.now(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list net worth items")
+		return
+	}
+
+	items, err := h.queries.ListNetWorthItemValues(r.Context(), db.ListNetWorthItemValuesParams{AsOf: recurrence.Date(now), UserID: userID})
+	if err != nil {
+		h.logger.Error("Failed to list net worth items", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list net worth items")
+		return
+	}
+	if items == nil {
+		items = []db.ListNetWorthItemValuesRow{}
+	}
+
+	respondJSON(w, http.StatusOK, items)
+}
+
+// CreateNetWorthItem adds an item with its first valuation.
+func (h *NetWorthHandler) CreateNetWorthItem(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req netWorthItemRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(true); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !req.ValuedOn.Valid {
+		now, err := h.now(r.Context(), userID)
+		if err != nil {
+			h.logger.Error("Failed to get user", zap.Error(err))
+			respondError(w, http.StatusInternalServerError, "failed to create net worth item")
+			return
+		}
+		req.ValuedOn = recurrence.Date(now)
+	}
+
+	var resp netWorthItemResponse
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		resp.NetWorthItem, err = qtx.CreateNetWorthItem(r.Context(), db.CreateNetWorthItemParams{
+			ID:     utils.NewUUID(),
+			UserID: userID,
+			Name:   req.Name,
+			Kind:   req.Kind,
+		})
+		if err != nil {
+			return err
+		}
+		v, err := qtx.UpsertNetWorthValuation(r.Context(), db.UpsertNetWorthValuationParams{
+			ID:       utils.NewUUID(),
+			ItemID:   resp.ID,
+			UserID:   userID,
+			Value:    req.Value,
+			ValuedOn: req.ValuedOn,
+		})
+		resp.Value, resp.ValuedOn = v.Value, v.ValuedOn
+		return err
+	})
+	if isUniqueViolation(err) {
+		respondError(w, http.StatusConflict, "an item with this name already exists")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to create net worth item", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create net worth item")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, resp)
+}
+
+func (h *NetWorthHandler) GetNetWorthItem(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	itemID, err := uuidParam(r, "itemID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	item, err := h.queries.GetNetWorthItem(r.Context(), db.GetNetWorthItemParams{ID: itemID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "item not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get net worth item", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get net worth item")
+		return
+	}
+
+	h.respondItem(w, r, http.StatusOK, item)
+}
+
+// respondItem writes item with its latest valuation up to today.
+func (h *NetWorthHandler) respondItem(w http.ResponseWriter, r *http.Request, status int, item db.NetWorthItem) {
+	now, err := h.now(r.Context(), item.UserID)
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get net worth item")
+		return
+	}
+	valuations, err := h.queries.ListNetWorthValuations(r.Context(), db.ListNetWorthValuationsParams{ItemID: item.ID, UserID: item.UserID})
+	if err != nil {
+		h.logger.Error("Failed to list valuations", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get net worth item")
+		return
+	}
+
+	resp := netWorthItemResponse{NetWorthItem: item}
+	today := recurrence.Day(now)
+	for _, v := range valuations {
+		if !v.ValuedOn.Time.After(today) {
+			resp.Value, resp.ValuedOn = v.Value, v.ValuedOn
+			break
+		}
+	}
+	respondJSON(w, status, resp)
+}
+
+// UpdateNetWorthItem renames an item or changes its kind; its value changes
+// by adding a valuation.
+func (h *NetWorthHandler) UpdateNetWorthItem(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	itemID, err := uuidParam(r, "itemID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req netWorthItemRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(false); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	item, err := h.queries.UpdateNetWorthItem(r.Context(), db.UpdateNetWorthItemParams{
+		ID:     itemID,
+		UserID: userID,
+		Name:   req.Name,
+		Kind:   req.Kind,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "item not found")
+		return
+	}
+	if isUniqueViolation(err) {
+		respondError(w, http.StatusConflict, "an item with this name already exists")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update net worth item", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update net worth item")
+		return
+	}
+
+	h.respondItem(w, r, http.StatusOK, item)
+}
+
+func (h *NetWorthHandler) DeleteNetWorthItem(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	itemID, err := uuidParam(r, "itemID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.queries.DeleteNetWorthItem(r.Context(), db.DeleteNetWorthItemParams{ID: itemID, UserID: userID}); err != nil {
+		h.logger.Error("Failed to delete net worth item", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete net worth item")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListValuations lists an item's valuations, latest first.
+func (h *NetWorthHandler) ListValuations(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	itemID, err := uuidParam(r, "itemID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := h.queries.GetNetWorthItem(r.Context(), db.GetNetWorthItemParams{ID: itemID, UserID: userID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "item not found")
+			return
+		}
+		h.logger.Error("Failed to get net worth item", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list valuations")
+		return
+	}
+
+	valuations, err := h.queries.ListNetWorthValuations(r.Context(), db.ListNetWorthValuationsParams{ItemID: itemID, UserID: userID})
+	if err != nil {
+		h.logger.Error("Failed to list valuations", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list valuations")
+		return
+	}
+	if valuations == nil {
+		valuations = []db.NetWorthValuation{}
+	}
+
+	respondJSON(w, http.StatusOK, valuations)
+}
+
+// CreateValuation records what an item is worth from a date on, replacing
+// any valuation on that date.
+func (h *NetWorthHandler) CreateValuation(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	itemID, err := uuidParam(r, "itemID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req valuationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !nonNegative(req.Value) {
+		respondError(w, http.StatusBadRequest, "value must be a number from 0 to below 10^16")
+		return
+	}
+	if _, err := h.queries.GetNetWorthItem(r.Context(), db.GetNetWorthItemParams{ID: itemID, UserID: userID}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "item not found")
+			return
+		}
+		h.logger.Error("Failed to get net worth item", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to record valuation")
+		return
+	}
+	if !req.ValuedOn.Valid {
+		now, err := h.now(r.Context(), userID)
+		if err != nil {
+			h.logger.Error("Failed to get user", zap.Error(err))
+			respondError(w, http.StatusInternalServerError, "failed to record valuation")
+			return
+		}
+		req.ValuedOn = recurrence.Date(now)
+	}
+
+	v, err := h.queries.UpsertNetWorthValuation(r.Context(), db.UpsertNetWorthValuationParams{
+		ID:       utils.NewUUID(),
+		ItemID:   itemID,
+		UserID:   userID,
+		Value:    req.Value,
+		ValuedOn: req.ValuedOn,
+	})
+	if err != nil {
+		h.logger.Error("Failed to record valuation", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to record valuation")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, v)
+}
+
+func (h *NetWorthHandler) DeleteValuation(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	itemID, err := uuidParam(r, "itemID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	valuationID, err := uuidParam(r, "valuationID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.queries.DeleteNetWorthValuation(r.Context(), db.DeleteNetWorthValuationParams{
+		ID:     valuationID,
+		ItemID: itemID,
+		UserID: userID,
+	}); err != nil {
+		h.logger.Error("Failed to delete valuation", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete valuation")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,54 +1,314 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/notify"
 	"go.uber.org/zap"
 )
 
 type NotificationHandler struct {
-	dbPool *pgxpool.Pool
-	config *config.Config
-	logger *zap.Logger
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	notify  *notify.Dispatcher
+	config  *config.Config
+	logger  *zap.Logger
 }
 
-func NewNotificationHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *NotificationHandler {
+func NewNotificationHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, dispatcher *notify.Dispatcher) *NotificationHandler {
 	return &NotificationHandler{
-		dbPool: dbPool,
-		config: cfg,
-		logger: logger,
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		notify:  dispatcher,
+		config:  cfg,
+		logger:  logger,
 	}
 }
 
+type notificationRequest struct {
+	Title   string              `json:"title"`
+	Message string              `json:"message"`
+	Type    db.NotificationType `json:"type"`
+	Date    pgtype.Timestamptz  `json:"date"`
+	IsRead  bool                `json:"isRead"`
+}
+
+type markReadRequest struct {
+	IDs []pgtype.UUID `json:"ids"`
+}
+
+func validNotificationType(t db.NotificationType) bool {
+	switch t {
+	case db.NotificationTypeInfo, db.NotificationTypeWarning, db.NotificationTypeAlert, db.NotificationTypeSuccess:
+		return true
+	}
+	return false
+}
+
+func (req *notificationRequest) validate() error {
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" || len(req.Title) > 255 {
+		return errors.New("title must be between 1 and 255 characters")
+	}
+	if strings.TrimSpace(req.Message) == "" {
+		return errors.New("message is required")
+	}
+	if !validNotificationType(req.Type) {
+		return errors.New("type must be info, warning, alert or success")
+	}
+	return nil
+}
+
 func (h *NotificationHandler) CreateNotification(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "CreateNotification not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req notificationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var notification db.Notification
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		notification, err = notify.Create(r.Context(), h.queries.WithTx(tx), notify.Message{
+			UserID:  userID,
+			Type:    req.Type,
+			Title:   req.Title,
+			Message: req.Message,
+			Date:    req.Date.Time,
+		})
+		return err
+	})
+	if err != nil {
+		h.logger.Error("Failed to create notification", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create notification")
+		return
+	}
+	h.notify.Dispatch(r.Context(), notification)
+
+	respondJSON(w, http.StatusCreated, notification)
 }
 
 func (h *NotificationHandler) GetNotificationByID(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "GetNotificationByID not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	notificationID, err := uuidParam(r, "notificationID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	notification, err := h.queries.GetNotification(r.Context(), db.GetNotificationParams{ID: notificationID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "notification not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get notification", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get notification")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, notification)
 }
 
+// ListNotificationsByUserID lists notifications newest first, optionally
+// filtered by type and is_read.
 func (h *NotificationHandler) ListNotificationsByUserID(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "ListNotificationsByUserID not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, offset, err := pagination(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	var notificationType db.NullNotificationType
+	if v := query.Get("type"); v != "" {
+		notificationType = db.NullNotificationType{NotificationType: db.NotificationType(v), Valid: true}
+		if !validNotificationType(notificationType.NotificationType) {
+			respondError(w, http.StatusBadRequest, "type must be info, warning, alert or success")
+			return
+		}
+	}
+	var isRead pgtype.Bool
+	if v := query.Get("is_read"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "is_read must be true or false")
+			return
+		}
+		isRead = pgtype.Bool{Bool: b, Valid: true}
+	}
+
+	notifications, err := h.queries.ListNotificationsByUser(r.Context(), db.ListNotificationsByUserParams{
+		UserID:    userID,
+		Type:      notificationType,
+		IsRead:    isRead,
+		RowLimit:  limit,
+		RowOffset: offset,
+	})
+	if err != nil {
+		h.logger.Error("Failed to list notifications", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list notifications")
+		return
+	}
+	if notifications == nil {
+		notifications = []db.Notification{}
+	}
+
+	respondJSON(w, http.StatusOK, notifications)
 }
 
 func (h *NotificationHandler) UpdateNotification(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "UpdateNotification not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	notificationID, err := uuidParam(r, "notificationID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req notificationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	notification, err := h.queries.UpdateNotification(r.Context(), db.UpdateNotificationParams{
+		ID:      notificationID,
+		UserID:  userID,
+		Title:   req.Title,
+		Message: req.Message,
+		Type:    req.Type,
+		IsRead:  req.IsRead,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "notification not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update notification", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update notification")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, notification)
 }
 
 func (h *NotificationHandler) DeleteNotification(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "DeleteNotification not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	notificationID, err := uuidParam(r, "notificationID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := h.queries.DeleteNotification(r.Context(), db.DeleteNotificationParams{ID: notificationID, UserID: userID})
+	if err != nil {
+		h.logger.Error("Failed to delete notification", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete notification")
+		return
+	}
+	if deleted == 0 {
+		respondError(w, http.StatusNotFound, "notification not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkNotificationsRead marks the notifications listed in "ids" as read.
+func (h *NotificationHandler) MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req markReadRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.IDs) == 0 {
+		respondError(w, http.StatusBadRequest, "ids must list at least one notification")
+		return
+	}
+
+	updated, err := h.queries.MarkNotificationsRead(r.Context(), db.MarkNotificationsReadParams{
+		UserID:          userID,
+		NotificationIds: req.IDs,
+	})
+	if err != nil {
+		h.logger.Error("Failed to mark notifications read", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to mark notifications read")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int64{"updated": updated})
+}
+
+func (h *NotificationHandler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := h.queries.MarkAllNotificationsRead(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to mark all notifications read", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to mark all notifications read")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int64{"updated": updated})
+}
+
+func (h *NotificationHandler) CountUnreadNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	count, err := h.queries.CountUnreadNotifications(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to count unread notifications", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to count unread notifications")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int64{"unread": count})
 }
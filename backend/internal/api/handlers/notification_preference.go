@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/notify"
+	"go.uber.org/zap"
+)
+
+// NotificationPreferenceHandler manages which channels each notification
+// type is delivered over, quiet hours and digest frequency.
+type NotificationPreferenceHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	notify  *notify.Dispatcher
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewNotificationPreferenceHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, dispatcher *notify.Dispatcher) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		notify:  dispatcher,
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type quietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type notificationPreferencesResponse struct {
+	Timezone        string                     `json:"timezone"`
+	QuietHours      *quietHours                `json:"quietHours"`
+	DigestFrequency db.DigestFrequency         `json:"digestFrequency"`
+	Channels        map[string]map[string]bool `json:"channels"`
+}
+
+// updateNotificationPreferencesRequest is a partial update: omitted fields
+// keep their current value, and "quietHours": null turns quiet hours off.
+type updateNotificationPreferencesRequest struct {
+	Timezone        *string                    `json:"timezone"`
+	QuietHours      json.RawMessage            `json:"quietHours"`
+	DigestFrequency *db.DigestFrequency        `json:"digestFrequency"`
+	Channels        map[string]map[string]bool `json:"channels"`
+}
+
+// GetNotificationPreferences returns the user's notification preferences
+// with defaults filled in.
+func (h *NotificationPreferenceHandler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.queries.GetUser(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get notification preferences")
+		return
+	}
+	prefs, err := notify.LoadPreferences(r.Context(), h.queries, user)
+	if err != nil {
+		h.logger.Error("Failed to load notification preferences", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get notification preferences")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, newNotificationPreferencesResponse(prefs))
+}
+
+// UpdateNotificationPreferences applies a partial update to the user's
+// notification preferences.
+func (h *NotificationPreferenceHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req updateNotificationPreferencesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Timezone != nil {
+		*req.Timezone = strings.TrimSpace(*req.Timezone)
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" {
+			respondError(w, http.StatusBadRequest, "timezone must be an IANA time zone, e.g. Africa/Nairobi")
+			return
+		}
+	}
+	if req.DigestFrequency != nil {
+		switch *req.DigestFrequency {
+		case db.DigestFrequencyNone, db.DigestFrequencyWeekly, db.DigestFrequencyMonthly:
+		default:
+			respondError(w, http.StatusBadRequest, "digestFrequency must be none, weekly or monthly")
+			return
+		}
+	}
+	var start, end pgtype.Time
+	setQuietHours := len(req.QuietHours) > 0
+	if setQuietHours && !bytes.Equal(req.QuietHours, []byte("null")) {
+		var qh quietHours
+		if err := json.Unmarshal(req.QuietHours, &qh); err != nil {
+			respondError(w, http.StatusBadRequest, "quietHours must be an object with start and end")
+			return
+		}
+		if start, err = parseClock(qh.Start); err != nil {
+			respondError(w, http.StatusBadRequest, "quietHours.start "+err.Error())
+			return
+		}
+		if end, err = parseClock(qh.End); err != nil {
+			respondError(w, http.StatusBadRequest, "quietHours.end "+err.Error())
+			return
+		}
+		if start.Microseconds == end.Microseconds {
+			respondError(w, http.StatusBadRequest, "quietHours start and end must differ")
+			return
+		}
+	}
+	var changes []db.UpsertNotificationPreferenceParams
+	for t, channels := range req.Channels {
+		notificationType := db.NotificationType(t)
+		if !validNotificationType(notificationType) {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("unknown notification type %q", t))
+			return
+		}
+		for c, enabled := range channels {
+			channel := db.NotificationChannel(c)
+			if !validNotificationChannel(channel) {
+				respondError(w, http.StatusBadRequest, fmt.Sprintf("unknown channel %q", c))
+				return
+			}
+			if !notify.Supported(notificationType, channel) {
+				respondError(w, http.StatusBadRequest, fmt.Sprintf("%s notifications cannot be sent by %s", t, c))
+				return
+			}
+			changes = append(changes, db.UpsertNotificationPreferenceParams{
+				UserID:  userID,
+				Type:    notificationType,
+				Channel: channel,
+				Enabled: enabled,
+			})
+		}
+	}
+
+	var user db.User
+	var prefs notify.Preferences
+	var rescheduled int64
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		user, err = qtx.GetUser(r.Context(), userID)
+		if err != nil {
+			return err
+		}
+		if req.Timezone != nil {
+			if err := qtx.SetUserTimezone(r.Context(), db.SetUserTimezoneParams{ID: userID, Timezone: *req.Timezone}); err != nil {
+				return err
+			}
+		}
+		if setQuietHours || req.DigestFrequency != nil {
+			current, err := notify.LoadPreferences(r.Context(), qtx, user)
+			if err != nil {
+				return err
+			}
+			settings := db.UpsertNotificationSettingsParams{
+				UserID:          userID,
+				QuietHoursStart: current.QuietHoursStart,
+				QuietHoursEnd:   current.QuietHoursEnd,
+				DigestFrequency: current.DigestFrequency,
+			}
+			if setQuietHours {
+				settings.QuietHoursStart, settings.QuietHoursEnd = start, end
+			}
+			if req.DigestFrequency != nil {
+				settings.DigestFrequency = *req.DigestFrequency
+			}
+			if _, err := qtx.UpsertNotificationSettings(r.Context(), settings); err != nil {
+				return err
+			}
+		}
+		for _, change := range changes {
+			if err := qtx.UpsertNotificationPreference(r.Context(), change); err != nil {
+				return err
+			}
+		}
+		// Email and SMS held for the old quiet hours are checked again
+		// against the new preferences: sent, held anew or cancelled.
+		rescheduled, err = qtx.RescheduleHeldOutboxMessages(r.Context(), userID)
+		if err != nil {
+			return err
+		}
+
+		user, err = qtx.GetUser(r.Context(), userID)
+		if err != nil {
+			return err
+		}
+		prefs, err = notify.LoadPreferences(r.Context(), qtx, user)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update notification preferences", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update notification preferences")
+		return
+	}
+	if rescheduled > 0 {
+		h.notify.Wake()
+	}
+
+	respondJSON(w, http.StatusOK, newNotificationPreferencesResponse(prefs))
+}
+
+func newNotificationPreferencesResponse(prefs notify.Preferences) notificationPreferencesResponse {
+	resp := notificationPreferencesResponse{
+		Timezone:        prefs.Location.String(),
+		DigestFrequency: prefs.DigestFrequency,
+		Channels:        make(map[string]map[string]bool, len(notify.Types)),
+	}
+	if prefs.QuietHoursStart.Valid && prefs.QuietHoursEnd.Valid {
+		resp.QuietHours = &quietHours{Start: formatClock(prefs.QuietHoursStart), End: formatClock(prefs.QuietHoursEnd)}
+	}
+	for _, t := range notify.Types {
+		channels := make(map[string]bool)
+		for _, channel := range notify.Channels {
+			if notify.Supported(t, channel) {
+				channels[string(channel)] = prefs.Enabled(t, channel)
+			}
+		}
+		resp.Channels[string(t)] = channels
+	}
+	return resp
+}
+
+func validNotificationChannel(channel db.NotificationChannel) bool {
+	switch channel {
+	case db.NotificationChannelInApp, db.NotificationChannelEmail, db.NotificationChannelSms:
+		return true
+	}
+	return false
+}
+
+// parseClock parses an "HH:MM" time of day.
+func parseClock(s string) (pgtype.Time, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return pgtype.Time{}, errors.New("must be a time of day in HH:MM format")
+	}
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	return pgtype.Time{Microseconds: clock.Microseconds(), Valid: true}, nil
+}
+
+func formatClock(t pgtype.Time) string {
+	minutes := t.Microseconds / int64(time.Minute/time.Microsecond)
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
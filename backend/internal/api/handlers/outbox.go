@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/notify"
+	"go.uber.org/zap"
+)
+
+// OutboxHandler lets operators inspect notification deliveries and replay
+// the ones that were dead-lettered.
+type OutboxHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	notify  *notify.Dispatcher
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewOutboxHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, dispatcher *notify.Dispatcher) *OutboxHandler {
+	return &OutboxHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		notify:  dispatcher,
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+// outboxMessageResponse shows the payload as JSON rather than base64.
+type outboxMessageResponse struct {
+	db.NotificationOutbox
+	Payload json.RawMessage `json:"payload"`
+}
+
+func newOutboxMessageResponse(m db.NotificationOutbox) outboxMessageResponse {
+	return outboxMessageResponse{NotificationOutbox: m, Payload: m.Payload}
+}
+
+func validOutboxStatus(s db.OutboxStatus) bool {
+	switch s {
+	case db.OutboxStatusPending, db.OutboxStatusSent, db.OutboxStatusDead, db.OutboxStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// ListOutboxMessages lists outbox messages, most recently updated first,
+// optionally filtered by status.
+func (h *OutboxHandler) ListOutboxMessages(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := pagination(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var status db.NullOutboxStatus
+	if v := r.URL.Query().Get("status"); v != "" {
+		status = db.NullOutboxStatus{OutboxStatus: db.OutboxStatus(v), Valid: true}
+		if !validOutboxStatus(status.OutboxStatus) {
+			respondError(w, http.StatusBadRequest, "status must be pending, sent, dead or cancelled")
+			return
+		}
+	}
+
+	messages, err := h.queries.ListOutboxMessages(r.Context(), db.ListOutboxMessagesParams{
+		Status:    status,
+		RowLimit:  limit,
+		RowOffset: offset,
+	})
+	if err != nil {
+		h.logger.Error("Failed to list outbox messages", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list outbox messages")
+		return
+	}
+
+	resp := make([]outboxMessageResponse, 0, len(messages))
+	for _, m := range messages {
+		resp = append(resp, newOutboxMessageResponse(m))
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *OutboxHandler) GetOutboxMessage(w http.ResponseWriter, r *http.Request) {
+	messageID, err := uuidParam(r, "messageID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	message, err := h.queries.GetOutboxMessage(r.Context(), messageID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "outbox message not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get outbox message", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get outbox message")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, newOutboxMessageResponse(message))
+}
+
+// ReplayOutboxMessage returns a dead message to the queue with a fresh set
+// of attempts.
+func (h *OutboxHandler) ReplayOutboxMessage(w http.ResponseWriter, r *http.Request) {
+	messageID, err := uuidParam(r, "messageID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	message, err := h.queries.ReplayOutboxMessage(r.Context(), messageID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		_, err = h.queries.GetOutboxMessage(r.Context(), messageID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			respondError(w, http.StatusNotFound, "outbox message not found")
+			return
+		}
+		if err == nil {
+			respondError(w, http.StatusConflict, "only dead messages can be replayed")
+			return
+		}
+	}
+	if err != nil {
+		h.logger.Error("Failed to replay outbox message", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to replay outbox message")
+		return
+	}
+	h.notify.Wake()
+
+	respondJSON(w, http.StatusOK, newOutboxMessageResponse(message))
+}
+
+// ReplayDeadOutboxMessages returns every dead message to the queue.
+func (h *OutboxHandler) ReplayDeadOutboxMessages(w http.ResponseWriter, r *http.Request) {
+	replayed, err := h.queries.ReplayDeadOutboxMessages(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to replay outbox messages", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to replay outbox messages")
+		return
+	}
+	h.notify.Wake()
+
+	respondJSON(w, http.StatusOK, map[string]int64{"replayed": replayed})
+}
@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/payee"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+var (
+	errAliasTaken   = errors.New("alias already belongs to another payee")
+	errInvalidPayee = errors.New("payee name must contain letters")
+)
+
+type PayeeHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewPayeeHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *PayeeHandler {
+	return &PayeeHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type payeeRequest struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+type payeeResponse struct {
+	db.Payee
+	Aliases []string `json:"aliases"`
+}
+
+type mergePayeesRequest struct {
+	PayeeIDs []pgtype.UUID `json:"payeeIds"`
+}
+
+// addAlias records alias for p, failing with errAliasTaken if it already
+// identifies a different payee.
+func addAlias(ctx context.Context, q *db.Queries, p db.Payee, alias string) error {
+	if alias == "" {
+		return nil
+	}
+	if err := q.AddPayeeAlias(ctx, db.AddPayeeAliasParams{UserID: p.UserID, Alias: alias, PayeeID: p.ID}); err != nil {
+		return err
+	}
+	owner, err := q.GetPayeeByAlias(ctx, db.GetPayeeByAliasParams{UserID: p.UserID, Alias: alias})
+	if err != nil {
+		return err
+	}
+	if owner.ID != p.ID {
+		return errAliasTaken
+	}
+	return nil
+}
+
+// findOrCreatePayee returns the payee a name resolves to, creating it on first use.
+func findOrCreatePayee(ctx context.Context, q *db.Queries, userID pgtype.UUID, name string) (db.Payee, error) {
+	key := payee.Normalize(name)
+	if key == "" {
+		return db.Payee{}, errInvalidPayee
+	}
+	p, err := q.GetPayeeByAlias(ctx, db.GetPayeeByAliasParams{UserID: userID, Alias: key})
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return p, err
+	}
+	p, err = q.CreatePayee(ctx, db.CreatePayeeParams{
+		ID:             utils.NewUUID(),
+		UserID:         userID,
+		Name:           strings.TrimSpace(name),
+		NormalizedName: key,
+	})
+	if err != nil {
+		return db.Payee{}, err
+	}
+	return p, addAlias(ctx, q, p, key)
+}
+
+// matchPayee returns the payee whose aliases match a transaction description, if any.
+func matchPayee(ctx context.Context, q *db.Queries, userID pgtype.UUID, description string) (pgtype.UUID, error) {
+	key := payee.Normalize(description)
+	if key == "" {
+		return pgtype.UUID{}, nil
+	}
+	p, err := q.GetPayeeByAlias(ctx, db.GetPayeeByAliasParams{UserID: userID, Alias: key})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.UUID{}, nil
+	}
+	return p.ID, err
+}
+
+// linkMatchingTransactions attaches the user's unlinked transactions whose
+// descriptions match one of the payee's aliases.
+func linkMatchingTransactions(ctx context.Context, q *db.Queries, p db.Payee) error {
+	aliases, err := q.ListPayeeAliases(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(aliases))
+	for _, a := range aliases {
+		known[a] = true
+	}
+	unlinked, err := q.ListTransactionsWithoutPayee(ctx, p.UserID)
+	if err != nil {
+		return err
+	}
+	for _, t := range unlinked {
+		if !known[payee.Normalize(t.Description.String)] {
+			continue
+		}
+		if err := q.SetTransactionPayee(ctx, db.SetTransactionPayeeParams{ID: t.ID, UserID: p.UserID, PayeeID: p.ID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *PayeeHandler) payeeResponse(ctx context.Context, p db.Payee) (payeeResponse, error) {
+	aliases, err := h.queries.ListPayeeAliases(ctx, p.ID)
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return payeeResponse{Payee: p, Aliases: aliases}, err
+}
+
+func (h *PayeeHandler) respondPayee(w http.ResponseWriter, r *http.Request, status int, p db.Payee) {
+	resp, err := h.payeeResponse(r.Context(), p)
+	if err != nil {
+		h.logger.Error("Failed to list payee aliases", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list payee aliases")
+		return
+	}
+	respondJSON(w, status, resp)
+}
+
+func (h *PayeeHandler) CreatePayee(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req payeeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	key := payee.Normalize(req.Name)
+	if key == "" {
+		respondError(w, http.StatusBadRequest, errInvalidPayee.Error())
+		return
+	}
+
+	var created db.Payee
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		created, err = qtx.CreatePayee(r.Context(), db.CreatePayeeParams{
+			ID:             utils.NewUUID(),
+			UserID:         userID,
+			Name:           strings.TrimSpace(req.Name),
+			NormalizedName: key,
+		})
+		if err != nil {
+			return err
+		}
+		for _, alias := range append([]string{req.Name}, req.Aliases...) {
+			if err := addAlias(r.Context(), qtx, created, payee.Normalize(alias)); err != nil {
+				return err
+			}
+		}
+		return linkMatchingTransactions(r.Context(), qtx, created)
+	})
+	if isUniqueViolation(err) {
+		respondError(w, http.StatusConflict, "a payee with this name already exists")
+		return
+	}
+	if errors.Is(err, errAliasTaken) {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to create payee", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create payee")
+		return
+	}
+
+	h.respondPayee(w, r, http.StatusCreated, created)
+}
+
+func (h *PayeeHandler) GetPayeeByID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	payeeID, err := uuidParam(r, "payeeID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	p, err := h.queries.GetPayee(r.Context(), db.GetPayeeParams{ID: payeeID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "payee not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get payee", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get payee")
+		return
+	}
+
+	h.respondPayee(w, r, http.StatusOK, p)
+}
+
+func (h *PayeeHandler) ListPayeesByUserID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	payees, err := h.queries.ListPayeesByUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list payees", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list payees")
+		return
+	}
+	if payees == nil {
+		payees = []db.Payee{}
+	}
+
+	respondJSON(w, http.StatusOK, payees)
+}
+
+// UpdatePayee renames a payee. The previous name stays on as an alias so
+// existing descriptions keep matching.
+func (h *PayeeHandler) UpdatePayee(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	payeeID, err := uuidParam(r, "payeeID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req payeeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	key := payee.Normalize(req.Name)
+	if key == "" {
+		respondError(w, http.StatusBadRequest, errInvalidPayee.Error())
+		return
+	}
+
+	var updated db.Payee
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		updated, err = qtx.UpdatePayeeName(r.Context(), db.UpdatePayeeNameParams{
+			ID:             payeeID,
+			UserID:         userID,
+			Name:           strings.TrimSpace(req.Name),
+			NormalizedName: key,
+		})
+		if err != nil {
+			return err
+		}
+		for _, alias := range append([]string{req.Name}, req.Aliases...) {
+			if err := addAlias(r.Context(), qtx, updated, payee.Normalize(alias)); err != nil {
+				return err
+			}
+		}
+		return linkMatchingTransactions(r.Context(), qtx, updated)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "payee not found")
+		return
+	}
+	if isUniqueViolation(err) || errors.Is(err, errAliasTaken) {
+		respondError(w, http.StatusConflict, "another payee already uses this name; merge the payees instead")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update payee", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update payee")
+		return
+	}
+
+	h.respondPayee(w, r, http.StatusOK, updated)
+}
+
+func (h *PayeeHandler) DeletePayee(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	payeeID, err := uuidParam(r, "payeeID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := h.queries.DeletePayee(r.Context(), db.DeletePayeeParams{ID: payeeID, UserID: userID})
+	if err != nil {
+		h.logger.Error("Failed to delete payee", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete payee")
+		return
+	}
+	if deleted == 0 {
+		respondError(w, http.StatusNotFound, "payee not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MergePayees folds the listed payees into the payee in the URL: their
+// aliases and transactions move over and the merged payees are deleted.
+func (h *PayeeHandler) MergePayees(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	payeeID, err := uuidParam(r, "payeeID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req mergePayeesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var sources []pgtype.UUID
+	for _, id := range req.PayeeIDs {
+		if id != payeeID {
+			sources = append(sources, id)
+		}
+	}
+	if len(sources) == 0 {
+		respondError(w, http.StatusBadRequest, "payeeIds must list at least one other payee")
+		return
+	}
+
+	var target db.Payee
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		target, err = qtx.GetPayee(r.Context(), db.GetPayeeParams{ID: payeeID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		for _, id := range sources {
+			if _, err := qtx.GetPayee(r.Context(), db.GetPayeeParams{ID: id, UserID: userID}); err != nil {
+				return err
+			}
+		}
+		if err := qtx.MovePayeeAliases(r.Context(), db.MovePayeeAliasesParams{TargetID: payeeID, UserID: userID, SourceIds: sources}); err != nil {
+			return err
+		}
+		if _, err := qtx.MovePayeeTransactions(r.Context(), db.MovePayeeTransactionsParams{TargetID: payeeID, UserID: userID, SourceIds: sources}); err != nil {
+			return err
+		}
+		return qtx.DeletePayees(r.Context(), db.DeletePayeesParams{UserID: userID, PayeeIds: sources})
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "payee not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to merge payees", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to merge payees")
+		return
+	}
+
+	h.respondPayee(w, r, http.StatusOK, target)
+}
+
+// SpendingByPayee returns expense totals per payee bucketed by interval
+// (day, week, month or year) over an optional date range and payee.
+func (h *PayeeHandler) SpendingByPayee(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	interval := query.Get("interval")
+	switch interval {
+	case "":
+		interval = "month"
+	case "day", "week", "month", "year":
+	default:
+		respondError(w, http.StatusBadRequest, "interval must be day, week, month or year")
+		return
+	}
+	var payeeID pgtype.UUID
+	if v := query.Get("payee_id"); v != "" {
+		if payeeID, err = parseUUID(v); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	fromDate, err := queryTime(r, "from_date", false)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	toDate, err := queryTime(r, "to_date", true)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := h.queries.SummarizePayeeSpending(r.Context(), db.SummarizePayeeSpendingParams{
+		Bucket:   interval,
+		UserID:   userID,
+		PayeeID:  payeeID,
+		FromDate: fromDate,
+		ToDate:   toDate,
+	})
+	if err != nil {
+		h.logger.Error("Failed to summarize payee spending", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to summarize payee spending")
+		return
+	}
+	if rows == nil {
+		rows = []db.SummarizePayeeSpendingRow{}
+	}
+
+	respondJSON(w, http.StatusOK, rows)
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+type RecurringTransactionHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewRecurringTransactionHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *RecurringTransactionHandler {
+	return &RecurringTransactionHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type recurringTransactionRequest struct {
+	Description string             `json:"description"`
+	Amount      money.Decimal      `json:"amount"`
+	Type        db.TransactionType `json:"type"`
+	CategoryID  pgtype.UUID        `json:"categoryId"`
+	PayeeID     pgtype.UUID        `json:"payeeId"`
+	AccountID   pgtype.UUID        `json:"accountId"`
+	// Frequency defaults to monthly, repeating every IntervalCount periods.
+	Frequency     db.RecurrenceFrequency `json:"frequency"`
+	IntervalCount int32                  `json:"intervalCount"`
+	StartDate     pgtype.Date            `json:"startDate"`
+	EndDate       pgtype.Date            `json:"endDate"`
+}
+
+// normalize fills in defaults and validates the request.
+func (req *recurringTransactionRequest) normalize() error {
+	req.Description = strings.TrimSpace(req.Description)
+	if req.Description == "" || utf8.RuneCountInString(req.Description) > 255 {
+		return errors.New("description is required and must be at most 255 characters")
+	}
+	if !req.Amount.Valid() || req.Amount.Sign() <= 0 {
+		return errors.New("amount must be a positive number")
+	}
+	if !req.Amount.InRange() {
+		return errors.New("amount is too large")
+	}
+	if req.Type != db.TransactionTypeIncome && req.Type != db.TransactionTypeExpense {
+		return errors.New("type must be income or expense")
+	}
+	if req.Frequency == "" {
+		req.Frequency = db.RecurrenceFrequencyMonthly
+	}
+	if req.IntervalCount == 0 {
+		req.IntervalCount = 1
+	}
+	return recurrence.New(req.Frequency, req.IntervalCount, req.StartDate, req.EndDate).Validate()
+}
+
+// checkLinks verifies that the optional category, payee and account
+// belong to the user.
+func (h *RecurringTransactionHandler) checkLinks(r *http.Request, userID pgtype.UUID, req recurringTransactionRequest) error {
+	if req.CategoryID.Valid {
+		if _, err := h.queries.GetCategory(r.Context(), db.GetCategoryParams{ID: req.CategoryID, UserID: userID}); err != nil {
+			return errors.New("category not found")
+		}
+	}
+	if req.PayeeID.Valid {
+		if _, err := h.queries.GetPayee(r.Context(), db.GetPayeeParams{ID: req.PayeeID, UserID: userID}); err != nil {
+			return errUnknownPayee
+		}
+	}
+	if req.AccountID.Valid {
+		if _, err := h.queries.GetAccount(r.Context(), db.GetAccountParams{ID: req.AccountID, UserID: userID}); err != nil {
+			return errors.New("account not found")
+		}
+	}
+	return nil
+}
+
+func (h *RecurringTransactionHandler) CreateRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req recurringTransactionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.normalize(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.checkLinks(r, userID, req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rt, err := h.queries.CreateRecurringTransaction(r.Context(), db.CreateRecurringTransactionParams{
+		ID:            utils.NewUUID(),
+		UserID:        userID,
+		Description:   req.Description,
+		Amount:        req.Amount,
+		Type:          req.Type,
+		CategoryID:    req.CategoryID,
+		PayeeID:       req.PayeeID,
+		AccountID:     req.AccountID,
+		Frequency:     req.Frequency,
+		IntervalCount: req.IntervalCount,
+		StartDate:     req.StartDate,
+		EndDate:       req.EndDate,
+	})
+	if err != nil {
+		h.logger.Error("Failed to create recurring transaction", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create recurring transaction")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, rt)
+}
+
+func (h *RecurringTransactionHandler) GetRecurringTransactionByID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	recurringID, err := uuidParam(r, "recurringID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rt, err := h.queries.GetRecurringTransaction(r.Context(), db.GetRecurringTransactionParams{ID: recurringID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "recurring transaction not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get recurring transaction", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get recurring transaction")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rt)
+}
+
+func (h *RecurringTransactionHandler) ListRecurringTransactionsByUserID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rts, err := h.queries.ListRecurringTransactionsByUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list recurring transactions", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list recurring transactions")
+		return
+	}
+	if rts == nil {
+		rts = []db.RecurringTransaction{}
+	}
+
+	respondJSON(w, http.StatusOK, rts)
+}
+
+func (h *RecurringTransactionHandler) UpdateRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	recurringID, err := uuidParam(r, "recurringID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req recurringTransactionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.normalize(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.checkLinks(r, userID, req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rt, err := h.queries.UpdateRecurringTransaction(r.Context(), db.UpdateRecurringTransactionParams{
+		ID:            recurringID,
+		UserID:        userID,
+		Description:   req.Description,
+		Amount:        req.Amount,
+		Type:          req.Type,
+		CategoryID:    req.CategoryID,
+		PayeeID:       req.PayeeID,
+		AccountID:     req.AccountID,
+		Frequency:     req.Frequency,
+		IntervalCount: req.IntervalCount,
+		StartDate:     req.StartDate,
+		EndDate:       req.EndDate,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "recurring transaction not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update recurring transaction", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update recurring transaction")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rt)
+}
+
+func (h *RecurringTransactionHandler) DeleteRecurringTransaction(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	recurringID, err := uuidParam(r, "recurringID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.queries.DeleteRecurringTransaction(r.Context(), db.DeleteRecurringTransactionParams{ID: recurringID, UserID: userID}); err != nil {
+		h.logger.Error("Failed to delete recurring transaction", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete recurring transaction")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
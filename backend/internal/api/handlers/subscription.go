@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/subscription"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+var errSubscriptionDecided = errors.New("subscription already confirmed or dismissed")
+
+// SubscriptionHandler serves the subscriptions detected in a user's
+// expenses and lets the user confirm or dismiss them.
+type SubscriptionHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewSubscriptionHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type confirmSubscriptionRequest struct {
+	// Description names the recurring transaction; it defaults to the
+	// payee's name.
+	Description string `json:"description"`
+}
+
+type subscriptionConfirmation struct {
+	Subscription         db.DetectedSubscription `json:"subscription"`
+	RecurringTransaction db.RecurringTransaction `json:"recurringTransaction"`
+}
+
+func (h *SubscriptionHandler) listSubscriptions(w http.ResponseWriter, r *http.Request, userID pgtype.UUID, status db.NullSubscriptionStatus) {
+	subscriptions, err := h.queries.ListDetectedSubscriptions(r.Context(), db.ListDetectedSubscriptionsParams{UserID: userID, Status: status})
+	if err != nil {
+		h.logger.Error("Failed to list subscriptions", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list subscriptions")
+		return
+	}
+	if subscriptions == nil {
+		subscriptions = []db.ListDetectedSubscriptionsRow{}
+	}
+
+	respondJSON(w, http.StatusOK, subscriptions)
+}
+
+// ListSubscriptionsByUserID lists detected subscriptions, optionally only
+// those with the given status.
+func (h *SubscriptionHandler) ListSubscriptionsByUserID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var status db.NullSubscriptionStatus
+	if v := r.URL.Query().Get("status"); v != "" {
+		status = db.NullSubscriptionStatus{SubscriptionStatus: db.SubscriptionStatus(v), Valid: true}
+		switch status.SubscriptionStatus {
+		case db.SubscriptionStatusPending, db.SubscriptionStatusConfirmed, db.SubscriptionStatusDismissed:
+		default:
+			respondError(w, http.StatusBadRequest, "status must be pending, confirmed or dismissed")
+			return
+		}
+	}
+
+	h.listSubscriptions(w, r, userID, status)
+}
+
+// ScanSubscriptions rescans the user's expenses now rather than waiting for
+// the background job, returning the pending subscriptions.
+func (h *SubscriptionHandler) ScanSubscriptions(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		return subscription.Scan(r.Context(), h.queries.WithTx(tx), userID, time.Now())
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to scan for subscriptions", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to scan for subscriptions")
+		return
+	}
+
+	h.listSubscriptions(w, r, userID, db.NullSubscriptionStatus{SubscriptionStatus: db.SubscriptionStatusPending, Valid: true})
+}
+
+// ConfirmSubscription turns a pending subscription into a monthly, weekly
+// or yearly recurring expense starting on its next expected date.
+func (h *SubscriptionHandler) ConfirmSubscription(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	subscriptionID, err := uuidParam(r, "subscriptionID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req confirmSubscriptionRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r, &req); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	req.Description = strings.TrimSpace(req.Description)
+	if utf8.RuneCountInString(req.Description) > 255 {
+		respondError(w, http.StatusBadRequest, "description must be at most 255 characters")
+		return
+	}
+
+	var resp subscriptionConfirmation
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		s, err := qtx.GetDetectedSubscription(r.Context(), db.GetDetectedSubscriptionParams{ID: subscriptionID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		if s.Status != db.SubscriptionStatusPending {
+			return errSubscriptionDecided
+		}
+		if req.Description == "" {
+			payee, err := qtx.GetPayee(r.Context(), db.GetPayeeParams{ID: s.PayeeID, UserID: userID})
+			if err != nil {
+				return err
+			}
+			req.Description = payee.Name
+		}
+		resp.RecurringTransaction, err = qtx.CreateRecurringTransaction(r.Context(), db.CreateRecurringTransactionParams{
+			ID:            utils.NewUUID(),
+			UserID:        userID,
+			Description:   req.Description,
+			Amount:        s.Amount,
+			Type:          db.TransactionTypeExpense,
+			CategoryID:    s.CategoryID,
+			PayeeID:       s.PayeeID,
+			Frequency:     s.Frequency,
+			IntervalCount: 1,
+			StartDate:     s.NextExpectedDate,
+			AccountID:     s.AccountID,
+		})
+		if err != nil {
+			return err
+		}
+		resp.Subscription, err = qtx.SetDetectedSubscriptionStatus(r.Context(), db.SetDetectedSubscriptionStatusParams{
+			ID:                     s.ID,
+			UserID:                 userID,
+			Status:                 db.SubscriptionStatusConfirmed,
+			RecurringTransactionID: resp.RecurringTransaction.ID,
+		})
+		return err
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		respondError(w, http.StatusNotFound, "subscription not found")
+		return
+	case errors.Is(err, errSubscriptionDecided):
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		h.logger.Error("Failed to confirm subscription", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to confirm subscription")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, resp)
+}
+
+// DismissSubscription hides a pending subscription; later scans do not
+// propose it again.
+func (h *SubscriptionHandler) DismissSubscription(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	subscriptionID, err := uuidParam(r, "subscriptionID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var s db.DetectedSubscription
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		s, err = qtx.GetDetectedSubscription(r.Context(), db.GetDetectedSubscriptionParams{ID: subscriptionID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		if s.Status != db.SubscriptionStatusPending {
+			return errSubscriptionDecided
+		}
+		s, err = qtx.SetDetectedSubscriptionStatus(r.Context(), db.SetDetectedSubscriptionStatusParams{
+			ID:     s.ID,
+			UserID: userID,
+			Status: db.SubscriptionStatusDismissed,
+		})
+		return err
+	})
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		respondError(w, http.StatusNotFound, "subscription not found")
+		return
+	case errors.Is(err, errSubscriptionDecided):
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		h.logger.Error("Failed to dismiss subscription", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to dismiss subscription")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, s)
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/budget"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"go.uber.org/zap"
+)
+
+// SummaryHandler reports income, expenses and budget use over a period.
+type SummaryHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewSummaryHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *SummaryHandler {
+	return &SummaryHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+// summaryPeriod is a reporting period and how many months of the monthly
+// category budgets it plans for, as a fraction.
+type summaryPeriod struct {
+	budget.Period
+	planNumerator   int32
+	planDenominator int32
+	// of returns the period of the same kind containing a time.
+	of func(time.Time, *time.Location) budget.Period
+}
+
+// previous is the period of the same kind just before p.
+func (p summaryPeriod) previous(loc *time.Location) budget.Period {
+	return p.of(p.Start.Add(-time.Nanosecond), loc)
+}
+
+type summaryResponse struct {
+	Period                string                      `json:"period"`
+	Start                 time.Time                   `json:"start"`
+	End                   time.Time                   `json:"end"`
+	Income                money.Decimal               `json:"income"`
+	Expenses              money.Decimal               `json:"expenses"`
+	Net                   money.Decimal               `json:"net"`
+	SavingsRate           money.Decimal               `json:"savingsRate"`
+	UncategorizedExpenses money.Decimal               `json:"uncategorizedExpenses"`
+	TransactionCount      int64                       `json:"transactionCount"`
+	Categories            []db.ListCategorySummaryRow `json:"categories"`
+}
+
+// parseSummaryPeriod reads period as month, week or year (the current one
+// at now in loc), a YYYY-MM month or a YYYY year. Weeks plan for 12/52 of
+// a month's budget and years for 12 months.
+func parseSummaryPeriod(period string, now time.Time, loc *time.Location) (summaryPeriod, error) {
+	month := func(t time.Time) summaryPeriod { return summaryPeriod{budget.MonthOf(t, loc), 1, 1, budget.MonthOf} }
+	year := func(t time.Time) summaryPeriod { return summaryPeriod{budget.YearOf(t, loc), 12, 1, budget.YearOf} }
+	switch period {
+	case "", "month":
+		return month(now), nil
+	case "week":
+		return summaryPeriod{budget.WeekOf(now, loc), 12, 52, budget.WeekOf}, nil
+	case "year":
+		return year(now), nil
+	}
+	if t, err := time.ParseInLocation("2006-01", period, loc); err == nil {
+		return month(t), nil
+	}
+	if t, err := time.ParseInLocation("2006", period, loc); err == nil {
+		return year(t), nil
+	}
+	return summaryPeriod{}, errors.New("period must be month, week, year, YYYY-MM or YYYY")
+}
+
+// GetSummary returns total income and expenses, net, savings rate and each
+// category's planned, actual and remaining amounts for a period in the
+// user's time zone. All arithmetic is done on exact NUMERIC values in SQL.
+func (h *SummaryHandler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	user, err := h.queries.GetUser(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get summary")
+		return
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	name := r.URL.Query().Get("period")
+	period, err := parseSummaryPeriod(name, time.Now(), loc)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if name == "" {
+		name = "month"
+	}
+	start := pgtype.Timestamptz{Time: period.Start, Valid: true}
+	end := pgtype.Timestamptz{Time: period.End, Valid: true}
+
+	totals, err := h.queries.GetSummaryTotals(r.Context(), db.GetSummaryTotalsParams{
+		UserID:      userID,
+		PeriodStart: start,
+		PeriodEnd:   end,
+	})
+	if err != nil {
+		h.logger.Error("Failed to get summary totals", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get summary")
+		return
+	}
+	categories, err := h.queries.ListCategorySummary(r.Context(), db.ListCategorySummaryParams{
+		PlanNumerator:   period.planNumerator,
+		PlanDenominator: period.planDenominator,
+		PeriodStart:     start,
+		PeriodEnd:       end,
+		UserID:          userID,
+	})
+	if err != nil {
+		h.logger.Error("Failed to list category summary", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get summary")
+		return
+	}
+	if categories == nil {
+		categories = []db.ListCategorySummaryRow{}
+	}
+
+	respondJSON(w, http.StatusOK, summaryResponse{
+		Period:                name,
+		Start:                 period.Start,
+		End:                   period.End,
+		Income:                totals.Income,
+		Expenses:              totals.Expenses,
+		Net:                   totals.Net,
+		SavingsRate:           totals.SavingsRate,
+		UncategorizedExpenses: totals.UncategorizedExpenses,
+		TransactionCount:      totals.TransactionCount,
+		Categories:            categories,
+	})
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+type TagHandler struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewTagHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *TagHandler {
+	return &TagHandler{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+type tagRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func (req *tagRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 50 {
+		return errors.New("name must be between 1 and 50 characters")
+	}
+	if req.Color == "" {
+		req.Color = "#CCCCCC"
+	}
+	if !colorPattern.MatchString(req.Color) {
+		return errors.New("color must be a hex color such as #FFAA00")
+	}
+	return nil
+}
+
+func (h *TagHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req tagRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tag, err := h.queries.CreateTag(r.Context(), db.CreateTagParams{
+		ID:     utils.NewUUID(),
+		UserID: userID,
+		Name:   req.Name,
+		Color:  req.Color,
+	})
+	if isUniqueViolation(err) {
+		respondError(w, http.StatusConflict, "a tag with this name already exists")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to create tag", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create tag")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, tag)
+}
+
+func (h *TagHandler) GetTagByID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tagID, err := uuidParam(r, "tagID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tag, err := h.queries.GetTag(r.Context(), db.GetTagParams{ID: tagID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "tag not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get tag", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get tag")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tag)
+}
+
+func (h *TagHandler) ListTagsByUserID(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tags, err := h.queries.ListTagsByUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list tags", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list tags")
+		return
+	}
+	if tags == nil {
+		tags = []db.Tag{}
+	}
+
+	respondJSON(w, http.StatusOK, tags)
+}
+
+func (h *TagHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tagID, err := uuidParam(r, "tagID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req tagRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tag, err := h.queries.UpdateTag(r.Context(), db.UpdateTagParams{
+		ID:     tagID,
+		UserID: userID,
+		Name:   req.Name,
+		Color:  req.Color,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "tag not found")
+		return
+	}
+	if isUniqueViolation(err) {
+		respondError(w, http.StatusConflict, "a tag with this name already exists")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update tag", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update tag")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tag)
+}
+
+func (h *TagHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tagID, err := uuidParam(r, "tagID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deleted, err := h.queries.DeleteTag(r.Context(), db.DeleteTagParams{ID: tagID, UserID: userID})
+	if err != nil {
+		h.logger.Error("Failed to delete tag", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete tag")
+		return
+	}
+	if deleted == 0 {
+		respondError(w, http.StatusNotFound, "tag not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SpendingByTag returns income and expense totals per tag over an optional
+// from_date/to_date range. Tags with no transactions in range report zero.
+func (h *TagHandler) SpendingByTag(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fromDate, err := queryTime(r, "from_date", false)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	toDate, err := queryTime(r, "to_date", true)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	totals, err := h.queries.SummarizeTagSpending(r.Context(), db.SummarizeTagSpendingParams{
+		FromDate: fromDate,
+		ToDate:   toDate,
+		UserID:   userID,
+	})
+	if err != nil {
+		h.logger.Error("Failed to summarize tag spending", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to summarize tag spending")
+		return
+	}
+	if totals == nil {
+		totals = []db.SummarizeTagSpendingRow{}
+	}
+
+	respondJSON(w, http.StatusOK, totals)
+}
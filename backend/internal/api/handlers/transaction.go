@@ -1,54 +1,1056 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/anomaly"
+	"github.com/nyunja/30budget/backend/internal/bill"
+	"github.com/nyunja/30budget/backend/internal/budget"
+	"github.com/nyunja/30budget/backend/internal/categorizer"
 	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/fx"
+	"github.com/nyunja/30budget/backend/internal/goal"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/notify"
+	"github.com/nyunja/30budget/backend/internal/quickentry"
+	"github.com/nyunja/30budget/backend/internal/storage"
+	"github.com/nyunja/30budget/backend/internal/utils"
 	"go.uber.org/zap"
 )
 
 type TransactionHandler struct {
-	dbPool *pgxpool.Pool
-	config *config.Config
-	logger *zap.Logger
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	storage storage.Storage
+	notify  *notify.Dispatcher
+	rates   *fx.Converter
+	config  *config.Config
+	logger  *zap.Logger
 }
 
-func NewTransactionHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *TransactionHandler {
+func NewTransactionHandler(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, store storage.Storage, dispatcher *notify.Dispatcher, rates *fx.Converter) *TransactionHandler {
 	return &TransactionHandler{
-		dbPool: dbPool,
-		config: cfg,
-		logger: logger,
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		storage: store,
+		notify:  dispatcher,
+		rates:   rates,
+		config:  cfg,
+		logger:  logger,
 	}
 }
 
+type transactionRequest struct {
+	Amount      money.Decimal      `json:"amount"`
+	Description pgtype.Text        `json:"description"`
+	CategoryID  pgtype.UUID        `json:"categoryId"`
+	Date        pgtype.Timestamptz `json:"date"`
+	Type        db.TransactionType `json:"type"`
+	// PayeeID links the transaction to an existing payee. Otherwise PayeeName
+	// finds or creates one, and failing both the description is matched
+	// against known payee aliases.
+	PayeeID   pgtype.UUID `json:"payeeId"`
+	PayeeName string      `json:"payeeName"`
+	// AccountID is the optional account the money moved in or out of.
+	AccountID pgtype.UUID `json:"accountId"`
+	// Currency is the amount's currency, defaulting to the account's or else
+	// the user's. It must match the account's.
+	Currency string `json:"currency"`
+	// TagIDs replaces the transaction's tags. On update, omitting it leaves
+	// the tags unchanged while an empty list clears them.
+	TagIDs []pgtype.UUID `json:"tagIds"`
+}
+
+type transactionTag struct {
+	ID    pgtype.UUID `json:"id"`
+	Name  string      `json:"name"`
+	Color string      `json:"color"`
+}
+
+// transactionResponse is a transaction with its tags plus, for uncategorized
+// transactions, the categories the user's model would suggest for it.
+type transactionResponse struct {
+	db.Transaction
+	Tags        []transactionTag         `json:"tags"`
+	Suggestions []categorizer.Suggestion `json:"suggestions,omitempty"`
+}
+
+var (
+	errUnknownTag       = errors.New("one or more tags not found")
+	errUnknownPayee     = errors.New("payee not found")
+	errCurrencyMismatch = errors.New("currency must match the account's currency")
+	errAmountPrecision  = errors.New("amount has more decimal places than its currency allows")
+	errAmountRange      = errors.New("amount is too large once converted to the user's currency")
+	errCategoryNotFound = errors.New("category not found")
+	errAccountNotFound  = errors.New("account not found")
+)
+
+// maxImportTransactions bounds how many transactions one import may hold.
+const maxImportTransactions = 500
+
+func (req transactionRequest) validate() error {
+	if !req.Amount.Valid() || req.Amount.Sign() <= 0 {
+		return errors.New("amount must be a positive number")
+	}
+	if !req.Amount.InRange() {
+		return errors.New("amount is too large")
+	}
+	if !req.Date.Valid {
+		return errors.New("date is required")
+	}
+	if req.Type != db.TransactionTypeIncome && req.Type != db.TransactionTypeExpense {
+		return errors.New("type must be income or expense")
+	}
+	if utf8.RuneCountInString(req.Description.String) > 255 {
+		return errors.New("description must be at most 255 characters")
+	}
+	if req.Currency != "" && !fx.ValidCurrency(strings.ToUpper(req.Currency)) {
+		return errors.New("currency must be a three-letter currency code")
+	}
+	return nil
+}
+
+// checkCategory verifies that an optional category belongs to the user.
+func (h *TransactionHandler) checkCategory(ctx context.Context, userID, categoryID pgtype.UUID) error {
+	if !categoryID.Valid {
+		return nil
+	}
+	_, err := h.queries.GetCategory(ctx, db.GetCategoryParams{ID: categoryID, UserID: userID})
+	return err
+}
+
+// checkAccount verifies that an optional account belongs to the user and
+// returns it.
+func (h *TransactionHandler) checkAccount(ctx context.Context, userID, accountID pgtype.UUID) (db.Account, error) {
+	if !accountID.Valid {
+		return db.Account{}, nil
+	}
+	return h.queries.GetAccount(ctx, db.GetAccountParams{ID: accountID, UserID: userID})
+}
+
+// prepare checks req's category and account against the user's and settles
+// its currency, returning the exchange rate and amount in the user's currency
+// to save it with. Errors the client can fix satisfy isRequestError.
+func (h *TransactionHandler) prepare(ctx context.Context, userID pgtype.UUID, req *transactionRequest) (money.Decimal, money.Decimal, error) {
+	err := h.checkCategory(ctx, userID, req.CategoryID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return money.Null, money.Null, errCategoryNotFound
+	}
+	if err != nil {
+		return money.Null, money.Null, fmt.Errorf("failed to get category: %w", err)
+	}
+	account, err := h.checkAccount(ctx, userID, req.AccountID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return money.Null, money.Null, errAccountNotFound
+	}
+	if err != nil {
+		return money.Null, money.Null, fmt.Errorf("failed to get account: %w", err)
+	}
+	rate, baseAmount, err := h.exchangeRate(ctx, userID, account, req)
+	if err != nil && !isRequestError(err) {
+		return money.Null, money.Null, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	return rate, baseAmount, err
+}
+
+// isRequestError reports whether err, from prepare or from saving a
+// transaction, is down to the request rather than the server.
+func isRequestError(err error) bool {
+	for _, target := range []error{
+		errCategoryNotFound, errAccountNotFound, errCurrencyMismatch, errAmountPrecision, errAmountRange,
+		fx.ErrNoRate, errUnknownTag, errUnknownPayee, errInvalidPayee,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// exchangeRate settles req's currency and returns its rate to the user's
+// currency on the transaction's date in the user's time zone, along with the
+// amount converted at that rate and rounded to the user's currency. It is
+// looked up before the transaction is saved so a slow rates provider does
+// not hold a database transaction open.
+func (h *TransactionHandler) exchangeRate(ctx context.Context, userID pgtype.UUID, account db.Account, req *transactionRequest) (money.Decimal, money.Decimal, error) {
+	req.Currency = strings.ToUpper(req.Currency)
+	user, err := h.queries.GetUser(ctx, userID)
+	if err != nil {
+		return money.Null, money.Null, err
+	}
+	switch {
+	case account.ID.Valid && req.Currency == "":
+		req.Currency = account.Currency
+	case account.ID.Valid && req.Currency != account.Currency:
+		return money.Null, money.Null, errCurrencyMismatch
+	case req.Currency == "":
+		req.Currency = user.Currency
+	}
+	if !req.Amount.Fits(req.Currency) {
+		return money.Null, money.Null, errAmountPrecision
+	}
+	req.Amount = req.Amount.RoundTo(req.Currency)
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	rate, err := h.rates.Rate(ctx, h.queries, req.Currency, user.Currency, req.Date.Time.In(loc))
+	if err != nil {
+		return money.Null, money.Null, err
+	}
+	exchangeRate := fx.Decimal(rate)
+	baseAmount := req.Amount.Mul(exchangeRate).RoundTo(user.Currency)
+	if !baseAmount.InRange() {
+		return money.Null, money.Null, errAmountRange
+	}
+	return exchangeRate, baseAmount, nil
+}
+
+// resolvePayee decides which payee a transaction belongs to.
+func resolvePayee(ctx context.Context, q *db.Queries, userID pgtype.UUID, req transactionRequest) (pgtype.UUID, error) {
+	switch {
+	case req.PayeeID.Valid:
+		_, err := q.GetPayee(ctx, db.GetPayeeParams{ID: req.PayeeID, UserID: userID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgtype.UUID{}, errUnknownPayee
+		}
+		return req.PayeeID, err
+	case strings.TrimSpace(req.PayeeName) != "":
+		p, err := findOrCreatePayee(ctx, q, userID, req.PayeeName)
+		return p.ID, err
+	default:
+		return matchPayee(ctx, q, userID, req.Description.String)
+	}
+}
+
+// setTags replaces a transaction's tags, failing with errUnknownTag if any
+// of the tags does not belong to the user.
+func setTags(ctx context.Context, q *db.Queries, userID, transactionID pgtype.UUID, tagIDs []pgtype.UUID) error {
+	if err := q.DeleteTransactionTags(ctx, transactionID); err != nil {
+		return err
+	}
+	unique := make(map[pgtype.UUID]bool, len(tagIDs))
+	for _, id := range tagIDs {
+		unique[id] = true
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+	added, err := q.AddTransactionTags(ctx, db.AddTransactionTagsParams{
+		TransactionID: transactionID,
+		UserID:        userID,
+		TagIds:        tagIDs,
+	})
+	if err != nil {
+		return err
+	}
+	if int(added) != len(unique) {
+		return errUnknownTag
+	}
+	return nil
+}
+
+// withTags attaches tags to a page of transactions.
+func (h *TransactionHandler) withTags(ctx context.Context, transactions []db.Transaction) ([]transactionResponse, error) {
+	ids := make([]pgtype.UUID, len(transactions))
+	for i, t := range transactions {
+		ids[i] = t.ID
+	}
+	rows, err := h.queries.ListTagsForTransactions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[pgtype.UUID][]transactionTag)
+	for _, row := range rows {
+		tags[row.TransactionID] = append(tags[row.TransactionID], transactionTag{ID: row.ID, Name: row.Name, Color: row.Color})
+	}
+	resp := make([]transactionResponse, len(transactions))
+	for i, t := range transactions {
+		resp[i] = transactionResponse{Transaction: t, Tags: tags[t.ID]}
+		if resp[i].Tags == nil {
+			resp[i].Tags = []transactionTag{}
+		}
+	}
+	return resp, nil
+}
+
+// respondTransaction writes a single transaction with its tags and suggestions.
+func (h *TransactionHandler) respondTransaction(w http.ResponseWriter, r *http.Request, status int, transaction db.Transaction) {
+	resp, err := h.withTags(r.Context(), []db.Transaction{transaction})
+	if err != nil {
+		h.logger.Error("Failed to load transaction tags", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to load transaction tags")
+		return
+	}
+	resp[0].Suggestions = h.suggest(r.Context(), transaction)
+	respondJSON(w, status, resp[0])
+}
+
+// suggest returns category suggestions for an uncategorized transaction.
+// Failures are logged rather than surfaced: suggestions are advisory.
+func (h *TransactionHandler) suggest(ctx context.Context, t db.Transaction) []categorizer.Suggestion {
+	if t.CategoryID.Valid {
+		return nil
+	}
+	suggestions, err := categorizer.Suggest(ctx, h.queries, t.UserID, t.Description.String, t.BaseAmount.Float64(), t.Type, categorizer.DefaultSuggestions)
+	if err != nil {
+		h.logger.Warn("Failed to suggest categories", zap.Error(err))
+	}
+	return suggestions
+}
+
 func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "CreateTransaction not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req transactionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rate, baseAmount, err := h.prepare(r.Context(), userID, &req)
+	if isRequestError(err) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to prepare transaction", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create transaction")
+		return
+	}
+
+	var transaction db.Transaction
+	var statuses []*budget.Status
+	var notifications []db.Notification
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		var err error
+		transaction, notifications, err = insertTransaction(r.Context(), qtx, userID, req, rate, baseAmount)
+		if err != nil {
+			return err
+		}
+		statuses, err = h.evaluateBudgets(r.Context(), qtx, transaction)
+		return err
+	})
+	if isRequestError(err) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to create transaction", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to create transaction")
+		return
+	}
+	publishBudgetStatuses(r.Context(), h.notify, userID, statuses)
+	for _, n := range notifications {
+		h.notify.Dispatch(r.Context(), n)
+	}
+
+	h.respondTransaction(w, r, http.StatusCreated, transaction)
+}
+
+// insertTransaction saves a prepared transaction and everything that follows
+// from it: its tags, the category model, and any bill, goal or anomaly it
+// matches. It returns the notifications to dispatch once the database
+// transaction commits. Budgets are left to the caller, which may evaluate a
+// whole batch at once.
+func insertTransaction(ctx context.Context, qtx *db.Queries, userID pgtype.UUID, req transactionRequest, rate, baseAmount money.Decimal) (db.Transaction, []db.Notification, error) {
+	payeeID, err := resolvePayee(ctx, qtx, userID, req)
+	if err != nil {
+		return db.Transaction{}, nil, err
+	}
+	transaction, err := qtx.CreateTransaction(ctx, db.CreateTransactionParams{
+		ID:           utils.NewUUID(),
+		UserID:       userID,
+		Amount:       req.Amount,
+		Description:  req.Description,
+		CategoryID:   req.CategoryID,
+		Date:         req.Date,
+		Type:         req.Type,
+		PayeeID:      payeeID,
+		AccountID:    req.AccountID,
+		Currency:     req.Currency,
+		ExchangeRate: rate,
+		BaseAmount:   baseAmount,
+	})
+	if err != nil {
+		return db.Transaction{}, nil, err
+	}
+	if err := setTags(ctx, qtx, userID, transaction.ID, req.TagIDs); err != nil {
+		return db.Transaction{}, nil, err
+	}
+	if err := categorizer.Learn(ctx, qtx, transaction); err != nil {
+		return db.Transaction{}, nil, err
+	}
+	if _, err := bill.Match(ctx, qtx, transaction); err != nil {
+		return db.Transaction{}, nil, err
+	}
+	notifications, err := goal.Match(ctx, qtx, transaction)
+	if err != nil {
+		return db.Transaction{}, nil, err
+	}
+	flagged, err := anomaly.Check(ctx, qtx, transaction)
+	if err != nil {
+		return db.Transaction{}, nil, err
+	}
+	if flagged != nil {
+		notifications = append(notifications, *flagged)
+	}
+	return transaction, notifications, nil
+}
+
+type importTransactionsRequest struct {
+	Transactions []transactionRequest `json:"transactions"`
+}
+
+// ImportTransactions saves a batch of transactions, e.g. read from a bank
+// statement, all or none of them. Uncategorized ones come back with the
+// categories the user's model suggests, as on create.
+func (h *TransactionHandler) ImportTransactions(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 2<<20)
+	var req importTransactionsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Transactions) == 0 || len(req.Transactions) > maxImportTransactions {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("transactions must hold between 1 and %d transactions", maxImportTransactions))
+		return
+	}
+	rates := make([]money.Decimal, len(req.Transactions))
+	baseAmounts := make([]money.Decimal, len(req.Transactions))
+	for i := range req.Transactions {
+		t := &req.Transactions[i]
+		if err := t.validate(); err != nil {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("transactions[%d]: %s", i, err))
+			return
+		}
+		rates[i], baseAmounts[i], err = h.prepare(r.Context(), userID, t)
+		if isRequestError(err) {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("transactions[%d]: %s", i, err))
+			return
+		}
+		if err != nil {
+			h.logger.Error("Failed to prepare transaction", zap.Error(err))
+			respondError(w, http.StatusInternalServerError, "failed to import transactions")
+			return
+		}
+	}
+
+	transactions := make([]db.Transaction, len(req.Transactions))
+	var statuses []*budget.Status
+	var notifications []db.Notification
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		for i, t := range req.Transactions {
+			transaction, raised, err := insertTransaction(r.Context(), qtx, userID, t, rates[i], baseAmounts[i])
+			if err != nil {
+				return fmt.Errorf("transactions[%d]: %w", i, err)
+			}
+			transactions[i] = transaction
+			notifications = append(notifications, raised...)
+		}
+		var err error
+		statuses, err = h.evaluateBudgets(r.Context(), qtx, transactions...)
+		return err
+	})
+	if isRequestError(err) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to import transactions", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to import transactions")
+		return
+	}
+	publishBudgetStatuses(r.Context(), h.notify, userID, statuses)
+	for _, n := range notifications {
+		h.notify.Dispatch(r.Context(), n)
+	}
+
+	resp, err := h.withTags(r.Context(), transactions)
+	if err != nil {
+		h.logger.Error("Failed to load transaction tags", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to load transaction tags")
+		return
+	}
+	for i, t := range transactions {
+		resp[i].Suggestions = h.suggest(r.Context(), t)
+	}
+	respondJSON(w, http.StatusCreated, resp)
 }
 
 func (h *TransactionHandler) GetTransactionByID(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "GetTransactionByID not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	transactionID, err := uuidParam(r, "transactionID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transaction, err := h.queries.GetTransaction(r.Context(), db.GetTransactionParams{ID: transactionID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get transaction", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get transaction")
+		return
+	}
+
+	h.respondTransaction(w, r, http.StatusOK, transaction)
 }
 
 func (h *TransactionHandler) ListTransactionsByUserID(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "ListTransactionsByUserID not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, offset, err := pagination(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fromDate, err := queryTime(r, "from_date", false)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	toDate, err := queryTime(r, "to_date", true)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tagIDs, err := parseUUIDList(r.URL.Query().Get("tags"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tagMatch := r.URL.Query().Get("tag_match")
+	if tagMatch != "" && tagMatch != "any" && tagMatch != "all" {
+		respondError(w, http.StatusBadRequest, "tag_match must be any or all")
+		return
+	}
+	if tagIDs == nil {
+		tagIDs = []pgtype.UUID{}
+	}
+
+	transactions, err := h.queries.ListTransactionsByUser(r.Context(), db.ListTransactionsByUserParams{
+		UserID:       userID,
+		FromDate:     fromDate,
+		ToDate:       toDate,
+		TagIds:       tagIDs,
+		MatchAllTags: tagMatch == "all",
+		RowLimit:     limit,
+		RowOffset:    offset,
+	})
+	if err != nil {
+		h.logger.Error("Failed to list transactions", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list transactions")
+		return
+	}
+	resp, err := h.withTags(r.Context(), transactions)
+	if err != nil {
+		h.logger.Error("Failed to load transaction tags", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to load transaction tags")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resp)
 }
 
 func (h *TransactionHandler) UpdateTransaction(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "UpdateTransaction not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	transactionID, err := uuidParam(r, "transactionID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req transactionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rate, baseAmount, err := h.prepare(r.Context(), userID, &req)
+	if isRequestError(err) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to prepare transaction", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update transaction")
+		return
+	}
+
+	var transaction db.Transaction
+	var statuses []*budget.Status
+	var notifications []db.Notification
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		previous, err := qtx.GetTransaction(r.Context(), db.GetTransactionParams{ID: transactionID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		if err := categorizer.Forget(r.Context(), qtx, previous); err != nil {
+			return err
+		}
+		payeeID, err := resolvePayee(r.Context(), qtx, userID, req)
+		if err != nil {
+			return err
+		}
+		transaction, err = qtx.UpdateTransaction(r.Context(), db.UpdateTransactionParams{
+			ID:           transactionID,
+			UserID:       userID,
+			Amount:       req.Amount,
+			Description:  req.Description,
+			CategoryID:   req.CategoryID,
+			Date:         req.Date,
+			Type:         req.Type,
+			PayeeID:      payeeID,
+			AccountID:    req.AccountID,
+			Currency:     req.Currency,
+			ExchangeRate: rate,
+			BaseAmount:   baseAmount,
+		})
+		if err != nil {
+			return err
+		}
+		if req.TagIDs != nil {
+			if err := setTags(r.Context(), qtx, userID, transaction.ID, req.TagIDs); err != nil {
+				return err
+			}
+		}
+		if err := categorizer.Learn(r.Context(), qtx, transaction); err != nil {
+			return err
+		}
+		// Re-match so an edited amount or date can settle a different bill.
+		if err := bill.Unmatch(r.Context(), qtx, transaction.ID); err != nil {
+			return err
+		}
+		if _, err := bill.Match(r.Context(), qtx, transaction); err != nil {
+			return err
+		}
+		reached, err := goal.Match(r.Context(), qtx, transaction)
+		if err != nil {
+			return err
+		}
+		notifications = append(notifications, reached...)
+		flagged, err := anomaly.Check(r.Context(), qtx, transaction)
+		if err != nil {
+			return err
+		}
+		if flagged != nil {
+			notifications = append(notifications, *flagged)
+		}
+		statuses, err = h.evaluateBudgets(r.Context(), qtx, previous, transaction)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+	if isRequestError(err) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update transaction", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to update transaction")
+		return
+	}
+	publishBudgetStatuses(r.Context(), h.notify, userID, statuses)
+	for _, n := range notifications {
+		h.notify.Dispatch(r.Context(), n)
+	}
+
+	h.respondTransaction(w, r, http.StatusOK, transaction)
 }
 
 func (h *TransactionHandler) DeleteTransaction(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusNotImplemented)
-	json.NewEncoder(w).Encode(map[string]string{"message": "DeleteTransaction not implemented"})
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	transactionID, err := uuidParam(r, "transactionID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Attachment rows go with the transaction; their files are removed once
+	// the deletion has committed.
+	var attachments []db.Attachment
+	var statuses []*budget.Status
+	var notifications []db.Notification
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		qtx := h.queries.WithTx(tx)
+		attachments, err = qtx.ListAttachmentsByTransaction(r.Context(), db.ListAttachmentsByTransactionParams{
+			TransactionID: transactionID,
+			UserID:        userID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := bill.Unmatch(r.Context(), qtx, transactionID); err != nil {
+			return err
+		}
+		notifications, err = goal.Unmatch(r.Context(), qtx, userID, transactionID)
+		if err != nil {
+			return err
+		}
+		deleted, err := qtx.DeleteTransaction(r.Context(), db.DeleteTransactionParams{ID: transactionID, UserID: userID})
+		if err != nil {
+			return err
+		}
+		if err := categorizer.Forget(r.Context(), qtx, deleted); err != nil {
+			return err
+		}
+		statuses, err = h.evaluateBudgets(r.Context(), qtx, deleted)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to delete transaction", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to delete transaction")
+		return
+	}
+	publishBudgetStatuses(r.Context(), h.notify, userID, statuses)
+	for _, n := range notifications {
+		h.notify.Dispatch(r.Context(), n)
+	}
+	for _, a := range attachments {
+		if err := h.storage.Delete(r.Context(), a.StorageKey); err != nil {
+			h.logger.Warn("Failed to delete stored attachment", zap.String("key", a.StorageKey), zap.Error(err))
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// evaluateBudgets re-checks budget thresholds for the category and period of
+// each given transaction, skipping duplicates. All of them must belong to
+// the same user, whose time zone sets the periods. Pass both the old and new
+// versions of an edited transaction so a move between categories or months
+// is reflected on both sides.
+func (h *TransactionHandler) evaluateBudgets(ctx context.Context, q *db.Queries, transactions ...db.Transaction) ([]*budget.Status, error) {
+	var statuses []*budget.Status
+	var loc *time.Location
+	seen := make(map[string]bool)
+	for _, t := range transactions {
+		if !t.CategoryID.Valid {
+			continue
+		}
+		if loc == nil {
+			var err error
+			if loc, err = budget.Location(ctx, q, t.UserID); err != nil {
+				return nil, err
+			}
+		}
+		at := t.Date.Time
+		key := t.CategoryID.String() + budget.MonthOf(at, loc).Start.Format("2006-01")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		status, err := budget.EvaluateCategory(ctx, q, t.UserID, t.CategoryID, at, loc, h.config.App.BudgetAlertThresholds)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+// SuggestCategories returns category suggestions for a description and amount
+// that have not been saved yet, e.g. while the user is filling in a form.
+func (h *TransactionHandler) SuggestCategories(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	query := r.URL.Query()
+	var amount float64
+	if v := query.Get("amount"); v != "" {
+		amount, err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "amount must be a number")
+			return
+		}
+	}
+	txType := db.TransactionType(query.Get("type"))
+	if txType != "" && txType != db.TransactionTypeIncome && txType != db.TransactionTypeExpense {
+		respondError(w, http.StatusBadRequest, "type must be income or expense")
+		return
+	}
+	limit := categorizer.DefaultSuggestions
+	if v := query.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > categorizer.MaxSuggestions {
+			respondError(w, http.StatusBadRequest, "limit must be between 1 and 10")
+			return
+		}
+	}
+
+	suggestions, err := categorizer.Suggest(r.Context(), h.queries, userID, query.Get("description"), amount, txType, limit)
+	if err != nil {
+		h.logger.Error("Failed to suggest categories", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to suggest categories")
+		return
+	}
+	if suggestions == nil {
+		suggestions = []categorizer.Suggestion{}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]any{"suggestions": suggestions})
+}
+
+// RetrainCategorizer rebuilds the user's suggestion model from scratch. The
+// model is normally kept up to date incrementally; this is for recovery and
+// for history that predates the model.
+func (h *TransactionHandler) RetrainCategorizer(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var trained int
+	err = pgx.BeginFunc(r.Context(), h.dbPool, func(tx pgx.Tx) error {
+		trained, err = categorizer.Rebuild(r.Context(), h.queries.WithTx(tx), userID)
+		return err
+	})
+	if err != nil {
+		h.logger.Error("Failed to retrain categorizer", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to retrain categorizer")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]int{"trained": trained})
+}
+
+type quickEntryRequest struct {
+	Text     string `json:"text"`
+	Timezone string `json:"timezone"`
+}
+
+// quickEntryResponse is a parsed quick-entry draft with the category it most
+// likely belongs to. Nothing is saved until the client confirms the draft by
+// creating the transaction.
+type quickEntryResponse struct {
+	quickentry.Draft
+	// AccountID is the one open account whose name matches the draft's
+	// account hint, if any.
+	AccountID   pgtype.UUID              `json:"accountId"`
+	CategoryID  pgtype.UUID              `json:"categoryId"`
+	Suggestions []categorizer.Suggestion `json:"suggestions"`
+}
+
+// QuickEntry parses free text such as "lunch 450 yesterday cash" into a
+// transaction draft for the user to confirm.
+func (h *TransactionHandler) QuickEntry(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var req quickEntryRequest
+	if err := decodeJSON(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		respondError(w, http.StatusBadRequest, "text is required")
+		return
+	}
+	user, err := h.queries.GetUser(r.Context(), userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		respondError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get user", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to get user")
+		return
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	if req.Timezone != "" {
+		if loc, err = time.LoadLocation(req.Timezone); err != nil {
+			respondError(w, http.StatusBadRequest, "unknown timezone")
+			return
+		}
+	}
+
+	draft := quickentry.Parse(req.Text, time.Now().In(loc))
+	resp := quickEntryResponse{Draft: draft}
+
+	if draft.AccountHint != "" {
+		accounts, err := h.queries.ListOpenAccountsByUser(r.Context(), userID)
+		if err != nil {
+			h.logger.Error("Failed to list accounts", zap.Error(err))
+			respondError(w, http.StatusInternalServerError, "failed to list accounts")
+			return
+		}
+		matches := matchAccounts(accounts, draft.AccountHint)
+		if len(matches) > 1 {
+			names := make([]string, len(matches))
+			for i, a := range matches {
+				names[i] = a.Name
+			}
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("%q could be any of the accounts %s", draft.AccountHint, strings.Join(names, ", ")))
+			return
+		}
+		if len(matches) == 1 {
+			resp.AccountID = matches[0].ID
+			if resp.Currency == "" {
+				resp.Currency = matches[0].Currency
+			}
+		}
+	}
+
+	// The model learns from amounts in the user's currency.
+	var baseAmount float64
+	if draft.Amount.Valid() {
+		currency := resp.Currency
+		if currency == "" {
+			currency = user.Currency
+		}
+		rate, err := h.rates.Rate(r.Context(), h.queries, currency, user.Currency, draft.Date)
+		switch {
+		case errors.Is(err, fx.ErrNoRate):
+			// Suggest from the description alone.
+		case err != nil:
+			h.logger.Error("Failed to get exchange rate", zap.Error(err))
+			respondError(w, http.StatusInternalServerError, "failed to get exchange rate")
+			return
+		default:
+			baseAmount = draft.Amount.Mul(fx.Decimal(rate)).RoundTo(user.Currency).Float64()
+		}
+	}
+
+	suggestions, err := categorizer.Suggest(r.Context(), h.queries, userID, draft.Description, baseAmount, draft.Type, categorizer.DefaultSuggestions)
+	if err != nil {
+		h.logger.Error("Failed to suggest categories", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to suggest categories")
+		return
+	}
+	resp.Suggestions = suggestions
+	categories, err := h.queries.ListCategoriesByUser(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list categories", zap.Error(err))
+		respondError(w, http.StatusInternalServerError, "failed to list categories")
+		return
+	}
+
+	// A category named outright in the text beats anything the model guesses.
+	if named, ok := namedCategory(categories, draft); ok {
+		resp.CategoryID = named.ID
+		resp.Type = named.Type
+		resp.Suggestions = append([]categorizer.Suggestion{{
+			CategoryID: named.ID,
+			Name:       named.Name,
+			Type:       named.Type,
+			Confidence: 1,
+		}}, withoutCategory(suggestions, named.ID)...)
+	} else if len(suggestions) > 0 {
+		resp.CategoryID = suggestions[0].CategoryID
+	}
+	if resp.Suggestions == nil {
+		resp.Suggestions = []categorizer.Suggestion{}
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// matchAccounts returns the accounts whose names contain hint, ignoring case,
+// spaces and punctuation, so "mpesa" matches "M-Pesa Savings".
+func matchAccounts(accounts []db.Account, hint string) []db.Account {
+	hint = accountKey(hint)
+	var matches []db.Account
+	for _, a := range accounts {
+		if name := accountKey(a.Name); hint != "" && strings.Contains(name, hint) {
+			matches = append(matches, a)
+		}
+	}
+	return matches
+}
+
+func accountKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// namedCategory finds a category whose name appears in the draft's description.
+func namedCategory(categories []db.Category, draft quickentry.Draft) (db.Category, bool) {
+	description := " " + strings.ToLower(draft.Description) + " "
+	for _, c := range categories {
+		name := strings.ToLower(strings.TrimSpace(c.Name))
+		if name == "" {
+			continue
+		}
+		if strings.Contains(description, " "+name+" ") || strings.EqualFold(draft.CategoryHint, name) {
+			return c, true
+		}
+	}
+	return db.Category{}, false
+}
+
+func withoutCategory(suggestions []categorizer.Suggestion, id pgtype.UUID) []categorizer.Suggestion {
+	var out []categorizer.Suggestion
+	for _, s := range suggestions {
+		if s.CategoryID != id {
+			out = append(out, s)
+		}
+	}
+	return out
 }
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+)
+
+// RequireAdminKey guards operator endpoints with a shared key sent in the
+// X-Admin-Key header. The endpoints are disabled when key is empty.
+func RequireAdminKey(key string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key == "" {
+				forbidden(w, "admin API is disabled")
+				return
+			}
+			given := r.Header.Get("X-Admin-Key")
+			if subtle.ConstantTimeCompare([]byte(given), []byte(key)) != 1 {
+				forbidden(w, "invalid admin key")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func forbidden(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/nyunja/30budget/backend/internal/auth"
+)
+
+// RequireAuth rejects requests without a valid access token and stores the
+// token's user ID in the request context. The token is read from the
+// Authorization header, or from the access_token query parameter for clients
+// such as EventSource that cannot set headers.
+func RequireAuth(secret string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.URL.Query().Get("access_token")
+			if h := r.Header.Get("Authorization"); h != "" {
+				scheme, value, ok := strings.Cut(h, " ")
+				if !ok || !strings.EqualFold(scheme, "Bearer") {
+					unauthorized(w, "authorization header must use the Bearer scheme")
+					return
+				}
+				token = strings.TrimSpace(value)
+			}
+			if token == "" {
+				unauthorized(w, "missing access token")
+				return
+			}
+
+			claims, err := auth.ParseAccessToken(secret, token)
+			if errors.Is(err, auth.ErrExpiredToken) {
+				unauthorized(w, "access token has expired")
+				return
+			}
+			if err != nil {
+				unauthorized(w, "invalid access token")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithUserID(r.Context(), claims.Subject)))
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
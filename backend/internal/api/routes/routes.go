@@ -1,74 +1,307 @@
 package routes
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/nyunja/30budget/backend/internal/api/handlers"
+	apimiddleware "github.com/nyunja/30budget/backend/internal/api/middleware"
+	"github.com/nyunja/30budget/backend/internal/bill"
 	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/digest"
+	"github.com/nyunja/30budget/backend/internal/events"
+	"github.com/nyunja/30budget/backend/internal/fx"
+	"github.com/nyunja/30budget/backend/internal/mail"
+	"github.com/nyunja/30budget/backend/internal/networth"
+	"github.com/nyunja/30budget/backend/internal/notify"
+	"github.com/nyunja/30budget/backend/internal/sms"
+	"github.com/nyunja/30budget/backend/internal/storage"
+	"github.com/nyunja/30budget/backend/internal/subscription"
 	"go.uber.org/zap"
 )
 
 // SetupRoutes initializes all API routes.
 func SetupRoutes(r *chi.Mux, dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) {
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		logger.Fatal("Failed to initialize storage", zap.Error(err))
+	}
+
+	hub := events.NewHub(logger)
+	if cfg.Redis.PubSub {
+		bus, err := events.NewRedisBus(cfg.Redis.URL, cfg.Redis.Password, logger)
+		if err != nil {
+			logger.Fatal("Failed to initialize Redis event bus", zap.Error(err))
+		}
+		hub.SetBus(bus)
+		go bus.Run(context.Background(), hub)
+	}
+	mailer, err := mail.New(cfg.Email, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize mailer", zap.Error(err))
+	}
+	texter, err := sms.New(cfg.SMS, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize SMS provider", zap.Error(err))
+	}
+	rateProvider, err := fx.New(cfg.FX)
+	if err != nil {
+		logger.Fatal("Failed to initialize exchange rate provider", zap.Error(err))
+	}
+	rates := fx.NewConverter(rateProvider, cfg.FX.Provider)
+	dispatcher := notify.NewDispatcher(dbPool, cfg, logger, hub, mailer, texter)
+	go dispatcher.Run(context.Background(), 30*time.Second)
+	go digest.NewJob(dbPool, cfg, logger, dispatcher).Run(context.Background(), 15*time.Minute)
+	go bill.NewReminderJob(dbPool, cfg, logger, dispatcher).Run(context.Background(), 15*time.Minute)
+	go subscription.NewJob(dbPool, cfg, logger).Run(context.Background(), 6*time.Hour)
+	go networth.NewJob(dbPool, cfg, logger, rates).Run(context.Background(), time.Hour)
+
 	// Initialize handlers
 	userHandler := handlers.NewUserHandler(dbPool, cfg, logger)
-	categoryHandler := handlers.NewCategoryHandler(dbPool, cfg, logger)
-	transactionHandler := handlers.NewTransactionHandler(dbPool, cfg, logger)
-	notificationHandler := handlers.NewNotificationHandler(dbPool, cfg, logger)
+	authHandler := handlers.NewAuthHandler(dbPool, cfg, logger)
+	categoryHandler := handlers.NewCategoryHandler(dbPool, cfg, logger, dispatcher)
+	transactionHandler := handlers.NewTransactionHandler(dbPool, cfg, logger, store, dispatcher, rates)
+	notificationHandler := handlers.NewNotificationHandler(dbPool, cfg, logger, dispatcher)
+	notificationPreferenceHandler := handlers.NewNotificationPreferenceHandler(dbPool, cfg, logger, dispatcher)
 	budgetTemplateHandler := handlers.NewBudgetTemplateHandler(dbPool, cfg, logger)
+	tagHandler := handlers.NewTagHandler(dbPool, cfg, logger)
+	payeeHandler := handlers.NewPayeeHandler(dbPool, cfg, logger)
+	billHandler := handlers.NewBillHandler(dbPool, cfg, logger)
+	recurringTransactionHandler := handlers.NewRecurringTransactionHandler(dbPool, cfg, logger)
+	subscriptionHandler := handlers.NewSubscriptionHandler(dbPool, cfg, logger)
+	calendarFeedHandler := handlers.NewCalendarFeedHandler(dbPool, cfg, logger)
+	summaryHandler := handlers.NewSummaryHandler(dbPool, cfg, logger)
+	analyticsHandler := handlers.NewAnalyticsHandler(dbPool, cfg, logger)
+	accountHandler := handlers.NewAccountHandler(dbPool, cfg, logger)
+	forecastHandler := handlers.NewForecastHandler(dbPool, cfg, logger, rates)
+	goalHandler := handlers.NewGoalHandler(dbPool, cfg, logger, dispatcher)
+	debtHandler := handlers.NewDebtHandler(dbPool, cfg, logger)
+	netWorthHandler := handlers.NewNetWorthHandler(dbPool, cfg, logger, rates)
+	anomalyHandler := handlers.NewAnomalyHandler(dbPool, cfg, logger)
+	attachmentHandler := handlers.NewAttachmentHandler(dbPool, cfg, logger, store)
+	eventHandler := handlers.NewEventHandler(cfg, logger, hub)
+	phoneHandler := handlers.NewPhoneHandler(dbPool, cfg, logger, texter)
+	outboxHandler := handlers.NewOutboxHandler(dbPool, cfg, logger, dispatcher)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(dbPool, cfg, logger, rates)
 
 	r.Route("/api/v1", func(r chi.Router) {
-		// Example route
-		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte(`{"message":"Welcome to the 30Budget API!"}`))
-		})
+		// Real-time event stream (Server-Sent Events). It stays open until the
+		// client disconnects, so it is the one route without a request timeout.
+		r.With(apimiddleware.RequireAuth(cfg.JWT.Secret)).Get("/users/{userID}/events", eventHandler.StreamEvents)
 
-		// User routes
-		r.Route("/users", func(r chi.Router) {
-			r.Post("/", userHandler.CreateUser)
-			r.Get("/{userID}", userHandler.GetUserByID)
-			r.Put("/{userID}", userHandler.UpdateUser)
-			r.Delete("/{userID}", userHandler.DeleteUser)
-		})
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(60 * time.Second))
 
-		// Category routes
-		r.Route("/users/{userID}/categories", func(r chi.Router) {
-			r.Post("/", categoryHandler.CreateCategory)
-			r.Get("/", categoryHandler.ListCategoriesByUserID)
-			r.Get("/{categoryID}", categoryHandler.GetCategoryByID)
-			r.Put("/{categoryID}", categoryHandler.UpdateCategory)
-			r.Delete("/{categoryID}", categoryHandler.DeleteCategory)
-		})
+			// Example route
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"message":"Welcome to the 30Budget API!"}`))
+			})
 
-		// Transaction routes
-		r.Route("/users/{userID}/transactions", func(r chi.Router) {
-			r.Post("/", transactionHandler.CreateTransaction)
-			r.Get("/", transactionHandler.ListTransactionsByUserID)
-			r.Get("/{transactionID}", transactionHandler.GetTransactionByID)
-			r.Put("/{transactionID}", transactionHandler.UpdateTransaction)
-			r.Delete("/{transactionID}", transactionHandler.DeleteTransaction)
-		})
+			// Auth routes
+			r.Post("/auth/login", authHandler.Login)
 
-		// Notification routes
-		r.Route("/users/{userID}/notifications", func(r chi.Router) {
-			r.Post("/", notificationHandler.CreateNotification)
-			r.Get("/", notificationHandler.ListNotificationsByUserID)
-			r.Get("/{notificationID}", notificationHandler.GetNotificationByID)
-			r.Put("/{notificationID}", notificationHandler.UpdateNotification)
-			r.Delete("/{notificationID}", notificationHandler.DeleteNotification)
-		})
+			// User routes
+			r.Route("/users", func(r chi.Router) {
+				r.Post("/", userHandler.CreateUser)
+				r.Get("/{userID}", userHandler.GetUserByID)
+				r.Put("/{userID}", userHandler.UpdateUser)
+				r.Delete("/{userID}", userHandler.DeleteUser)
+			})
+
+			// Exchange rate routes
+			r.Get("/exchange-rates", exchangeRateHandler.GetExchangeRate)
+
+			// Phone routes (verified numbers receive SMS alerts)
+			r.Route("/users/{userID}/phone", func(r chi.Router) {
+				r.Put("/", phoneHandler.StartPhoneVerification)
+				r.Post("/verify", phoneHandler.VerifyPhone)
+				r.Delete("/", phoneHandler.RemovePhone)
+			})
+
+			// Category routes
+			r.Route("/users/{userID}/categories", func(r chi.Router) {
+				r.Post("/", categoryHandler.CreateCategory)
+				r.Get("/", categoryHandler.ListCategoriesByUserID)
+				r.Get("/{categoryID}", categoryHandler.GetCategoryByID)
+				r.Put("/{categoryID}", categoryHandler.UpdateCategory)
+				r.Delete("/{categoryID}", categoryHandler.DeleteCategory)
+			})
+
+			// Transaction routes
+			r.Route("/users/{userID}/transactions", func(r chi.Router) {
+				r.Post("/", transactionHandler.CreateTransaction)
+				r.Get("/", transactionHandler.ListTransactionsByUserID)
+				r.Post("/import", transactionHandler.ImportTransactions)
+				r.Get("/suggestions", transactionHandler.SuggestCategories)
+				r.Post("/suggestions/retrain", transactionHandler.RetrainCategorizer)
+				r.Post("/quick-entry", transactionHandler.QuickEntry)
+				r.Get("/{transactionID}", transactionHandler.GetTransactionByID)
+				r.Put("/{transactionID}", transactionHandler.UpdateTransaction)
+				r.Delete("/{transactionID}", transactionHandler.DeleteTransaction)
+
+				r.Post("/{transactionID}/attachments", attachmentHandler.UploadAttachment)
+				r.Get("/{transactionID}/attachments", attachmentHandler.ListAttachments)
+				r.Get("/{transactionID}/attachments/{attachmentID}", attachmentHandler.DownloadAttachment)
+				r.Delete("/{transactionID}/attachments/{attachmentID}", attachmentHandler.DeleteAttachment)
+			})
+
+			// Tag routes
+			r.Route("/users/{userID}/tags", func(r chi.Router) {
+				r.Post("/", tagHandler.CreateTag)
+				r.Get("/", tagHandler.ListTagsByUserID)
+				r.Get("/spending", tagHandler.SpendingByTag)
+				r.Get("/{tagID}", tagHandler.GetTagByID)
+				r.Put("/{tagID}", tagHandler.UpdateTag)
+				r.Delete("/{tagID}", tagHandler.DeleteTag)
+			})
+
+			// Payee routes
+			r.Route("/users/{userID}/payees", func(r chi.Router) {
+				r.Post("/", payeeHandler.CreatePayee)
+				r.Get("/", payeeHandler.ListPayeesByUserID)
+				r.Get("/spending", payeeHandler.SpendingByPayee)
+				r.Get("/{payeeID}", payeeHandler.GetPayeeByID)
+				r.Put("/{payeeID}", payeeHandler.UpdatePayee)
+				r.Delete("/{payeeID}", payeeHandler.DeletePayee)
+				r.Post("/{payeeID}/merge", payeeHandler.MergePayees)
+			})
+
+			// Summary routes
+			r.Get("/users/{userID}/summary", summaryHandler.GetSummary)
+
+			// Analytics routes
+			r.Route("/users/{userID}/analytics", func(r chi.Router) {
+				r.Get("/spending", analyticsHandler.SpendingTrend)
+				r.Get("/comparison", analyticsHandler.CompareSpending)
+				r.Get("/heatmap", analyticsHandler.SpendingHeatmap)
+			})
+
+			// Anomaly routes
+			r.Get("/users/{userID}/anomalies", anomalyHandler.ListAnomaliesByUserID)
+
+			// Account routes
+			r.Route("/users/{userID}/accounts", func(r chi.Router) {
+				r.Post("/", accountHandler.CreateAccount)
+				r.Get("/", accountHandler.ListAccountsByUserID)
+				r.Get("/{accountID}", accountHandler.GetAccountByID)
+				r.Put("/{accountID}", accountHandler.UpdateAccount)
+				r.Delete("/{accountID}", accountHandler.DeleteAccount)
+			})
+
+			// Forecast routes
+			r.Get("/users/{userID}/forecast", forecastHandler.GetForecast)
+
+			// Savings goal routes
+			r.Route("/users/{userID}/goals", func(r chi.Router) {
+				r.Post("/", goalHandler.CreateGoal)
+				r.Get("/", goalHandler.ListGoalsByUserID)
+				r.Get("/{goalID}", goalHandler.GetGoalByID)
+				r.Put("/{goalID}", goalHandler.UpdateGoal)
+				r.Delete("/{goalID}", goalHandler.DeleteGoal)
+				r.Post("/{goalID}/contributions", goalHandler.CreateContribution)
+				r.Get("/{goalID}/contributions", goalHandler.ListContributions)
+				r.Delete("/{goalID}/contributions/{contributionID}", goalHandler.DeleteContribution)
+			})
+
+			// Debt routes
+			r.Route("/users/{userID}/debts", func(r chi.Router) {
+				r.Post("/", debtHandler.CreateDebt)
+				r.Get("/", debtHandler.ListDebtsByUserID)
+				r.Get("/plan", debtHandler.PlanPayoff)
+				r.Get("/{debtID}", debtHandler.GetDebtByID)
+				r.Put("/{debtID}", debtHandler.UpdateDebt)
+				r.Delete("/{debtID}", debtHandler.DeleteDebt)
+			})
+
+			// Net worth routes
+			r.Route("/users/{userID}/net-worth", func(r chi.Router) {
+				r.Get("/", netWorthHandler.GetNetWorth)
+				r.Post("/items", netWorthHandler.CreateNetWorthItem)
+				r.Get("/items", netWorthHandler.ListNetWorthItems)
+				r.Get("/items/{itemID}", netWorthHandler.GetNetWorthItem)
+				r.Put("/items/{itemID}", netWorthHandler.UpdateNetWorthItem)
+				r.Delete("/items/{itemID}", netWorthHandler.DeleteNetWorthItem)
+				r.Get("/items/{itemID}/valuations", netWorthHandler.ListValuations)
+				r.Post("/items/{itemID}/valuations", netWorthHandler.CreateValuation)
+				r.Delete("/items/{itemID}/valuations/{valuationID}", netWorthHandler.DeleteValuation)
+			})
+
+			// Bill routes
+			r.Route("/users/{userID}/bills", func(r chi.Router) {
+				r.Post("/", billHandler.CreateBill)
+				r.Get("/", billHandler.ListBillsByUserID)
+				r.Get("/upcoming", billHandler.ListUpcomingBills)
+				r.Get("/{billID}", billHandler.GetBillByID)
+				r.Put("/{billID}", billHandler.UpdateBill)
+				r.Delete("/{billID}", billHandler.DeleteBill)
+				r.Post("/{billID}/pay", billHandler.PayBill)
+				r.Get("/{billID}/payments", billHandler.ListBillPayments)
+			})
+
+			// Recurring transaction routes
+			r.Route("/users/{userID}/recurring-transactions", func(r chi.Router) {
+				r.Post("/", recurringTransactionHandler.CreateRecurringTransaction)
+				r.Get("/", recurringTransactionHandler.ListRecurringTransactionsByUserID)
+				r.Get("/{recurringID}", recurringTransactionHandler.GetRecurringTransactionByID)
+				r.Put("/{recurringID}", recurringTransactionHandler.UpdateRecurringTransaction)
+				r.Delete("/{recurringID}", recurringTransactionHandler.DeleteRecurringTransaction)
+			})
+
+			// Detected subscription routes
+			r.Route("/users/{userID}/subscriptions", func(r chi.Router) {
+				r.Get("/", subscriptionHandler.ListSubscriptionsByUserID)
+				r.Post("/scan", subscriptionHandler.ScanSubscriptions)
+				r.Post("/{subscriptionID}/confirm", subscriptionHandler.ConfirmSubscription)
+				r.Post("/{subscriptionID}/dismiss", subscriptionHandler.DismissSubscription)
+			})
+
+			// Calendar feed routes. The feed itself is authorized by the secret
+			// token in its URL so calendar apps can subscribe to it.
+			r.Get("/users/{userID}/calendar-feed", calendarFeedHandler.GetCalendarFeed)
+			r.Post("/users/{userID}/calendar-feed", calendarFeedHandler.RotateCalendarFeed)
+			r.Delete("/users/{userID}/calendar-feed", calendarFeedHandler.RevokeCalendarFeed)
+			r.Get("/calendar/{token}.ics", calendarFeedHandler.ServeCalendarFeed)
+
+			// Notification routes
+			r.Route("/users/{userID}/notifications", func(r chi.Router) {
+				r.Post("/", notificationHandler.CreateNotification)
+				r.Get("/", notificationHandler.ListNotificationsByUserID)
+				r.Get("/unread-count", notificationHandler.CountUnreadNotifications)
+				r.Post("/mark-read", notificationHandler.MarkNotificationsRead)
+				r.Post("/mark-all-read", notificationHandler.MarkAllNotificationsRead)
+				r.Get("/{notificationID}", notificationHandler.GetNotificationByID)
+				r.Put("/{notificationID}", notificationHandler.UpdateNotification)
+				r.Delete("/{notificationID}", notificationHandler.DeleteNotification)
+			})
+
+			// Notification preference routes
+			r.Get("/users/{userID}/notification-preferences", notificationPreferenceHandler.GetNotificationPreferences)
+			r.Put("/users/{userID}/notification-preferences", notificationPreferenceHandler.UpdateNotificationPreferences)
+
+			// Budget Template routes
+			r.Route("/users/{userID}/budget-templates", func(r chi.Router) {
+				r.Post("/", budgetTemplateHandler.CreateBudgetTemplate)
+				r.Get("/", budgetTemplateHandler.ListBudgetTemplatesByUserID)
+				r.Get("/{templateID}", budgetTemplateHandler.GetBudgetTemplateByID)
+				r.Put("/{templateID}", budgetTemplateHandler.UpdateBudgetTemplate)
+				r.Delete("/{templateID}", budgetTemplateHandler.DeleteBudgetTemplate)
+			})
 
-		// Budget Template routes
-		r.Route("/users/{userID}/budget-templates", func(r chi.Router) {
-			r.Post("/", budgetTemplateHandler.CreateBudgetTemplate)
-			r.Get("/", budgetTemplateHandler.ListBudgetTemplatesByUserID)
-			r.Get("/{templateID}", budgetTemplateHandler.GetBudgetTemplateByID)
-			r.Put("/{templateID}", budgetTemplateHandler.UpdateBudgetTemplate)
-			r.Delete("/{templateID}", budgetTemplateHandler.DeleteBudgetTemplate)
+			// Admin routes (require X-Admin-Key)
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(apimiddleware.RequireAdminKey(cfg.App.AdminAPIKey))
+				r.Get("/outbox", outboxHandler.ListOutboxMessages)
+				r.Post("/outbox/replay", outboxHandler.ReplayDeadOutboxMessages)
+				r.Get("/outbox/{messageID}", outboxHandler.GetOutboxMessage)
+				r.Post("/outbox/{messageID}/replay", outboxHandler.ReplayOutboxMessage)
+				r.Post("/exchange-rates", exchangeRateHandler.ImportExchangeRates)
+			})
 		})
 	})
 }
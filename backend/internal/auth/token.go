@@ -0,0 +1,104 @@
+// Package auth issues and verifies the short-lived access tokens sent as
+// "Authorization: Bearer <token>". Tokens are HS256-signed JWTs whose subject
+// is the user ID.
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned for malformed tokens and bad signatures.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrExpiredToken is returned for well-formed tokens past their expiry.
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Claims are the JWT claims carried by an access token.
+type Claims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// NewAccessToken signs a token for userID that expires after ttl.
+func NewAccessToken(secret, userID string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(Claims{Subject: userID, IssuedAt: now.Unix(), ExpiresAt: now.Add(ttl).Unix()})
+	if err != nil {
+		return "", err
+	}
+	unsigned := encoding.EncodeToString(h) + "." + encoding.EncodeToString(c)
+	return unsigned + "." + encoding.EncodeToString(sign(secret, unsigned)), nil
+}
+
+// ParseAccessToken verifies token's signature and expiry and returns its claims.
+func ParseAccessToken(secret, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	signature, err := encoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(secret, parts[0]+"."+parts[1])) {
+		return nil, ErrInvalidToken
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != "HS256" {
+		return nil, ErrInvalidToken
+	}
+	var c Claims
+	if err := decodeSegment(parts[1], &c); err != nil || c.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	if c.ExpiresAt == 0 || time.Now().Unix() >= c.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+	return &c, nil
+}
+
+func sign(secret, data string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := encoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("invalid token segment: %w", err)
+	}
+	return json.Unmarshal(b, v)
+}
+
+type contextKey struct{}
+
+// WithUserID returns a copy of ctx carrying the authenticated user's ID.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, userID)
+}
+
+// UserID returns the authenticated user's ID stored by WithUserID.
+func UserID(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(contextKey{}).(string)
+	return userID, ok
+}
@@ -0,0 +1,250 @@
+// Package bill tracks bills: when each occurrence is due, which occurrences
+// have been paid, and the transactions that paid them.
+package bill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+	"github.com/nyunja/30budget/backend/internal/utils"
+)
+
+const (
+	// A transaction can settle an occurrence paid up to matchDaysEarly
+	// days before or matchDaysLate days after its due date...
+	matchDaysEarly = 7
+	matchDaysLate  = 14
+	// ...for within matchTolerancePercent of the bill's amount.
+	matchTolerancePercent = 20
+)
+
+// MaxOccurrences caps how many occurrences Upcoming returns, e.g. for a
+// daily bill left unpaid for years.
+const MaxOccurrences = 1000
+
+// ErrNothingDue is returned when paying a bill with no unpaid occurrence.
+var ErrNothingDue = errors.New("bill has no outstanding payment")
+
+// Rule is the bill's schedule.
+func Rule(b db.Bill) recurrence.Rule {
+	return recurrence.New(b.Frequency, b.IntervalCount, b.StartDate, b.EndDate)
+}
+
+// FirstDue is the next due date of a bill with schedule rule and no payments.
+func FirstDue(rule recurrence.Rule) pgtype.Date {
+	if due, ok := rule.OnOrAfter(rule.Start); ok {
+		return recurrence.Date(due)
+	}
+	return pgtype.Date{}
+}
+
+// Refresh recomputes b's next due date as the first occurrence after its
+// latest payment, e.g. after its schedule changed or a payment was removed.
+func Refresh(ctx context.Context, q *db.Queries, b db.Bill) (db.Bill, error) {
+	rule := Rule(b)
+	next := FirstDue(rule)
+	latest, err := q.GetLatestBillPayment(ctx, b.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return db.Bill{}, fmt.Errorf("failed to get latest bill payment: %w", err)
+	}
+	if err == nil {
+		next = pgtype.Date{}
+		if due, ok := rule.Next(latest.DueDate.Time); ok {
+			next = recurrence.Date(due)
+		}
+	}
+	b, err = q.SetBillNextDueDate(ctx, db.SetBillNextDueDateParams{ID: b.ID, NextDueDate: next})
+	if err != nil {
+		return db.Bill{}, fmt.Errorf("failed to set bill due date: %w", err)
+	}
+	return b, nil
+}
+
+// Pay records b's next due occurrence as paid, by transactionID if it is
+// valid, and moves the bill on to its following occurrence.
+func Pay(ctx context.Context, q *db.Queries, b db.Bill, transactionID pgtype.UUID) (db.BillPayment, db.Bill, error) {
+	if !b.NextDueDate.Valid {
+		return db.BillPayment{}, db.Bill{}, ErrNothingDue
+	}
+	payment, err := q.CreateBillPayment(ctx, db.CreateBillPaymentParams{
+		ID:            utils.NewUUID(),
+		BillID:        b.ID,
+		UserID:        b.UserID,
+		DueDate:       b.NextDueDate,
+		TransactionID: transactionID,
+	})
+	if err != nil {
+		return db.BillPayment{}, db.Bill{}, err
+	}
+	next := pgtype.Date{}
+	if due, ok := Rule(b).Next(b.NextDueDate.Time); ok {
+		next = recurrence.Date(due)
+	}
+	b, err = q.SetBillNextDueDate(ctx, db.SetBillNextDueDateParams{ID: b.ID, NextDueDate: next})
+	if err != nil {
+		return db.BillPayment{}, db.Bill{}, fmt.Errorf("failed to advance bill: %w", err)
+	}
+	return payment, b, nil
+}
+
+// Match looks for an outstanding bill that expense t pays and, if it finds
+// one, records the payment. A bill matches when t is dated near the bill's
+// due date, its amount is close to the bill's, and it has the bill's payee,
+// or failing that its category, or failing both mentions the bill's name.
+// The earliest due bill wins. t's date is taken in the user's time zone, as
+// due dates are.
+func Match(ctx context.Context, q *db.Queries, t db.Transaction) (*db.BillPayment, error) {
+	if t.Type != db.TransactionTypeExpense {
+		return nil, nil
+	}
+	user, err := q.GetUser(ctx, t.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	from, to := matchWindow(t.Date.Time, loc)
+	candidates, err := q.ListBillsDueBetween(ctx, db.ListBillsDueBetweenParams{
+		UserID:   t.UserID,
+		FromDate: recurrence.Date(from),
+		ToDate:   recurrence.Date(to),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bills: %w", err)
+	}
+	for _, b := range candidates {
+		if !pays(t, b) {
+			continue
+		}
+		payment, _, err := Pay(ctx, q, b, t.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &payment, nil
+	}
+	return nil, nil
+}
+
+// matchWindow is the range of due dates a payment made at paidAt can
+// settle, with paidAt's date taken in loc.
+func matchWindow(paidAt time.Time, loc *time.Location) (from, to time.Time) {
+	date := recurrence.Day(paidAt.In(loc))
+	return date.AddDate(0, 0, -matchDaysLate), date.AddDate(0, 0, matchDaysEarly)
+}
+
+func pays(t db.Transaction, b db.Bill) bool {
+	switch {
+	case b.PayeeID.Valid:
+		if t.PayeeID != b.PayeeID {
+			return false
+		}
+	case b.CategoryID.Valid:
+		if t.CategoryID != b.CategoryID {
+			return false
+		}
+	default:
+		if !strings.Contains(strings.ToLower(t.Description.String), strings.ToLower(b.Name)) {
+			return false
+		}
+	}
+	// |paid - amount| * 100 <= amount * tolerance
+	amount := b.Amount.Rat()
+	diff := new(big.Rat).Sub(t.BaseAmount.Rat(), amount)
+	diff.Abs(diff).Mul(diff, big.NewRat(100, 1))
+	return diff.Cmp(new(big.Rat).Mul(amount, big.NewRat(matchTolerancePercent, 1))) <= 0
+}
+
+// Unmatch removes the bill payment made by the transaction, if any, so the
+// occurrence it paid is outstanding again. Call it before the transaction is
+// changed or deleted.
+func Unmatch(ctx context.Context, q *db.Queries, transactionID pgtype.UUID) error {
+	payment, err := q.DeleteBillPaymentByTransaction(ctx, transactionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove bill payment: %w", err)
+	}
+	b, err := q.GetBill(ctx, db.GetBillParams{ID: payment.BillID, UserID: payment.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to get bill: %w", err)
+	}
+	_, err = Refresh(ctx, q, b)
+	return err
+}
+
+// Occurrence is one due date of a bill.
+type Occurrence struct {
+	BillID       pgtype.UUID   `json:"billId"`
+	Name         string        `json:"name"`
+	Amount       money.Decimal `json:"amount"`
+	CategoryID   pgtype.UUID   `json:"categoryId"`
+	PayeeID      pgtype.UUID   `json:"payeeId"`
+	AccountID    pgtype.UUID   `json:"accountId"`
+	DueDate      pgtype.Date   `json:"dueDate"`
+	DaysUntilDue int           `json:"daysUntilDue"`
+}
+
+// Upcoming splits the unpaid occurrences of bills due up to until into
+// those overdue at today and those still to come, each in date order. Bills
+// repeating more often than the window lists every occurrence in it. Only
+// the earliest MaxOccurrences are returned; truncated reports whether any
+// were left out.
+func Upcoming(bills []db.Bill, today, until time.Time) (overdue, upcoming []Occurrence, truncated bool) {
+	today, until = recurrence.Day(today), recurrence.Day(until)
+	for _, b := range bills {
+		if !b.NextDueDate.Valid {
+			continue
+		}
+		rule := Rule(b)
+		n := 0
+		for due, ok := recurrence.Day(b.NextDueDate.Time), true; ok && !due.After(until); due, ok = rule.Next(due) {
+			// No bill's occurrences past its first MaxOccurrences can make
+			// the cut.
+			if n == MaxOccurrences {
+				truncated = true
+				break
+			}
+			n++
+			o := Occurrence{
+				BillID:       b.ID,
+				Name:         b.Name,
+				Amount:       b.Amount,
+				CategoryID:   b.CategoryID,
+				PayeeID:      b.PayeeID,
+				AccountID:    b.AccountID,
+				DueDate:      recurrence.Date(due),
+				DaysUntilDue: int(due.Sub(today).Hours() / 24),
+			}
+			if due.Before(today) {
+				overdue = append(overdue, o)
+			} else {
+				upcoming = append(upcoming, o)
+			}
+		}
+	}
+	byDate := func(s []Occurrence) {
+		slices.SortStableFunc(s, func(a, b Occurrence) int { return a.DueDate.Time.Compare(b.DueDate.Time) })
+	}
+	byDate(overdue)
+	byDate(upcoming)
+	if len(overdue) > MaxOccurrences {
+		overdue, truncated = overdue[:MaxOccurrences], true
+	}
+	if room := MaxOccurrences - len(overdue); len(upcoming) > room {
+		upcoming, truncated = upcoming[:room], true
+	}
+	return overdue, upcoming, truncated
+}
@@ -0,0 +1,178 @@
+package bill
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+)
+
+func id(b byte) pgtype.UUID {
+	return pgtype.UUID{Bytes: [16]byte{b}, Valid: true}
+}
+
+func day(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func amount(s string) money.Decimal {
+	d, err := money.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestMatchWindow(t *testing.T) {
+	eat := time.FixedZone("EAT", 3*60*60)
+	tests := []struct {
+		name     string
+		paidAt   time.Time
+		loc      *time.Location
+		from, to string
+	}{
+		{"UTC", time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), time.UTC, "2026-02-15", "2026-03-08"},
+		// 22:30 UTC on 1 March is already 2 March in Nairobi.
+		{"next day in the user's zone", time.Date(2026, 3, 1, 22, 30, 0, 0, time.UTC), eat, "2026-02-16", "2026-03-09"},
+		{"same day in the user's zone", time.Date(2026, 3, 1, 20, 59, 0, 0, time.UTC), eat, "2026-02-15", "2026-03-08"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := matchWindow(tt.paidAt, tt.loc)
+			if got := from.Format(time.DateOnly); got != tt.from {
+				t.Errorf("from = %s, want %s", got, tt.from)
+			}
+			if got := to.Format(time.DateOnly); got != tt.to {
+				t.Errorf("to = %s, want %s", got, tt.to)
+			}
+		})
+	}
+}
+
+func TestPays(t *testing.T) {
+	byPayee := db.Bill{Name: "Rent", Amount: amount("1000"), PayeeID: id(1), CategoryID: id(2)}
+	byCategory := db.Bill{Name: "Rent", Amount: amount("1000"), CategoryID: id(2)}
+	byName := db.Bill{Name: "Netflix", Amount: amount("1000")}
+	expense := func(base string, payee, category pgtype.UUID, description string) db.Transaction {
+		return db.Transaction{
+			Type:        db.TransactionTypeExpense,
+			Amount:      amount("7.50"),
+			BaseAmount:  amount(base),
+			PayeeID:     payee,
+			CategoryID:  category,
+			Description: pgtype.Text{String: description, Valid: description != ""},
+		}
+	}
+	tests := []struct {
+		name string
+		bill db.Bill
+		t    db.Transaction
+		want bool
+	}{
+		{"exact", byPayee, expense("1000", id(1), pgtype.UUID{}, ""), true},
+		{"20% over", byPayee, expense("1200", id(1), pgtype.UUID{}, ""), true},
+		{"20% under", byPayee, expense("800", id(1), pgtype.UUID{}, ""), true},
+		{"just over 20% over", byPayee, expense("1200.01", id(1), pgtype.UUID{}, ""), false},
+		{"just over 20% under", byPayee, expense("799.99", id(1), pgtype.UUID{}, ""), false},
+		{"other payee", byPayee, expense("1000", id(9), id(2), "Rent"), false},
+		{"payee beats category", byPayee, expense("1000", pgtype.UUID{}, id(2), ""), false},
+		{"category", byCategory, expense("1000", id(9), id(2), ""), true},
+		{"other category", byCategory, expense("1000", pgtype.UUID{}, id(9), "Rent"), false},
+		{"name in the description", byName, expense("1000", id(9), id(9), "netflix subscription"), true},
+		{"name missing", byName, expense("1000", pgtype.UUID{}, pgtype.UUID{}, "spotify"), false},
+		{"no description", byName, expense("1000", pgtype.UUID{}, pgtype.UUID{}, ""), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pays(tt.t, tt.bill); got != tt.want {
+				t.Errorf("pays = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newBill(b byte, frequency db.RecurrenceFrequency, start, next string) db.Bill {
+	return db.Bill{
+		ID:            id(b),
+		Name:          string('A' + b - 1),
+		Amount:        amount("100"),
+		Frequency:     frequency,
+		IntervalCount: 1,
+		StartDate:     recurrence.Date(day(start)),
+		NextDueDate:   recurrence.Date(day(next)),
+	}
+}
+
+func TestUpcoming(t *testing.T) {
+	paidUp := newBill(4, db.RecurrenceFrequencyOnce, "2026-01-01", "2026-01-01")
+	paidUp.NextDueDate = pgtype.Date{}
+	bills := []db.Bill{
+		newBill(1, db.RecurrenceFrequencyMonthly, "2026-01-31", "2026-02-28"),
+		newBill(2, db.RecurrenceFrequencyWeekly, "2026-03-02", "2026-03-09"),
+		newBill(3, db.RecurrenceFrequencyOnce, "2026-04-20", "2026-04-20"),
+		paidUp,
+	}
+	overdue, upcoming, truncated := Upcoming(bills, time.Date(2026, 3, 20, 15, 0, 0, 0, time.UTC), day("2026-04-05"))
+	if truncated {
+		t.Error("truncated = true")
+	}
+	type occ struct {
+		name string
+		due  string
+		days int
+	}
+	list := func(os []Occurrence) []occ {
+		var out []occ
+		for _, o := range os {
+			out = append(out, occ{o.Name, o.DueDate.Time.Format(time.DateOnly), o.DaysUntilDue})
+		}
+		return out
+	}
+	wantOverdue := []occ{{"A", "2026-02-28", -20}, {"B", "2026-03-09", -11}, {"B", "2026-03-16", -4}}
+	wantUpcoming := []occ{{"B", "2026-03-23", 3}, {"B", "2026-03-30", 10}, {"A", "2026-03-31", 11}}
+	if got := list(overdue); !slices.Equal(got, wantOverdue) {
+		t.Errorf("overdue = %v, want %v", got, wantOverdue)
+	}
+	if got := list(upcoming); !slices.Equal(got, wantUpcoming) {
+		t.Errorf("upcoming = %v, want %v", got, wantUpcoming)
+	}
+}
+
+func TestUpcomingCap(t *testing.T) {
+	// Unpaid daily for years: thousands of occurrences overdue.
+	daily := newBill(1, db.RecurrenceFrequencyDaily, "2020-01-01", "2020-01-01")
+	today := day("2026-03-20")
+
+	overdue, upcoming, truncated := Upcoming([]db.Bill{daily}, today, today.AddDate(0, 0, 30))
+	if !truncated {
+		t.Error("truncated = false")
+	}
+	if len(overdue) != MaxOccurrences || len(upcoming) != 0 {
+		t.Fatalf("got %d overdue and %d upcoming, want %d overdue only", len(overdue), len(upcoming), MaxOccurrences)
+	}
+	if first, last := overdue[0].DueDate.Time, overdue[MaxOccurrences-1].DueDate.Time; !first.Equal(day("2020-01-01")) || !last.Equal(day("2020-01-01").AddDate(0, 0, MaxOccurrences-1)) {
+		t.Errorf("overdue runs %s to %s, want the earliest", first.Format(time.DateOnly), last.Format(time.DateOnly))
+	}
+
+	// The cap covers both lists together, overdue first.
+	recent := newBill(1, db.RecurrenceFrequencyDaily, "2026-01-01", "2026-03-10")
+	overdue, upcoming, truncated = Upcoming([]db.Bill{recent}, today, today.AddDate(10, 0, 0))
+	if !truncated || len(overdue) != 10 || len(overdue)+len(upcoming) != MaxOccurrences {
+		t.Errorf("got %d overdue and %d upcoming (truncated %v), want 10 and %d", len(overdue), len(upcoming), truncated, MaxOccurrences-10)
+	}
+
+	// Exactly MaxOccurrences is not truncated.
+	exact := newBill(1, db.RecurrenceFrequencyDaily, "2026-03-20", "2026-03-20")
+	_, upcoming, truncated = Upcoming([]db.Bill{exact}, today, today.AddDate(0, 0, MaxOccurrences-1))
+	if truncated || len(upcoming) != MaxOccurrences {
+		t.Errorf("got %d upcoming (truncated %v), want %d untruncated", len(upcoming), truncated, MaxOccurrences)
+	}
+}
@@ -0,0 +1,128 @@
+package bill
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/budget"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/notify"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+	"go.uber.org/zap"
+)
+
+// ReminderJob raises a warning notification once per occurrence of a bill,
+// remind_days_before days ahead of its due date in the user's time zone.
+// The bill is marked reminded in the same transaction that creates the
+// notification, so replicas do not remind twice.
+type ReminderJob struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	notify  *notify.Dispatcher
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewReminderJob(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, dispatcher *notify.Dispatcher) *ReminderJob {
+	return &ReminderJob{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		notify:  dispatcher,
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+// Run checks for due reminders every interval until ctx is cancelled.
+func (j *ReminderJob) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.remindDue(ctx)
+		}
+	}
+}
+
+func (j *ReminderJob) remindDue(ctx context.Context) {
+	bills, err := j.queries.ListBillsToRemind(ctx)
+	if err != nil {
+		j.logger.Error("Failed to list bills to remind", zap.Error(err))
+		return
+	}
+	users := make(map[[16]byte]db.User)
+	for _, b := range bills {
+		user, ok := users[b.UserID.Bytes]
+		if !ok {
+			if user, err = j.queries.GetUser(ctx, b.UserID); err != nil {
+				j.logger.Error("Failed to get user", zap.String("user_id", b.UserID.String()), zap.Error(err))
+				continue
+			}
+			users[b.UserID.Bytes] = user
+		}
+		if err := j.remind(ctx, user, b, time.Now()); err != nil {
+			j.logger.Error("Failed to send bill reminder", zap.String("bill_id", b.ID.String()), zap.Error(err))
+		}
+	}
+}
+
+// remind notifies user about b's next due date if, at now, it is within
+// the bill's reminder window.
+func (j *ReminderJob) remind(ctx context.Context, user db.User, b db.Bill, now time.Time) error {
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	today := recurrence.Day(now.In(loc))
+	due := recurrence.Day(b.NextDueDate.Time)
+	if today.Before(due.AddDate(0, 0, -int(b.RemindDaysBefore))) {
+		return nil
+	}
+
+	var n *db.Notification
+	err = pgx.BeginFunc(ctx, j.dbPool, func(tx pgx.Tx) error {
+		qtx := j.queries.WithTx(tx)
+		claimed, err := qtx.MarkBillReminded(ctx, db.MarkBillRemindedParams{ID: b.ID, NextDueDate: b.NextDueDate})
+		if err != nil {
+			return fmt.Errorf("failed to mark bill reminded: %w", err)
+		}
+		if claimed == 0 {
+			return nil
+		}
+		created, err := notify.Create(ctx, qtx, reminderMessage(user, b, int(due.Sub(today).Hours()/24)))
+		if err != nil {
+			return err
+		}
+		n = &created
+		return nil
+	})
+	if err != nil || n == nil {
+		return err
+	}
+	j.notify.Dispatch(ctx, *n)
+	return nil
+}
+
+func reminderMessage(user db.User, b db.Bill, days int) notify.Message {
+	msg := notify.Message{UserID: user.ID, Type: db.NotificationTypeWarning}
+	switch {
+	case days < 0:
+		msg.Title = fmt.Sprintf("%s is overdue", b.Name)
+	case days == 0:
+		msg.Title = fmt.Sprintf("%s due today", b.Name)
+	case days == 1:
+		msg.Title = fmt.Sprintf("%s due tomorrow", b.Name)
+	default:
+		msg.Title = fmt.Sprintf("%s due in %d days", b.Name, days)
+	}
+	msg.Message = fmt.Sprintf("Your %s bill of %s%s is due on %s.",
+		b.Name, user.CurrencySymbol, budget.FormatAmount(b.Amount.Rat()), b.NextDueDate.Time.Format("Mon 2 Jan 2006"))
+	return msg
+}
@@ -0,0 +1,191 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/notify"
+)
+
+// Status is a category's budget position for a period after evaluation.
+type Status struct {
+	CategoryID  pgtype.UUID   `json:"categoryId"`
+	PeriodStart pgtype.Date   `json:"periodStart"`
+	PeriodEnd   pgtype.Date   `json:"periodEnd"`
+	Spent       money.Decimal `json:"spent"`
+	BudgetLimit money.Decimal `json:"budgetLimit"`
+	PercentUsed float64       `json:"percentUsed"`
+	// Thresholds lists the configured thresholds currently crossed.
+	Thresholds []int `json:"thresholds"`
+	// Notification is the alert raised by this evaluation, if any.
+	Notification *db.Notification `json:"-"`
+}
+
+// Location returns the user's time zone, which budget periods follow, falling
+// back to UTC when it is not a known zone.
+func Location(ctx context.Context, q *db.Queries, userID pgtype.UUID) (*time.Location, error) {
+	user, err := q.GetUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// EvaluateCategory compares a category's spending in the budget period
+// containing at, a month in loc (see Location), against thresholds (percentages of its budget limit).
+//
+// Each threshold notifies at most once per period: crossing it records a
+// budget_alerts row, and only newly crossed thresholds raise a notification
+// (the highest one, when several are crossed at once). Thresholds that are no
+// longer crossed, e.g. after an edit or delete, have their record removed so
+// they can fire again if spending climbs back up.
+//
+// Run it with transaction-scoped queries after any change to the category's
+// expenses so the alert state commits with the change. The returned status is
+// nil for categories without a budget.
+func EvaluateCategory(ctx context.Context, q *db.Queries, userID, categoryID pgtype.UUID, at time.Time, loc *time.Location, thresholds []int) (*Status, error) {
+	if !categoryID.Valid {
+		return nil, nil
+	}
+	category, err := q.GetCategory(ctx, db.GetCategoryParams{ID: categoryID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	period := MonthOf(at, loc)
+	periodStart := pgtype.Date{Time: period.Start, Valid: true}
+	limit := category.BudgetLimit.Rat()
+	if category.Type != db.TransactionTypeExpense || limit.Sign() <= 0 {
+		return nil, clearAlerts(ctx, q, categoryID, periodStart, nil)
+	}
+
+	spent, err := q.GetCategorySpend(ctx, db.GetCategorySpendParams{
+		UserID:      userID,
+		CategoryID:  categoryID,
+		PeriodStart: pgtype.Timestamptz{Time: period.Start, Valid: true},
+		PeriodEnd:   pgtype.Timestamptz{Time: period.End, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum category spending: %w", err)
+	}
+	spentRat := spent.Rat()
+	percent, _ := new(big.Rat).Quo(new(big.Rat).Mul(spentRat, big.NewRat(100, 1)), limit).Float64()
+	status := &Status{
+		CategoryID:  categoryID,
+		PeriodStart: periodStart,
+		PeriodEnd:   pgtype.Date{Time: period.End, Valid: true},
+		Spent:       spent,
+		BudgetLimit: category.BudgetLimit,
+		PercentUsed: math.Round(percent*10) / 10,
+		Thresholds:  []int{},
+	}
+
+	crossed := make(map[int32]bool)
+	var fresh []int
+	for _, t := range thresholds {
+		// spent/limit >= t/100, kept exact by cross-multiplying.
+		lhs := new(big.Rat).Mul(spentRat, big.NewRat(100, 1))
+		rhs := new(big.Rat).Mul(limit, big.NewRat(int64(t), 1))
+		if lhs.Cmp(rhs) < 0 {
+			continue
+		}
+		crossed[int32(t)] = true
+		status.Thresholds = append(status.Thresholds, t)
+		inserted, err := q.CreateBudgetAlert(ctx, db.CreateBudgetAlertParams{
+			UserID:      userID,
+			CategoryID:  categoryID,
+			PeriodStart: periodStart,
+			Threshold:   int32(t),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record budget alert: %w", err)
+		}
+		if inserted > 0 {
+			fresh = append(fresh, t)
+		}
+	}
+	if err := clearAlerts(ctx, q, categoryID, periodStart, crossed); err != nil {
+		return nil, err
+	}
+	sort.Ints(status.Thresholds)
+	if len(fresh) == 0 {
+		return status, nil
+	}
+
+	sort.Ints(fresh)
+	threshold := fresh[len(fresh)-1]
+	msg, err := alertMessage(ctx, q, category, period, threshold, spentRat, limit)
+	if err != nil {
+		return nil, err
+	}
+	n, err := notify.Create(ctx, q, msg)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.SetBudgetAlertNotification(ctx, db.SetBudgetAlertNotificationParams{
+		CategoryID:     categoryID,
+		PeriodStart:    periodStart,
+		Threshold:      int32(threshold),
+		NotificationID: n.ID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to link budget alert notification: %w", err)
+	}
+	status.Notification = &n
+	return status, nil
+}
+
+// clearAlerts removes alert records for the period whose threshold is not in keep.
+func clearAlerts(ctx context.Context, q *db.Queries, categoryID pgtype.UUID, periodStart pgtype.Date, keep map[int32]bool) error {
+	alerts, err := q.ListBudgetAlerts(ctx, db.ListBudgetAlertsParams{CategoryID: categoryID, PeriodStart: periodStart})
+	if err != nil {
+		return fmt.Errorf("failed to list budget alerts: %w", err)
+	}
+	for _, a := range alerts {
+		if keep[a.Threshold] {
+			continue
+		}
+		if err := q.DeleteBudgetAlert(ctx, db.DeleteBudgetAlertParams{
+			CategoryID:  categoryID,
+			PeriodStart: periodStart,
+			Threshold:   a.Threshold,
+		}); err != nil {
+			return fmt.Errorf("failed to clear budget alert: %w", err)
+		}
+	}
+	return nil
+}
+
+func alertMessage(ctx context.Context, q *db.Queries, category db.Category, period Period, threshold int, spent, limit *big.Rat) (notify.Message, error) {
+	user, err := q.GetUser(ctx, category.UserID)
+	if err != nil {
+		return notify.Message{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	symbol := user.CurrencySymbol
+	msg := notify.Message{UserID: category.UserID}
+	if threshold >= 100 {
+		msg.Type = db.NotificationTypeAlert
+		msg.Title = fmt.Sprintf("%s budget exceeded", category.Name)
+	} else {
+		msg.Type = db.NotificationTypeWarning
+		msg.Title = fmt.Sprintf("%s budget at %d%%", category.Name, threshold)
+	}
+	msg.Message = fmt.Sprintf("You have spent %s%s of your %s%s %s budget for %s.",
+		symbol, FormatAmount(spent), symbol, FormatAmount(limit), category.Name, period.Label())
+	return msg, nil
+}
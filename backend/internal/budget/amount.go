@@ -0,0 +1,24 @@
+package budget
+
+import (
+	"math/big"
+	"strings"
+)
+
+// FormatAmount renders r with two decimals and thousands separators, e.g. 12,500.00.
+func FormatAmount(r *big.Rat) string {
+	s := r.FloatString(2)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return sign + b.String() + "." + frac
+}
@@ -0,0 +1,57 @@
+// Package budget evaluates spending against category budgets.
+package budget
+
+import (
+	"time"
+)
+
+// Period is a half-open budget period [Start, End).
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// MonthOf returns the calendar month containing t, in loc.
+func MonthOf(t time.Time, loc *time.Location) Period {
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	return Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// WeekOf returns the Monday-to-Sunday week containing t, in loc.
+func WeekOf(t time.Time, loc *time.Location) Period {
+	t = t.In(loc)
+	offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+	start := time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, loc)
+	return Period{Start: start, End: start.AddDate(0, 0, 7)}
+}
+
+// Label is a human readable name for the period, e.g. "October 2026".
+func (p Period) Label() string {
+	return p.Start.Format("January 2006")
+}
+
+// YearOf returns the calendar year containing t, in loc.
+func YearOf(t time.Time, loc *time.Location) Period {
+	t = t.In(loc)
+	start := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, loc)
+	return Period{Start: start, End: start.AddDate(1, 0, 0)}
+}
+
+// CycleOf returns the month-long cycle containing t, in loc, for cycles that
+// start on day startDay (1-28) of each month, such as a pay cycle.
+func CycleOf(t time.Time, loc *time.Location, startDay int) Period {
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), startDay, 0, 0, 0, 0, loc)
+	if t.Day() < startDay {
+		start = start.AddDate(0, -1, 0)
+	}
+	return Period{Start: start, End: start.AddDate(0, 1, 0)}
+}
+
+// DayOf returns the day containing t, in loc.
+func DayOf(t time.Time, loc *time.Location) Period {
+	t = t.In(loc)
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	return Period{Start: start, End: start.AddDate(0, 0, 1)}
+}
@@ -0,0 +1,159 @@
+// Package categorizer suggests categories for transactions using a small
+// multinomial naive Bayes model trained on each user's own categorized history.
+// The model lives in Postgres as token counts, so it is updated incrementally
+// inside the same database transaction as the change that trains it.
+package categorizer
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+)
+
+const (
+	// DefaultSuggestions is the number of suggestions returned when the caller does not ask for a specific count.
+	DefaultSuggestions = 3
+	// MaxSuggestions caps the number of suggestions a caller may ask for.
+	MaxSuggestions = 10
+
+	maxTokenLength = 64
+	amountPrefix   = "amount:"
+)
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "from": true, "to": true, "of": true,
+	"at": true, "on": true, "in": true, "by": true, "via": true, "ltd": true,
+	"payment": true, "paid": true, "ref": true,
+}
+
+// Suggestion is a candidate category with the model's confidence in it.
+type Suggestion struct {
+	CategoryID pgtype.UUID        `json:"categoryId"`
+	Name       string             `json:"name"`
+	Type       db.TransactionType `json:"type"`
+	Confidence float64            `json:"confidence"`
+}
+
+// Tokenize turns a transaction description and amount into model features.
+// Words are lower-cased and stripped of digits-only fragments such as till or
+// branch numbers, and the amount is reduced to a coarse logarithmic bucket so
+// that "rent 25000" and "rent 26000" share a feature.
+func Tokenize(description string, amount float64) []string {
+	var tokens []string
+	fields := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, f := range fields {
+		if utf8.RuneCountInString(f) < 2 || stopWords[f] || isDigits(f) {
+			continue
+		}
+		if utf8.RuneCountInString(f) > maxTokenLength {
+			f = string([]rune(f)[:maxTokenLength])
+		}
+		tokens = append(tokens, f)
+	}
+	if bucket, ok := AmountBucket(amount); ok {
+		tokens = append(tokens, bucket)
+	}
+	return tokens
+}
+
+// AmountBucket returns the feature for an amount: half-decades on a log scale
+// (1-3, 3-10, 10-31, 31-100, ...).
+func AmountBucket(amount float64) (string, bool) {
+	amount = math.Abs(amount)
+	if amount < 1 || math.IsInf(amount, 0) || math.IsNaN(amount) {
+		return "", false
+	}
+	return amountPrefix + strconv.Itoa(int(math.Floor(2*math.Log10(amount)))), true
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ClassStats is what the model knows about one category.
+type ClassStats struct {
+	CategoryID pgtype.UUID
+	Name       string
+	Type       db.TransactionType
+	DocCount   int
+	TokenCount int
+	// Tokens holds the counts of the queried tokens only.
+	Tokens map[string]int
+}
+
+// Model is a trained snapshot restricted to the tokens being classified.
+type Model struct {
+	Classes    []ClassStats
+	Vocabulary int
+}
+
+// Predict scores each category for the given tokens and returns the best n,
+// with confidences normalised to sum to one across all candidate categories.
+// When txType is set, only categories of that type are considered.
+func (m Model) Predict(tokens []string, txType db.TransactionType, n int) []Suggestion {
+	var candidates []ClassStats
+	totalDocs := 0
+	for _, c := range m.Classes {
+		if txType != "" && c.Type != txType {
+			continue
+		}
+		candidates = append(candidates, c)
+		totalDocs += c.DocCount
+	}
+	if len(candidates) == 0 || totalDocs == 0 {
+		return nil
+	}
+
+	vocab := float64(m.Vocabulary)
+	if vocab < 1 {
+		vocab = 1
+	}
+	scores := make([]float64, len(candidates))
+	best := math.Inf(-1)
+	for i, c := range candidates {
+		score := math.Log(float64(c.DocCount+1) / float64(totalDocs+len(candidates)))
+		for _, t := range tokens {
+			score += math.Log(float64(c.Tokens[t]+1) / (float64(c.TokenCount) + vocab))
+		}
+		scores[i] = score
+		if score > best {
+			best = score
+		}
+	}
+
+	// Softmax, shifted by the best score to stay clear of underflow.
+	sum := 0.0
+	for i := range scores {
+		scores[i] = math.Exp(scores[i] - best)
+		sum += scores[i]
+	}
+	suggestions := make([]Suggestion, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = Suggestion{
+			CategoryID: c.CategoryID,
+			Name:       c.Name,
+			Type:       c.Type,
+			Confidence: math.Round(scores[i]/sum*1000) / 1000,
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Confidence > suggestions[j].Confidence
+	})
+	if n > 0 && len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+	return suggestions
+}
@@ -0,0 +1,34 @@
+package categorizer
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Lunch at the Java House 0712", 0)
+	want := []string{"lunch", "java", "house"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Tokenize = %q, want %q", got, want)
+	}
+}
+
+func TestTokenizeRunes(t *testing.T) {
+	// Single-rune words are dropped however many bytes they take.
+	if got := Tokenize("é ü", 0); len(got) != 0 {
+		t.Errorf("Tokenize kept single-rune words: %q", got)
+	}
+	if got := Tokenize("café", 0); len(got) != 1 || got[0] != "café" {
+		t.Errorf("Tokenize(café) = %q", got)
+	}
+
+	long := strings.Repeat("é", maxTokenLength+10)
+	got := Tokenize(long, 0)
+	if len(got) != 1 {
+		t.Fatalf("Tokenize(long) = %q", got)
+	}
+	if !utf8.ValidString(got[0]) || utf8.RuneCountInString(got[0]) != maxTokenLength {
+		t.Errorf("long word truncated to %d runes, valid UTF-8 %v", utf8.RuneCountInString(got[0]), utf8.ValidString(got[0]))
+	}
+}
@@ -0,0 +1,133 @@
+package categorizer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+)
+
+// TransactionTokens returns the model features for a stored transaction.
+func TransactionTokens(t db.Transaction) []string {
+	return Tokenize(t.Description.String, t.BaseAmount.Float64())
+}
+
+// Learn adds a categorized transaction to the user's model. Uncategorized
+// transactions are ignored. Pass a transaction-scoped *db.Queries so the model
+// stays consistent with the transactions table.
+func Learn(ctx context.Context, q *db.Queries, t db.Transaction) error {
+	return train(ctx, q, t, 1)
+}
+
+// Forget removes a previously learned transaction from the user's model.
+func Forget(ctx context.Context, q *db.Queries, t db.Transaction) error {
+	if err := train(ctx, q, t, -1); err != nil {
+		return err
+	}
+	if err := q.PruneCategoryModelTokens(ctx, t.UserID); err != nil {
+		return fmt.Errorf("failed to prune category model tokens: %w", err)
+	}
+	if err := q.PruneCategoryModelDocs(ctx, t.UserID); err != nil {
+		return fmt.Errorf("failed to prune category model docs: %w", err)
+	}
+	return nil
+}
+
+func train(ctx context.Context, q *db.Queries, t db.Transaction, delta int32) error {
+	if !t.CategoryID.Valid {
+		return nil
+	}
+	tokens := TransactionTokens(t)
+	if len(tokens) > 0 {
+		if err := q.AddCategoryModelTokens(ctx, db.AddCategoryModelTokensParams{
+			UserID:     t.UserID,
+			CategoryID: t.CategoryID,
+			Delta:      delta,
+			Tokens:     tokens,
+		}); err != nil {
+			return fmt.Errorf("failed to update category model tokens: %w", err)
+		}
+	}
+	if err := q.AddCategoryModelDoc(ctx, db.AddCategoryModelDocParams{
+		UserID:     t.UserID,
+		CategoryID: t.CategoryID,
+		DocCount:   delta,
+		TokenCount: delta * int32(len(tokens)),
+	}); err != nil {
+		return fmt.Errorf("failed to update category model docs: %w", err)
+	}
+	return nil
+}
+
+// Rebuild discards the user's model and retrains it from their full
+// categorized history.
+func Rebuild(ctx context.Context, q *db.Queries, userID pgtype.UUID) (int, error) {
+	if err := q.DeleteCategoryModelTokens(ctx, userID); err != nil {
+		return 0, fmt.Errorf("failed to reset category model tokens: %w", err)
+	}
+	if err := q.DeleteCategoryModelDocs(ctx, userID); err != nil {
+		return 0, fmt.Errorf("failed to reset category model docs: %w", err)
+	}
+	transactions, err := q.ListCategorizedTransactions(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list categorized transactions: %w", err)
+	}
+	for _, t := range transactions {
+		if err := Learn(ctx, q, t); err != nil {
+			return 0, err
+		}
+	}
+	return len(transactions), nil
+}
+
+// Suggest returns up to n category suggestions for a description and amount.
+func Suggest(ctx context.Context, q *db.Queries, userID pgtype.UUID, description string, amount float64, txType db.TransactionType, n int) ([]Suggestion, error) {
+	tokens := Tokenize(description, amount)
+	model, err := loadModel(ctx, q, userID, tokens)
+	if err != nil {
+		return nil, err
+	}
+	return model.Predict(tokens, txType, n), nil
+}
+
+func loadModel(ctx context.Context, q *db.Queries, userID pgtype.UUID, tokens []string) (Model, error) {
+	docs, err := q.ListCategoryModelDocs(ctx, userID)
+	if err != nil {
+		return Model{}, fmt.Errorf("failed to load category model: %w", err)
+	}
+	if len(docs) == 0 {
+		return Model{}, nil
+	}
+	vocab, err := q.CountCategoryModelVocabulary(ctx, userID)
+	if err != nil {
+		return Model{}, fmt.Errorf("failed to count category model vocabulary: %w", err)
+	}
+	counts, err := q.ListCategoryModelTokenCounts(ctx, db.ListCategoryModelTokenCountsParams{
+		UserID: userID,
+		Tokens: tokens,
+	})
+	if err != nil {
+		return Model{}, fmt.Errorf("failed to load category model tokens: %w", err)
+	}
+
+	byCategory := make(map[pgtype.UUID]map[string]int, len(docs))
+	for _, c := range counts {
+		if byCategory[c.CategoryID] == nil {
+			byCategory[c.CategoryID] = make(map[string]int)
+		}
+		byCategory[c.CategoryID][c.Token] = int(c.Count)
+	}
+	model := Model{Vocabulary: int(vocab)}
+	for _, d := range docs {
+		model.Classes = append(model.Classes, ClassStats{
+			CategoryID: d.CategoryID,
+			Name:       d.Name,
+			Type:       d.Type,
+			DocCount:   int(d.DocCount),
+			TokenCount: int(d.TokenCount),
+			Tokens:     byCategory[d.CategoryID],
+		})
+	}
+	return model, nil
+}
@@ -15,6 +15,9 @@ type Config struct {
 	JWT            JWTConfig
 	Storage        StorageConfig
 	Redis          RedisConfig
+	Email          EmailConfig
+	SMS            SMSConfig
+	FX             FXConfig
 	App            AppConfig
 	MigrationsPath string
 	AutoMigrate    bool
@@ -51,24 +54,60 @@ type JWTConfig struct {
 }
 
 type StorageConfig struct {
-	Provider     string
-	LocalPath    string
-	AWSRegion    string
-	AWSBucket    string
-	AWSAccessKey string
-	AWSSecretKey string
-	AWSEndpoint  string
+	Provider       string
+	LocalPath      string
+	AWSRegion      string
+	AWSBucket      string
+	AWSAccessKey   string
+	AWSSecretKey   string
+	AWSEndpoint    string
+	MaxUploadBytes int64
+	UserQuotaBytes int64
 }
 
 type RedisConfig struct {
 	URL      string
 	Password string
 	CacheTTL time.Duration
+	PubSub   bool
+}
+
+type EmailConfig struct {
+	Provider     string
+	BrevoAPIKey  string
+	FromEmail    string
+	FromName     string
+	SupportEmail string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	OutputDir    string
+}
+
+type SMSConfig struct {
+	Provider               string
+	TwilioAccountSID       string
+	TwilioAuthToken        string
+	TwilioFromNumber       string
+	AfricasTalkingUsername string
+	AfricasTalkingAPIKey   string
+	AfricasTalkingSenderID string
+}
+
+type FXConfig struct {
+	Provider  string
+	RatesFile string
+	HTTPURL   string
 }
 
 type AppConfig struct {
-	URL         string
-	FrontendURL string
+	URL                     string
+	FrontendURL             string
+	APIURL                  string
+	BudgetAlertThresholds   []int
+	NotificationMaxAttempts int
+	AdminAPIKey             string
 }
 
 func Load() (*Config, error) {
@@ -104,22 +143,55 @@ func Load() (*Config, error) {
 			RefreshTokenCookieName: getEnv("REFRESH_TOKEN_COOKIE_NAME", "refresh_token"),
 		},
 		Storage: StorageConfig{
-			Provider:     getEnv("STORAGE_PROVIDER", "local"),
-			LocalPath:    getEnv("LOCAL_STORAGE_PATH", "./uploads"),
-			AWSRegion:    getEnv("AWS_REGION", "us-east-1"),
-			AWSBucket:    getEnv("AWS_BUCKET", ""),
-			AWSAccessKey: getEnv("AWS_ACCESS_KEY_ID", ""),
-			AWSSecretKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
-			AWSEndpoint:  getEnv("AWS_ENDPOINT", ""),
+			Provider:       getEnv("STORAGE_PROVIDER", "local"),
+			LocalPath:      getEnv("LOCAL_STORAGE_PATH", "./uploads"),
+			AWSRegion:      getEnv("AWS_REGION", "us-east-1"),
+			AWSBucket:      getEnv("AWS_BUCKET", ""),
+			AWSAccessKey:   getEnv("AWS_ACCESS_KEY_ID", ""),
+			AWSSecretKey:   getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			AWSEndpoint:    getEnv("AWS_ENDPOINT", ""),
+			MaxUploadBytes: int64(getEnvAsInt("MAX_UPLOAD_SIZE_MB", 5)) * 1024 * 1024,
+			UserQuotaBytes: int64(getEnvAsInt("STORAGE_QUOTA_MB", 100)) * 1024 * 1024,
 		},
 		Redis: RedisConfig{
 			URL:      getEnv("REDIS_URL", "redis://localhost:6379/0"),
 			Password: getEnv("REDIS_PASSWORD", ""),
 			CacheTTL: time.Duration(getEnvAsInt("CACHE_TTL_MINUTES", 60)) * time.Minute,
+			PubSub:   getEnvAsBool("REDIS_PUBSUB_ENABLED", false),
+		},
+		Email: EmailConfig{
+			Provider:     getEnv("EMAIL_PROVIDER", "log"),
+			BrevoAPIKey:  getEnv("BREVO_API_KEY", ""),
+			FromEmail:    getEnv("FROM_EMAIL", "noreply@30budget.app"),
+			FromName:     getEnv("FROM_NAME", "30Budget"),
+			SupportEmail: getEnv("SUPPORT_EMAIL", "support@30budget.app"),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			OutputDir:    getEnv("EMAIL_OUTPUT_DIR", "./tmp/mail"),
+		},
+		SMS: SMSConfig{
+			Provider:               getEnv("SMS_PROVIDER", "mock"),
+			TwilioAccountSID:       getEnv("TWILIO_ACCOUNT_SID", ""),
+			TwilioAuthToken:        getEnv("TWILIO_AUTH_TOKEN", ""),
+			TwilioFromNumber:       getEnv("TWILIO_FROM_NUMBER", ""),
+			AfricasTalkingUsername: getEnv("AFRICASTALKING_USERNAME", ""),
+			AfricasTalkingAPIKey:   getEnv("AFRICASTALKING_API_KEY", ""),
+			AfricasTalkingSenderID: getEnv("AFRICASTALKING_SENDER_ID", ""),
+		},
+		FX: FXConfig{
+			Provider:  getEnv("FX_PROVIDER", "fake"),
+			RatesFile: getEnv("FX_RATES_FILE", "./rates.csv"),
+			HTTPURL:   getEnv("FX_HTTP_URL", "https://api.frankfurter.app"),
 		},
 		App: AppConfig{
-			URL:         getEnv("APP_URL", "http://localhost:3000"),
-			FrontendURL: getEnv("FRONTEND_URL", "http://localhost:3000"),
+			URL:                     getEnv("APP_URL", "http://localhost:3000"),
+			FrontendURL:             getEnv("FRONTEND_URL", "http://localhost:3000"),
+			APIURL:                  getEnv("API_URL", "http://localhost:8080"),
+			BudgetAlertThresholds:   getEnvAsIntSlice("BUDGET_ALERT_THRESHOLDS", []int{80, 100}),
+			NotificationMaxAttempts: getEnvAsInt("NOTIFICATION_MAX_ATTEMPTS", 8),
+			AdminAPIKey:             getEnv("ADMIN_API_KEY", ""),
 		},
 		MigrationsPath: getEnv("MIGRATIONS_PATH", "./migrations"),
 		AutoMigrate:    getEnvAsBool("AUTO_MIGRATE", true),
@@ -142,6 +214,22 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsIntSlice(key string, defaultValue []int) []int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var values []int
+	for _, part := range strings.Split(value, ",") {
+		intValue, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || intValue <= 0 {
+			return defaultValue
+		}
+		values = append(values, intValue)
+	}
+	return values
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
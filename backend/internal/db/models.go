@@ -9,8 +9,227 @@ import (
 	"fmt"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/money"
 )
 
+type AccountType string
+
+const (
+	AccountTypeCash        AccountType = "cash"
+	AccountTypeBank        AccountType = "bank"
+	AccountTypeMobileMoney AccountType = "mobile_money"
+	AccountTypeSavings     AccountType = "savings"
+	AccountTypeCreditCard  AccountType = "credit_card"
+	AccountTypeOther       AccountType = "other"
+)
+
+func (e *AccountType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountType(s)
+	case string:
+		*e = AccountType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountType: %T", src)
+	}
+	return nil
+}
+
+type NullAccountType struct {
+	AccountType AccountType `json:"accountType"`
+	Valid       bool        `json:"valid"` // Valid is true if AccountType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccountType) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccountType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccountType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccountType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AccountType), nil
+}
+
+type AnomalyKind string
+
+const (
+	AnomalyKindCategoryOutlier AnomalyKind = "category_outlier"
+	AnomalyKindPayeeOutlier    AnomalyKind = "payee_outlier"
+	AnomalyKindNewPayee        AnomalyKind = "new_payee"
+	AnomalyKindDuplicate       AnomalyKind = "duplicate"
+)
+
+func (e *AnomalyKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AnomalyKind(s)
+	case string:
+		*e = AnomalyKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AnomalyKind: %T", src)
+	}
+	return nil
+}
+
+type NullAnomalyKind struct {
+	AnomalyKind AnomalyKind `json:"anomalyKind"`
+	Valid       bool        `json:"valid"` // Valid is true if AnomalyKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAnomalyKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.AnomalyKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AnomalyKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAnomalyKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AnomalyKind), nil
+}
+
+type DigestFrequency string
+
+const (
+	DigestFrequencyNone    DigestFrequency = "none"
+	DigestFrequencyWeekly  DigestFrequency = "weekly"
+	DigestFrequencyMonthly DigestFrequency = "monthly"
+)
+
+func (e *DigestFrequency) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DigestFrequency(s)
+	case string:
+		*e = DigestFrequency(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DigestFrequency: %T", src)
+	}
+	return nil
+}
+
+type NullDigestFrequency struct {
+	DigestFrequency DigestFrequency `json:"digestFrequency"`
+	Valid           bool            `json:"valid"` // Valid is true if DigestFrequency is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDigestFrequency) Scan(value interface{}) error {
+	if value == nil {
+		ns.DigestFrequency, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DigestFrequency.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDigestFrequency) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DigestFrequency), nil
+}
+
+type NetWorthKind string
+
+const (
+	NetWorthKindAsset     NetWorthKind = "asset"
+	NetWorthKindLiability NetWorthKind = "liability"
+)
+
+func (e *NetWorthKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NetWorthKind(s)
+	case string:
+		*e = NetWorthKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NetWorthKind: %T", src)
+	}
+	return nil
+}
+
+type NullNetWorthKind struct {
+	NetWorthKind NetWorthKind `json:"netWorthKind"`
+	Valid        bool         `json:"valid"` // Valid is true if NetWorthKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNetWorthKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.NetWorthKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NetWorthKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNetWorthKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NetWorthKind), nil
+}
+
+type NotificationChannel string
+
+const (
+	NotificationChannelInApp NotificationChannel = "in_app"
+	NotificationChannelEmail NotificationChannel = "email"
+	NotificationChannelSms   NotificationChannel = "sms"
+)
+
+func (e *NotificationChannel) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = NotificationChannel(s)
+	case string:
+		*e = NotificationChannel(s)
+	default:
+		return fmt.Errorf("unsupported scan type for NotificationChannel: %T", src)
+	}
+	return nil
+}
+
+type NullNotificationChannel struct {
+	NotificationChannel NotificationChannel `json:"notificationChannel"`
+	Valid               bool                `json:"valid"` // Valid is true if NotificationChannel is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullNotificationChannel) Scan(value interface{}) error {
+	if value == nil {
+		ns.NotificationChannel, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.NotificationChannel.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullNotificationChannel) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.NotificationChannel), nil
+}
+
 type NotificationType string
 
 const (
@@ -55,6 +274,138 @@ func (ns NullNotificationType) Value() (driver.Value, error) {
 	return string(ns.NotificationType), nil
 }
 
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusSent      OutboxStatus = "sent"
+	OutboxStatusDead      OutboxStatus = "dead"
+	OutboxStatusCancelled OutboxStatus = "cancelled"
+)
+
+func (e *OutboxStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = OutboxStatus(s)
+	case string:
+		*e = OutboxStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for OutboxStatus: %T", src)
+	}
+	return nil
+}
+
+type NullOutboxStatus struct {
+	OutboxStatus OutboxStatus `json:"outboxStatus"`
+	Valid        bool         `json:"valid"` // Valid is true if OutboxStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullOutboxStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.OutboxStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.OutboxStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullOutboxStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.OutboxStatus), nil
+}
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceFrequencyOnce    RecurrenceFrequency = "once"
+	RecurrenceFrequencyDaily   RecurrenceFrequency = "daily"
+	RecurrenceFrequencyWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceFrequencyMonthly RecurrenceFrequency = "monthly"
+	RecurrenceFrequencyYearly  RecurrenceFrequency = "yearly"
+)
+
+func (e *RecurrenceFrequency) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RecurrenceFrequency(s)
+	case string:
+		*e = RecurrenceFrequency(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RecurrenceFrequency: %T", src)
+	}
+	return nil
+}
+
+type NullRecurrenceFrequency struct {
+	RecurrenceFrequency RecurrenceFrequency `json:"recurrenceFrequency"`
+	Valid               bool                `json:"valid"` // Valid is true if RecurrenceFrequency is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRecurrenceFrequency) Scan(value interface{}) error {
+	if value == nil {
+		ns.RecurrenceFrequency, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RecurrenceFrequency.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRecurrenceFrequency) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.RecurrenceFrequency), nil
+}
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusPending   SubscriptionStatus = "pending"
+	SubscriptionStatusConfirmed SubscriptionStatus = "confirmed"
+	SubscriptionStatusDismissed SubscriptionStatus = "dismissed"
+)
+
+func (e *SubscriptionStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SubscriptionStatus(s)
+	case string:
+		*e = SubscriptionStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SubscriptionStatus: %T", src)
+	}
+	return nil
+}
+
+type NullSubscriptionStatus struct {
+	SubscriptionStatus SubscriptionStatus `json:"subscriptionStatus"`
+	Valid              bool               `json:"valid"` // Valid is true if SubscriptionStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSubscriptionStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.SubscriptionStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SubscriptionStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSubscriptionStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SubscriptionStatus), nil
+}
+
 type TransactionType string
 
 const (
@@ -97,28 +448,220 @@ func (ns NullTransactionType) Value() (driver.Value, error) {
 	return string(ns.TransactionType), nil
 }
 
+type Account struct {
+	ID             pgtype.UUID        `json:"id"`
+	UserID         pgtype.UUID        `json:"userId"`
+	Name           string             `json:"name"`
+	Type           AccountType        `json:"type"`
+	OpeningBalance money.Decimal      `json:"openingBalance"`
+	Archived       bool               `json:"archived"`
+	CreatedAt      pgtype.Timestamptz `json:"createdAt"`
+	UpdatedAt      pgtype.Timestamptz `json:"updatedAt"`
+	Currency       string             `json:"currency"`
+}
+
+type Attachment struct {
+	ID            pgtype.UUID        `json:"id"`
+	UserID        pgtype.UUID        `json:"userId"`
+	TransactionID pgtype.UUID        `json:"transactionId"`
+	FileName      string             `json:"fileName"`
+	ContentType   string             `json:"contentType"`
+	SizeBytes     int64              `json:"sizeBytes"`
+	StorageKey    string             `json:"storageKey"`
+	CreatedAt     pgtype.Timestamptz `json:"createdAt"`
+}
+
+type Bill struct {
+	ID               pgtype.UUID         `json:"id"`
+	UserID           pgtype.UUID         `json:"userId"`
+	Name             string              `json:"name"`
+	Amount           money.Decimal       `json:"amount"`
+	CategoryID       pgtype.UUID         `json:"categoryId"`
+	PayeeID          pgtype.UUID         `json:"payeeId"`
+	Frequency        RecurrenceFrequency `json:"frequency"`
+	IntervalCount    int32               `json:"intervalCount"`
+	StartDate        pgtype.Date         `json:"startDate"`
+	EndDate          pgtype.Date         `json:"endDate"`
+	NextDueDate      pgtype.Date         `json:"nextDueDate"`
+	RemindDaysBefore int32               `json:"remindDaysBefore"`
+	RemindedDueDate  pgtype.Date         `json:"remindedDueDate"`
+	CreatedAt        pgtype.Timestamptz  `json:"createdAt"`
+	UpdatedAt        pgtype.Timestamptz  `json:"updatedAt"`
+	AccountID        pgtype.UUID         `json:"accountId"`
+}
+
+type BillPayment struct {
+	ID            pgtype.UUID        `json:"id"`
+	BillID        pgtype.UUID        `json:"billId"`
+	UserID        pgtype.UUID        `json:"userId"`
+	DueDate       pgtype.Date        `json:"dueDate"`
+	TransactionID pgtype.UUID        `json:"transactionId"`
+	CreatedAt     pgtype.Timestamptz `json:"createdAt"`
+}
+
+type BudgetAlert struct {
+	UserID         pgtype.UUID        `json:"userId"`
+	CategoryID     pgtype.UUID        `json:"categoryId"`
+	PeriodStart    pgtype.Date        `json:"periodStart"`
+	Threshold      int32              `json:"threshold"`
+	NotificationID pgtype.UUID        `json:"notificationId"`
+	CreatedAt      pgtype.Timestamptz `json:"createdAt"`
+}
+
 type BudgetTemplate struct {
 	ID           pgtype.UUID        `json:"id"`
 	UserID       pgtype.UUID        `json:"userId"`
 	Name         string             `json:"name"`
 	Description  pgtype.Text        `json:"description"`
-	GlobalBudget pgtype.Numeric     `json:"globalBudget"`
+	GlobalBudget money.Decimal      `json:"globalBudget"`
 	Categories   []byte             `json:"categories"`
 	CreatedAt    pgtype.Timestamptz `json:"createdAt"`
 	UpdatedAt    pgtype.Timestamptz `json:"updatedAt"`
 }
 
+type CalendarFeed struct {
+	UserID         pgtype.UUID        `json:"userId"`
+	TokenHash      string             `json:"tokenHash"`
+	LastAccessedAt pgtype.Timestamptz `json:"lastAccessedAt"`
+	CreatedAt      pgtype.Timestamptz `json:"createdAt"`
+}
+
 type Category struct {
 	ID          pgtype.UUID        `json:"id"`
 	UserID      pgtype.UUID        `json:"userId"`
 	Name        string             `json:"name"`
 	Color       string             `json:"color"`
 	Type        TransactionType    `json:"type"`
-	BudgetLimit pgtype.Numeric     `json:"budgetLimit"`
+	BudgetLimit money.Decimal      `json:"budgetLimit"`
 	CreatedAt   pgtype.Timestamptz `json:"createdAt"`
 	UpdatedAt   pgtype.Timestamptz `json:"updatedAt"`
 }
 
+type CategoryModelDoc struct {
+	UserID     pgtype.UUID        `json:"userId"`
+	CategoryID pgtype.UUID        `json:"categoryId"`
+	DocCount   int32              `json:"docCount"`
+	TokenCount int32              `json:"tokenCount"`
+	UpdatedAt  pgtype.Timestamptz `json:"updatedAt"`
+}
+
+type CategoryModelToken struct {
+	UserID     pgtype.UUID `json:"userId"`
+	CategoryID pgtype.UUID `json:"categoryId"`
+	Token      string      `json:"token"`
+	Count      int32       `json:"count"`
+}
+
+type Debt struct {
+	ID             pgtype.UUID        `json:"id"`
+	UserID         pgtype.UUID        `json:"userId"`
+	Name           string             `json:"name"`
+	Balance        money.Decimal      `json:"balance"`
+	Apr            money.Decimal      `json:"apr"`
+	MinimumPayment money.Decimal      `json:"minimumPayment"`
+	DueDay         int32              `json:"dueDay"`
+	CreatedAt      pgtype.Timestamptz `json:"createdAt"`
+	UpdatedAt      pgtype.Timestamptz `json:"updatedAt"`
+}
+
+type DetectedSubscription struct {
+	ID                     pgtype.UUID         `json:"id"`
+	UserID                 pgtype.UUID         `json:"userId"`
+	PayeeID                pgtype.UUID         `json:"payeeId"`
+	Frequency              RecurrenceFrequency `json:"frequency"`
+	Amount                 money.Decimal       `json:"amount"`
+	CategoryID             pgtype.UUID         `json:"categoryId"`
+	AccountID              pgtype.UUID         `json:"accountId"`
+	ChargeCount            int32               `json:"chargeCount"`
+	LastChargedOn          pgtype.Date         `json:"lastChargedOn"`
+	NextExpectedDate       pgtype.Date         `json:"nextExpectedDate"`
+	AnnualCost             money.Decimal       `json:"annualCost"`
+	Status                 SubscriptionStatus  `json:"status"`
+	RecurringTransactionID pgtype.UUID         `json:"recurringTransactionId"`
+	CreatedAt              pgtype.Timestamptz  `json:"createdAt"`
+	UpdatedAt              pgtype.Timestamptz  `json:"updatedAt"`
+}
+
+type Digest struct {
+	UserID         pgtype.UUID        `json:"userId"`
+	Frequency      DigestFrequency    `json:"frequency"`
+	PeriodStart    pgtype.Date        `json:"periodStart"`
+	NotificationID pgtype.UUID        `json:"notificationId"`
+	CreatedAt      pgtype.Timestamptz `json:"createdAt"`
+}
+
+type ExchangeRate struct {
+	BaseCurrency  string             `json:"baseCurrency"`
+	QuoteCurrency string             `json:"quoteCurrency"`
+	RateDate      pgtype.Date        `json:"rateDate"`
+	Rate          money.Decimal      `json:"rate"`
+	Source        string             `json:"source"`
+	CreatedAt     pgtype.Timestamptz `json:"createdAt"`
+}
+
+type Goal struct {
+	ID           pgtype.UUID        `json:"id"`
+	UserID       pgtype.UUID        `json:"userId"`
+	Name         string             `json:"name"`
+	TargetAmount money.Decimal      `json:"targetAmount"`
+	TargetDate   pgtype.Date        `json:"targetDate"`
+	AccountID    pgtype.UUID        `json:"accountId"`
+	CategoryID   pgtype.UUID        `json:"categoryId"`
+	AchievedAt   pgtype.Timestamptz `json:"achievedAt"`
+	CreatedAt    pgtype.Timestamptz `json:"createdAt"`
+	UpdatedAt    pgtype.Timestamptz `json:"updatedAt"`
+}
+
+type GoalContribution struct {
+	ID            pgtype.UUID        `json:"id"`
+	GoalID        pgtype.UUID        `json:"goalId"`
+	UserID        pgtype.UUID        `json:"userId"`
+	Amount        money.Decimal      `json:"amount"`
+	Date          pgtype.Timestamptz `json:"date"`
+	TransactionID pgtype.UUID        `json:"transactionId"`
+	Note          pgtype.Text        `json:"note"`
+	CreatedAt     pgtype.Timestamptz `json:"createdAt"`
+}
+
+type GoalMilestone struct {
+	GoalID         pgtype.UUID        `json:"goalId"`
+	Percent        int32              `json:"percent"`
+	NotificationID pgtype.UUID        `json:"notificationId"`
+	CreatedAt      pgtype.Timestamptz `json:"createdAt"`
+}
+
+type NetWorthItem struct {
+	ID        pgtype.UUID        `json:"id"`
+	UserID    pgtype.UUID        `json:"userId"`
+	Name      string             `json:"name"`
+	Kind      NetWorthKind       `json:"kind"`
+	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+	UpdatedAt pgtype.Timestamptz `json:"updatedAt"`
+}
+
+type NetWorthSnapshot struct {
+	UserID             pgtype.UUID        `json:"userId"`
+	SnapshotDate       pgtype.Date        `json:"snapshotDate"`
+	AccountAssets      money.Decimal      `json:"accountAssets"`
+	ItemAssets         money.Decimal      `json:"itemAssets"`
+	TotalAssets        money.Decimal      `json:"totalAssets"`
+	AccountLiabilities money.Decimal      `json:"accountLiabilities"`
+	DebtLiabilities    money.Decimal      `json:"debtLiabilities"`
+	ItemLiabilities    money.Decimal      `json:"itemLiabilities"`
+	TotalLiabilities   money.Decimal      `json:"totalLiabilities"`
+	NetWorth           money.Decimal      `json:"netWorth"`
+	CreatedAt          pgtype.Timestamptz `json:"createdAt"`
+}
+
+type NetWorthValuation struct {
+	ID        pgtype.UUID        `json:"id"`
+	ItemID    pgtype.UUID        `json:"itemId"`
+	UserID    pgtype.UUID        `json:"userId"`
+	Value     money.Decimal      `json:"value"`
+	ValuedOn  pgtype.Date        `json:"valuedOn"`
+	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+}
+
 type Notification struct {
 	ID        pgtype.UUID        `json:"id"`
 	UserID    pgtype.UUID        `json:"userId"`
@@ -131,16 +674,118 @@ type Notification struct {
 	UpdatedAt pgtype.Timestamptz `json:"updatedAt"`
 }
 
-type Transaction struct {
-	ID          pgtype.UUID        `json:"id"`
+type NotificationOutbox struct {
+	ID             pgtype.UUID         `json:"id"`
+	NotificationID pgtype.UUID         `json:"notificationId"`
+	UserID         pgtype.UUID         `json:"userId"`
+	Channel        NotificationChannel `json:"channel"`
+	Payload        []byte              `json:"payload"`
+	Status         OutboxStatus        `json:"status"`
+	Attempts       int32               `json:"attempts"`
+	LastError      pgtype.Text         `json:"lastError"`
+	NextAttemptAt  pgtype.Timestamptz  `json:"nextAttemptAt"`
+	SentAt         pgtype.Timestamptz  `json:"sentAt"`
+	CreatedAt      pgtype.Timestamptz  `json:"createdAt"`
+	UpdatedAt      pgtype.Timestamptz  `json:"updatedAt"`
+}
+
+type NotificationPreference struct {
+	UserID    pgtype.UUID         `json:"userId"`
+	Type      NotificationType    `json:"type"`
+	Channel   NotificationChannel `json:"channel"`
+	Enabled   bool                `json:"enabled"`
+	UpdatedAt pgtype.Timestamptz  `json:"updatedAt"`
+}
+
+type NotificationSetting struct {
+	UserID          pgtype.UUID        `json:"userId"`
+	QuietHoursStart pgtype.Time        `json:"quietHoursStart"`
+	QuietHoursEnd   pgtype.Time        `json:"quietHoursEnd"`
+	DigestFrequency DigestFrequency    `json:"digestFrequency"`
+	UpdatedAt       pgtype.Timestamptz `json:"updatedAt"`
+}
+
+type Payee struct {
+	ID             pgtype.UUID        `json:"id"`
+	UserID         pgtype.UUID        `json:"userId"`
+	Name           string             `json:"name"`
+	NormalizedName string             `json:"normalizedName"`
+	CreatedAt      pgtype.Timestamptz `json:"createdAt"`
+	UpdatedAt      pgtype.Timestamptz `json:"updatedAt"`
+}
+
+type PayeeAlias struct {
+	UserID    pgtype.UUID        `json:"userId"`
+	Alias     string             `json:"alias"`
+	PayeeID   pgtype.UUID        `json:"payeeId"`
+	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+}
+
+type PhoneVerification struct {
 	UserID      pgtype.UUID        `json:"userId"`
-	Amount      pgtype.Numeric     `json:"amount"`
-	Description pgtype.Text        `json:"description"`
-	CategoryID  pgtype.UUID        `json:"categoryId"`
-	Date        pgtype.Timestamptz `json:"date"`
-	Type        TransactionType    `json:"type"`
+	PhoneNumber string             `json:"phoneNumber"`
+	CodeHash    string             `json:"codeHash"`
+	Attempts    int32              `json:"attempts"`
+	ExpiresAt   pgtype.Timestamptz `json:"expiresAt"`
 	CreatedAt   pgtype.Timestamptz `json:"createdAt"`
-	UpdatedAt   pgtype.Timestamptz `json:"updatedAt"`
+}
+
+type RecurringTransaction struct {
+	ID            pgtype.UUID         `json:"id"`
+	UserID        pgtype.UUID         `json:"userId"`
+	Description   string              `json:"description"`
+	Amount        money.Decimal       `json:"amount"`
+	Type          TransactionType     `json:"type"`
+	CategoryID    pgtype.UUID         `json:"categoryId"`
+	PayeeID       pgtype.UUID         `json:"payeeId"`
+	Frequency     RecurrenceFrequency `json:"frequency"`
+	IntervalCount int32               `json:"intervalCount"`
+	StartDate     pgtype.Date         `json:"startDate"`
+	EndDate       pgtype.Date         `json:"endDate"`
+	CreatedAt     pgtype.Timestamptz  `json:"createdAt"`
+	UpdatedAt     pgtype.Timestamptz  `json:"updatedAt"`
+	AccountID     pgtype.UUID         `json:"accountId"`
+}
+
+type Tag struct {
+	ID        pgtype.UUID        `json:"id"`
+	UserID    pgtype.UUID        `json:"userId"`
+	Name      string             `json:"name"`
+	Color     string             `json:"color"`
+	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+	UpdatedAt pgtype.Timestamptz `json:"updatedAt"`
+}
+
+type Transaction struct {
+	ID           pgtype.UUID        `json:"id"`
+	UserID       pgtype.UUID        `json:"userId"`
+	Amount       money.Decimal      `json:"amount"`
+	Description  pgtype.Text        `json:"description"`
+	CategoryID   pgtype.UUID        `json:"categoryId"`
+	Date         pgtype.Timestamptz `json:"date"`
+	Type         TransactionType    `json:"type"`
+	CreatedAt    pgtype.Timestamptz `json:"createdAt"`
+	UpdatedAt    pgtype.Timestamptz `json:"updatedAt"`
+	PayeeID      pgtype.UUID        `json:"payeeId"`
+	AccountID    pgtype.UUID        `json:"accountId"`
+	Currency     string             `json:"currency"`
+	ExchangeRate money.Decimal      `json:"exchangeRate"`
+	BaseAmount   money.Decimal      `json:"baseAmount"`
+}
+
+type TransactionAnomaly struct {
+	TransactionID  pgtype.UUID        `json:"transactionId"`
+	Kind           AnomalyKind        `json:"kind"`
+	UserID         pgtype.UUID        `json:"userId"`
+	Explanation    string             `json:"explanation"`
+	NotificationID pgtype.UUID        `json:"notificationId"`
+	CreatedAt      pgtype.Timestamptz `json:"createdAt"`
+}
+
+type TransactionTag struct {
+	TransactionID pgtype.UUID        `json:"transactionId"`
+	TagID         pgtype.UUID        `json:"tagId"`
+	CreatedAt     pgtype.Timestamptz `json:"createdAt"`
 }
 
 type User struct {
@@ -150,8 +795,11 @@ type User struct {
 	PasswordHash       string             `json:"passwordHash"`
 	Currency           string             `json:"currency"`
 	CurrencySymbol     string             `json:"currencySymbol"`
-	MonthlyIncome      pgtype.Numeric     `json:"monthlyIncome"`
+	MonthlyIncome      money.Decimal      `json:"monthlyIncome"`
 	OnboardingComplete bool               `json:"onboardingComplete"`
 	CreatedAt          pgtype.Timestamptz `json:"createdAt"`
 	UpdatedAt          pgtype.Timestamptz `json:"updatedAt"`
+	PhoneNumber        pgtype.Text        `json:"phoneNumber"`
+	PhoneVerifiedAt    pgtype.Timestamptz `json:"phoneVerifiedAt"`
+	Timezone           string             `json:"timezone"`
 }
@@ -0,0 +1,49 @@
+-- name: CreateAccount :one
+INSERT INTO accounts (id, user_id, name, type, opening_balance, currency)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetAccount :one
+SELECT * FROM accounts
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListOpenAccountsByUser :many
+SELECT * FROM accounts
+WHERE user_id = $1 AND NOT archived
+ORDER BY name;
+
+-- name: UpdateAccount :one
+UPDATE accounts
+SET name = $3, type = $4, opening_balance = $5, archived = $6, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteAccount :exec
+DELETE FROM accounts
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListAccountBalances :many
+-- The user's accounts, open ones first, each with its balance at @as_of:
+-- the opening balance plus the income and less the expenses dated before,
+-- in the account's currency.
+SELECT a.*,
+    (a.opening_balance + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0))::numeric AS balance
+FROM accounts a
+LEFT JOIN transactions t ON t.account_id = a.id AND t.date < @as_of
+WHERE a.user_id = @user_id
+GROUP BY a.id
+ORDER BY a.archived, a.name;
+
+-- name: GetAccountBalance :one
+SELECT (a.opening_balance + COALESCE(SUM(CASE WHEN t.type = 'income' THEN t.amount ELSE -t.amount END), 0))::numeric AS balance
+FROM accounts a
+LEFT JOIN transactions t ON t.account_id = a.id AND t.date < @as_of
+WHERE a.id = @id AND a.user_id = @user_id
+GROUP BY a.id;
+
+-- name: GetUnassignedBalance :one
+-- Net income at @as_of of the transactions not recorded against an account,
+-- in the user's currency.
+SELECT COALESCE(SUM(CASE WHEN type = 'income' THEN base_amount ELSE -base_amount END), 0)::numeric AS balance
+FROM transactions
+WHERE user_id = @user_id AND account_id IS NULL AND date < @as_of;
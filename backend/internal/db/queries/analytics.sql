@@ -0,0 +1,115 @@
+-- name: ListSpendingBuckets :many
+-- Expense totals per bucket and category, zero-filled, including an
+-- uncategorized row with a NULL category. Buckets run from each of
+-- bucket_starts to the next, the last ending at period_end. An empty
+-- category_ids means every category.
+WITH buckets AS (
+    SELECT b.start,
+           COALESCE(LEAD(b.start) OVER (ORDER BY b.start), @period_end::timestamptz) AS finish
+    FROM unnest(@bucket_starts::timestamptz[]) AS b(start)
+),
+cats AS (
+    SELECT c.id, c.name, c.color
+    FROM categories c
+    WHERE c.user_id = @user_id
+      AND c.type = 'expense'
+      AND (cardinality(@category_ids::uuid[]) = 0 OR c.id = ANY(@category_ids::uuid[]))
+    UNION ALL
+    SELECT NULL::uuid, 'Uncategorized', '#CCCCCC'
+    WHERE cardinality(@category_ids::uuid[]) = 0
+)
+SELECT
+    b.start::timestamptz AS bucket_start,
+    b.finish::timestamptz AS bucket_end,
+    c.id AS category_id,
+    c.name::text AS category_name,
+    c.color::text AS category_color,
+    COALESCE(SUM(t.base_amount), 0)::numeric AS amount
+FROM buckets b
+CROSS JOIN cats c
+LEFT JOIN transactions t
+    ON t.user_id = @user_id
+   AND t.type = 'expense'
+   AND t.category_id IS NOT DISTINCT FROM c.id
+   AND t.date >= b.start
+   AND t.date < b.finish
+GROUP BY b.start, b.finish, c.id, c.name, c.color
+ORDER BY b.start, c.id IS NULL, c.name;
+
+-- name: ListSpendingTotals :many
+-- Expense total per bucket (bounded as in ListSpendingBuckets), with the
+-- change on the previous bucket and the average of the last window_size
+-- buckets, zero-filled.
+WITH buckets AS (
+    SELECT b.start,
+           COALESCE(LEAD(b.start) OVER (ORDER BY b.start), @period_end::timestamptz) AS finish
+    FROM unnest(@bucket_starts::timestamptz[]) AS b(start)
+),
+totals AS (
+    SELECT b.start, b.finish, COALESCE(SUM(t.base_amount), 0)::numeric AS total
+    FROM buckets b
+    LEFT JOIN transactions t
+        ON t.user_id = @user_id
+       AND t.type = 'expense'
+       AND (cardinality(@category_ids::uuid[]) = 0 OR t.category_id = ANY(@category_ids::uuid[]))
+       AND t.date >= b.start
+       AND t.date < b.finish
+    GROUP BY b.start, b.finish
+)
+SELECT
+    start::timestamptz AS bucket_start,
+    finish::timestamptz AS bucket_end,
+    total,
+    (total - LAG(total) OVER w)::numeric AS change,
+    (CASE WHEN LAG(total) OVER w > 0
+          THEN ROUND((total - LAG(total) OVER w) * 100 / LAG(total) OVER w, 2) END)::numeric AS change_percent,
+    ROUND(AVG(total) OVER (ORDER BY start ROWS BETWEEN @window_preceding::int PRECEDING AND CURRENT ROW), 2)::numeric AS rolling_average
+FROM totals
+WINDOW w AS (ORDER BY start)
+ORDER BY start;
+
+-- name: ListCategoryComparison :many
+-- Expenses per category in the current period against the previous one.
+-- change_percent is NULL when nothing was spent in the previous period.
+SELECT
+    s.category_id,
+    s.category_name,
+    s.current,
+    s.previous,
+    (s.current - s.previous)::numeric AS change,
+    (CASE WHEN s.previous > 0 THEN ROUND((s.current - s.previous) * 100 / s.previous, 2) END)::numeric AS change_percent
+FROM (
+    SELECT
+        t.category_id,
+        COALESCE(c.name, 'Uncategorized')::text AS category_name,
+        COALESCE(SUM(t.base_amount) FILTER (WHERE t.date >= @current_start AND t.date < @current_end), 0)::numeric AS current,
+        COALESCE(SUM(t.base_amount) FILTER (WHERE t.date >= @previous_start AND t.date < @previous_end), 0)::numeric AS previous
+    FROM transactions t
+    LEFT JOIN categories c ON c.id = t.category_id
+    WHERE t.user_id = @user_id
+      AND t.type = 'expense'
+      AND t.date >= LEAST(@current_start, @previous_start)
+      AND t.date < GREATEST(@current_end, @previous_end)
+    GROUP BY t.category_id, c.name
+) s
+ORDER BY s.current DESC, s.category_name;
+
+-- name: ListSpendingHeatmap :many
+-- Expense count and total for every day of the week (1 = Monday) and hour
+-- in the given time zone, zero-filled.
+SELECT
+    d.weekday::int AS weekday,
+    h.hour::int AS hour,
+    COUNT(t.id) AS transaction_count,
+    COALESCE(SUM(t.base_amount), 0)::numeric AS amount
+FROM generate_series(1, 7) AS d(weekday)
+CROSS JOIN generate_series(0, 23) AS h(hour)
+LEFT JOIN transactions t
+    ON t.user_id = @user_id
+   AND t.type = 'expense'
+   AND t.date >= @period_start
+   AND t.date < @period_end
+   AND EXTRACT(ISODOW FROM t.date AT TIME ZONE @tz::text) = d.weekday
+   AND EXTRACT(HOUR FROM t.date AT TIME ZONE @tz::text) = h.hour
+GROUP BY d.weekday, h.hour
+ORDER BY d.weekday, h.hour;
@@ -0,0 +1,80 @@
+-- name: GetExpenseStats :one
+-- Count, mean and sample standard deviation of the user's other expenses
+-- between two instants, optionally only those in a category or to a payee.
+SELECT
+    COUNT(*) AS sample_size,
+    COALESCE(AVG(base_amount), 0)::numeric AS mean,
+    COALESCE(STDDEV_SAMP(base_amount), 0)::numeric AS stddev
+FROM transactions
+WHERE user_id = @user_id
+  AND id <> @exclude_id
+  AND type = 'expense'
+  AND date >= @period_start
+  AND date < @period_end
+  AND (sqlc.narg('category_id')::uuid IS NULL OR category_id = sqlc.narg('category_id'))
+  AND (sqlc.narg('payee_id')::uuid IS NULL OR payee_id = sqlc.narg('payee_id'));
+
+-- name: GetExpensePercentile :one
+-- The amount below which the given fraction of the user's other expenses
+-- between two instants fall.
+SELECT
+    COUNT(*) AS sample_size,
+    COALESCE(percentile_cont(@fraction::float8) WITHIN GROUP (ORDER BY base_amount), 0)::numeric AS amount
+FROM transactions
+WHERE user_id = @user_id
+  AND id <> @exclude_id
+  AND type = 'expense'
+  AND date >= @period_start
+  AND date < @period_end;
+
+-- name: CountEarlierPayeeTransactions :one
+SELECT COUNT(*) FROM transactions
+WHERE user_id = @user_id
+  AND payee_id = @payee_id
+  AND id <> @exclude_id
+  AND date <= @before;
+
+-- name: FindDuplicateExpense :one
+-- The other expense of the same amount closest to @at within a window, to
+-- the same payee or, without one, with the same description.
+SELECT * FROM transactions
+WHERE user_id = @user_id
+  AND id <> @exclude_id
+  AND type = 'expense'
+  AND amount = @amount
+  AND currency = @currency
+  AND date >= @window_start
+  AND date <= @window_end
+  AND CASE
+    WHEN sqlc.narg('payee_id')::uuid IS NOT NULL THEN payee_id = sqlc.narg('payee_id')
+    ELSE LOWER(TRIM(description)) = LOWER(TRIM(@description::text))
+  END
+ORDER BY ABS(EXTRACT(EPOCH FROM date - @at::timestamptz)), created_at
+LIMIT 1;
+
+-- name: ListTransactionAnomalies :many
+SELECT * FROM transaction_anomalies
+WHERE transaction_id = $1;
+
+-- name: CreateTransactionAnomaly :execrows
+INSERT INTO transaction_anomalies (transaction_id, kind, user_id, explanation)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING;
+
+-- name: SetTransactionAnomalyNotification :exec
+UPDATE transaction_anomalies
+SET notification_id = $3
+WHERE transaction_id = $1 AND kind = $2;
+
+-- name: DeleteTransactionAnomaly :exec
+DELETE FROM transaction_anomalies
+WHERE transaction_id = $1 AND kind = $2;
+
+-- name: ListAnomaliesByUser :many
+-- Flagged transactions, most recently flagged first.
+SELECT a.*, t.amount, t.currency, t.description, t.date, t.category_id, t.payee_id
+FROM transaction_anomalies a
+JOIN transactions t ON t.id = a.transaction_id
+WHERE a.user_id = @user_id
+ORDER BY a.created_at DESC, t.date DESC, a.kind
+LIMIT @row_limit OFFSET @row_offset;
@@ -0,0 +1,23 @@
+-- name: CreateAttachment :one
+INSERT INTO attachments (id, user_id, transaction_id, file_name, content_type, size_bytes, storage_key)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetAttachment :one
+SELECT * FROM attachments
+WHERE id = $1 AND transaction_id = $2 AND user_id = $3;
+
+-- name: ListAttachmentsByTransaction :many
+SELECT * FROM attachments
+WHERE transaction_id = $1 AND user_id = $2
+ORDER BY created_at;
+
+-- name: DeleteAttachment :one
+DELETE FROM attachments
+WHERE id = $1 AND transaction_id = $2 AND user_id = $3
+RETURNING *;
+
+-- name: SumAttachmentSizeByUser :one
+SELECT COALESCE(SUM(size_bytes), 0)::bigint AS total_bytes
+FROM attachments
+WHERE user_id = $1;
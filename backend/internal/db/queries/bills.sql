@@ -0,0 +1,82 @@
+-- name: CreateBill :one
+INSERT INTO bills (id, user_id, name, amount, category_id, payee_id, frequency, interval_count, start_date, end_date, next_due_date, remind_days_before, account_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING *;
+
+-- name: GetBill :one
+SELECT * FROM bills
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListBillsByUser :many
+SELECT * FROM bills
+WHERE user_id = $1
+ORDER BY next_due_date NULLS LAST, name;
+
+-- name: UpdateBill :one
+UPDATE bills
+SET name = $3, amount = $4, category_id = $5, payee_id = $6, frequency = $7, interval_count = $8,
+    start_date = $9, end_date = $10, remind_days_before = $11, account_id = $12, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteBill :exec
+DELETE FROM bills
+WHERE id = $1 AND user_id = $2;
+
+-- name: SetBillNextDueDate :one
+UPDATE bills
+SET next_due_date = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: ListOutstandingBills :many
+-- Bills with an unpaid occurrence due on or before the given date,
+-- including overdue ones.
+SELECT * FROM bills
+WHERE user_id = @user_id
+  AND next_due_date IS NOT NULL
+  AND next_due_date <= @due_before
+ORDER BY next_due_date, name;
+
+-- name: ListBillsDueBetween :many
+SELECT * FROM bills
+WHERE user_id = @user_id
+  AND next_due_date BETWEEN @from_date AND @to_date
+ORDER BY next_due_date, name;
+
+-- name: ListBillsToRemind :many
+-- Bills whose reminder may be due; the caller checks the exact day in the
+-- user's time zone.
+SELECT b.* FROM bills b
+WHERE b.next_due_date IS NOT NULL
+  AND b.next_due_date <= CURRENT_DATE + b.remind_days_before + 1
+  AND b.reminded_due_date IS DISTINCT FROM b.next_due_date
+ORDER BY b.user_id, b.next_due_date;
+
+-- name: MarkBillReminded :execrows
+UPDATE bills
+SET reminded_due_date = next_due_date
+WHERE id = $1
+  AND next_due_date = $2
+  AND reminded_due_date IS DISTINCT FROM next_due_date;
+
+-- name: CreateBillPayment :one
+INSERT INTO bill_payments (id, bill_id, user_id, due_date, transaction_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListBillPayments :many
+SELECT * FROM bill_payments
+WHERE bill_id = $1 AND user_id = $2
+ORDER BY due_date DESC;
+
+-- name: GetLatestBillPayment :one
+SELECT * FROM bill_payments
+WHERE bill_id = $1
+ORDER BY due_date DESC
+LIMIT 1;
+
+-- name: DeleteBillPaymentByTransaction :one
+DELETE FROM bill_payments
+WHERE transaction_id = $1
+RETURNING *;
@@ -0,0 +1,26 @@
+-- name: GetCategorySpend :one
+SELECT COALESCE(SUM(base_amount), 0)::numeric AS spent
+FROM transactions
+WHERE user_id = @user_id
+  AND category_id = @category_id
+  AND type = 'expense'
+  AND date >= @period_start
+  AND date < @period_end;
+
+-- name: ListBudgetAlerts :many
+SELECT * FROM budget_alerts
+WHERE category_id = $1 AND period_start = $2;
+
+-- name: CreateBudgetAlert :execrows
+INSERT INTO budget_alerts (user_id, category_id, period_start, threshold)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT DO NOTHING;
+
+-- name: SetBudgetAlertNotification :exec
+UPDATE budget_alerts
+SET notification_id = $4
+WHERE category_id = $1 AND period_start = $2 AND threshold = $3;
+
+-- name: DeleteBudgetAlert :exec
+DELETE FROM budget_alerts
+WHERE category_id = $1 AND period_start = $2 AND threshold = $3;
@@ -0,0 +1,23 @@
+-- name: UpsertCalendarFeed :one
+INSERT INTO calendar_feeds (user_id, token_hash)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET token_hash = EXCLUDED.token_hash,
+    last_accessed_at = NULL,
+    created_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetCalendarFeed :one
+SELECT * FROM calendar_feeds
+WHERE user_id = $1;
+
+-- name: GetCalendarFeedByTokenHash :one
+-- Looks up the feed for a token and records that it was fetched.
+UPDATE calendar_feeds
+SET last_accessed_at = CURRENT_TIMESTAMP
+WHERE token_hash = $1
+RETURNING *;
+
+-- name: DeleteCalendarFeed :execrows
+DELETE FROM calendar_feeds
+WHERE user_id = $1;
@@ -0,0 +1,23 @@
+-- name: GetCategory :one
+SELECT * FROM categories
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListCategoriesByUser :many
+SELECT * FROM categories
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: CreateCategory :one
+INSERT INTO categories (id, user_id, name, color, type, budget_limit)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: UpdateCategory :one
+UPDATE categories
+SET name = $3, color = $4, type = $5, budget_limit = $6, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteCategory :execrows
+DELETE FROM categories
+WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,46 @@
+-- name: AddCategoryModelTokens :exec
+INSERT INTO category_model_tokens (user_id, category_id, token, count)
+SELECT @user_id::uuid, @category_id::uuid, t.token, (COUNT(*) * @delta::int)::int
+FROM unnest(@tokens::text[]) AS t(token)
+GROUP BY t.token
+ON CONFLICT (user_id, category_id, token)
+DO UPDATE SET count = category_model_tokens.count + EXCLUDED.count;
+
+-- name: AddCategoryModelDoc :exec
+INSERT INTO category_model_docs (user_id, category_id, doc_count, token_count)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, category_id)
+DO UPDATE SET doc_count = category_model_docs.doc_count + EXCLUDED.doc_count,
+              token_count = category_model_docs.token_count + EXCLUDED.token_count,
+              updated_at = CURRENT_TIMESTAMP;
+
+-- name: PruneCategoryModelTokens :exec
+DELETE FROM category_model_tokens
+WHERE user_id = $1 AND count <= 0;
+
+-- name: PruneCategoryModelDocs :exec
+DELETE FROM category_model_docs
+WHERE user_id = $1 AND doc_count <= 0;
+
+-- name: ListCategoryModelDocs :many
+SELECT d.category_id, c.name, c.type, d.doc_count, d.token_count
+FROM category_model_docs d
+JOIN categories c ON c.id = d.category_id
+WHERE d.user_id = $1 AND d.doc_count > 0;
+
+-- name: ListCategoryModelTokenCounts :many
+SELECT category_id, token, count
+FROM category_model_tokens
+WHERE user_id = @user_id AND token = ANY(@tokens::text[]);
+
+-- name: CountCategoryModelVocabulary :one
+SELECT COUNT(DISTINCT token) FROM category_model_tokens
+WHERE user_id = $1 AND count > 0;
+
+-- name: DeleteCategoryModelTokens :exec
+DELETE FROM category_model_tokens
+WHERE user_id = $1;
+
+-- name: DeleteCategoryModelDocs :exec
+DELETE FROM category_model_docs
+WHERE user_id = $1;
@@ -0,0 +1,28 @@
+-- name: CreateDebt :one
+INSERT INTO debts (id, user_id, name, balance, apr, minimum_payment, due_day)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetDebt :one
+SELECT * FROM debts
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListDebtsByUser :many
+SELECT * FROM debts
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: UpdateDebt :one
+UPDATE debts
+SET name = $3,
+    balance = $4,
+    apr = $5,
+    minimum_payment = $6,
+    due_day = $7,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteDebt :exec
+DELETE FROM debts
+WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,47 @@
+-- name: ListDigestUsers :many
+SELECT u.* FROM users u
+LEFT JOIN notification_settings s ON s.user_id = u.id
+WHERE COALESCE(s.digest_frequency, 'weekly') <> 'none'
+ORDER BY u.created_at;
+
+-- name: CreateDigest :execrows
+INSERT INTO digests (user_id, frequency, period_start)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: SetDigestNotification :exec
+UPDATE digests
+SET notification_id = $4
+WHERE user_id = $1 AND frequency = $2 AND period_start = $3;
+
+-- name: GetPeriodTotals :one
+SELECT
+    COALESCE(SUM(base_amount) FILTER (WHERE type = 'income'), 0)::numeric AS income,
+    COALESCE(SUM(base_amount) FILTER (WHERE type = 'expense'), 0)::numeric AS expenses,
+    COUNT(*) AS transaction_count
+FROM transactions
+WHERE user_id = @user_id
+  AND date >= @period_start
+  AND date < @period_end;
+
+-- name: ListCategorySpend :many
+SELECT c.id, c.name, c.budget_limit, SUM(t.base_amount)::numeric AS spent
+FROM transactions t
+JOIN categories c ON c.id = t.category_id
+WHERE t.user_id = @user_id
+  AND t.type = 'expense'
+  AND t.date >= @period_start
+  AND t.date < @period_end
+GROUP BY c.id, c.name, c.budget_limit
+ORDER BY spent DESC, c.name;
+
+-- name: ListLargestExpenses :many
+SELECT t.id, t.description, t.base_amount AS amount, t.date, c.name AS category_name
+FROM transactions t
+LEFT JOIN categories c ON c.id = t.category_id
+WHERE t.user_id = @user_id
+  AND t.type = 'expense'
+  AND t.date >= @period_start
+  AND t.date < @period_end
+ORDER BY t.base_amount DESC, t.date DESC
+LIMIT @row_limit;
@@ -0,0 +1,16 @@
+-- name: FindExchangeRate :one
+-- The latest rate stored between two currencies, in either direction, dated
+-- from @since to @until; a rate stored in the asked direction wins a tie.
+SELECT base_currency, rate, rate_date FROM exchange_rates
+WHERE ((base_currency = @from_currency AND quote_currency = @to_currency)
+    OR (base_currency = @to_currency AND quote_currency = @from_currency))
+  AND rate_date >= @since
+  AND rate_date <= @until
+ORDER BY rate_date DESC, base_currency = @from_currency DESC
+LIMIT 1;
+
+-- name: UpsertExchangeRate :exec
+INSERT INTO exchange_rates (base_currency, quote_currency, rate_date, rate, source)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (base_currency, quote_currency, rate_date) DO UPDATE
+SET rate = EXCLUDED.rate, source = EXCLUDED.source, created_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,16 @@
+-- name: ListDiscretionarySpend :many
+-- Day-to-day spending between two instants per account, NULL for
+-- transactions without one: expenses that neither settled a bill nor went
+-- to the payee of a recurring expense, which are forecast separately.
+SELECT t.account_id, SUM(t.base_amount)::numeric AS total
+FROM transactions t
+WHERE t.user_id = @user_id
+  AND t.type = 'expense'
+  AND t.date >= @period_start
+  AND t.date < @period_end
+  AND NOT EXISTS (SELECT 1 FROM bill_payments bp WHERE bp.transaction_id = t.id)
+  AND NOT EXISTS (
+    SELECT 1 FROM recurring_transactions rt
+    WHERE rt.user_id = t.user_id AND rt.type = 'expense' AND rt.payee_id = t.payee_id
+  )
+GROUP BY t.account_id;
@@ -0,0 +1,90 @@
+-- name: CreateGoal :one
+INSERT INTO goals (id, user_id, name, target_amount, target_date, account_id, category_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetGoal :one
+SELECT * FROM goals
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListGoalsByUser :many
+-- Goals with the total contributed to each.
+SELECT g.*, COALESCE(SUM(c.amount), 0)::numeric AS saved
+FROM goals g
+LEFT JOIN goal_contributions c ON c.goal_id = g.id
+WHERE g.user_id = @user_id
+GROUP BY g.id
+ORDER BY g.achieved_at IS NOT NULL, g.target_date NULLS LAST, g.name;
+
+-- name: UpdateGoal :one
+UPDATE goals
+SET name = $3,
+    target_amount = $4,
+    target_date = $5,
+    account_id = $6,
+    category_id = $7,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteGoal :exec
+DELETE FROM goals
+WHERE id = $1 AND user_id = $2;
+
+-- name: SetGoalAchieved :exec
+UPDATE goals
+SET achieved_at = $2
+WHERE id = $1;
+
+-- name: ListGoalsForTransaction :many
+-- Goals a transaction in the given category or account contributes to.
+SELECT * FROM goals
+WHERE user_id = @user_id
+  AND (category_id = sqlc.narg('category_id') OR account_id = sqlc.narg('account_id'))
+ORDER BY created_at;
+
+-- name: GetGoalSaved :one
+SELECT COALESCE(SUM(amount), 0)::numeric AS saved
+FROM goal_contributions
+WHERE goal_id = $1;
+
+-- name: CreateGoalContribution :one
+INSERT INTO goal_contributions (id, goal_id, user_id, amount, date, transaction_id, note)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListGoalContributions :many
+SELECT * FROM goal_contributions
+WHERE goal_id = $1 AND user_id = $2
+ORDER BY date DESC, created_at DESC;
+
+-- name: DeleteGoalContribution :one
+DELETE FROM goal_contributions
+WHERE id = $1 AND goal_id = $2 AND user_id = $3
+RETURNING *;
+
+-- name: DeleteGoalContributionsByTransaction :many
+-- Removes the contributions made by a transaction, returning the goals they
+-- were made to.
+DELETE FROM goal_contributions
+WHERE transaction_id = $1
+RETURNING goal_id;
+
+-- name: ListGoalMilestones :many
+SELECT * FROM goal_milestones
+WHERE goal_id = $1
+ORDER BY percent;
+
+-- name: CreateGoalMilestone :execrows
+INSERT INTO goal_milestones (goal_id, percent)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: SetGoalMilestoneNotification :exec
+UPDATE goal_milestones
+SET notification_id = $3
+WHERE goal_id = $1 AND percent = $2;
+
+-- name: DeleteGoalMilestone :exec
+DELETE FROM goal_milestones
+WHERE goal_id = $1 AND percent = $2;
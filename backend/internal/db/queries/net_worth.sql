@@ -0,0 +1,83 @@
+-- name: CreateNetWorthItem :one
+INSERT INTO net_worth_items (id, user_id, name, kind)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetNetWorthItem :one
+SELECT * FROM net_worth_items
+WHERE id = $1 AND user_id = $2;
+
+-- name: UpdateNetWorthItem :one
+UPDATE net_worth_items
+SET name = $3,
+    kind = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteNetWorthItem :exec
+DELETE FROM net_worth_items
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListNetWorthItemValues :many
+-- Items with their latest valuation on or before as_of; value and valued_on
+-- are null for items first valued later.
+SELECT i.*, v.value, v.valued_on
+FROM net_worth_items i
+LEFT JOIN LATERAL (
+    SELECT value, valued_on
+    FROM net_worth_valuations
+    WHERE item_id = i.id AND valued_on <= @as_of
+    ORDER BY valued_on DESC
+    LIMIT 1
+) v ON TRUE
+WHERE i.user_id = @user_id
+ORDER BY i.kind, i.name;
+
+-- name: UpsertNetWorthValuation :one
+INSERT INTO net_worth_valuations (id, item_id, user_id, value, valued_on)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (item_id, valued_on) DO UPDATE
+SET value = EXCLUDED.value
+RETURNING *;
+
+-- name: ListNetWorthValuations :many
+SELECT * FROM net_worth_valuations
+WHERE item_id = $1 AND user_id = $2
+ORDER BY valued_on DESC;
+
+-- name: DeleteNetWorthValuation :exec
+DELETE FROM net_worth_valuations
+WHERE id = $1 AND item_id = $2 AND user_id = $3;
+
+-- name: ListNetWorthSnapshotUsers :many
+-- Every user with the date of their latest snapshot, if any.
+SELECT u.id, u.timezone, MAX(s.snapshot_date)::date AS latest
+FROM users u
+LEFT JOIN net_worth_snapshots s ON s.user_id = u.id
+GROUP BY u.id
+ORDER BY u.id;
+
+-- name: UpsertNetWorthSnapshot :one
+INSERT INTO net_worth_snapshots (
+    user_id, snapshot_date, account_assets, item_assets, total_assets,
+    account_liabilities, debt_liabilities, item_liabilities, total_liabilities, net_worth
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (user_id, snapshot_date) DO UPDATE
+SET account_assets = EXCLUDED.account_assets,
+    item_assets = EXCLUDED.item_assets,
+    total_assets = EXCLUDED.total_assets,
+    account_liabilities = EXCLUDED.account_liabilities,
+    debt_liabilities = EXCLUDED.debt_liabilities,
+    item_liabilities = EXCLUDED.item_liabilities,
+    total_liabilities = EXCLUDED.total_liabilities,
+    net_worth = EXCLUDED.net_worth,
+    created_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListNetWorthSnapshots :many
+SELECT * FROM net_worth_snapshots
+WHERE user_id = @user_id
+  AND snapshot_date BETWEEN @from_date AND @to_date
+ORDER BY snapshot_date;
@@ -0,0 +1,71 @@
+-- name: CreateOutboxMessage :exec
+INSERT INTO notification_outbox (id, notification_id, user_id, channel, payload, next_attempt_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (notification_id, channel) DO NOTHING;
+
+-- name: ClaimOutboxMessages :many
+-- Claimed rows are leased until lease_until; if the worker dies mid-send
+-- they become due again and are retried.
+UPDATE notification_outbox
+SET attempts = attempts + 1, next_attempt_at = @lease_until, updated_at = CURRENT_TIMESTAMP
+WHERE id IN (
+    SELECT o.id FROM notification_outbox o
+    WHERE o.status = 'pending' AND o.next_attempt_at <= CURRENT_TIMESTAMP
+    ORDER BY o.next_attempt_at
+    LIMIT @row_limit
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkOutboxMessageSent :exec
+UPDATE notification_outbox
+SET status = 'sent', last_error = NULL, sent_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: MarkOutboxMessageFailed :exec
+UPDATE notification_outbox
+SET status = $2, last_error = $3, next_attempt_at = $4, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeferOutboxMessage :exec
+UPDATE notification_outbox
+SET attempts = attempts - 1, next_attempt_at = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RescheduleHeldOutboxMessages :execrows
+-- Messages held back by quiet hours and not yet tried become due now, so the
+-- worker checks them against the user's changed preferences. Leased messages
+-- and those waiting to retry have been tried and keep their time.
+UPDATE notification_outbox
+SET next_attempt_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND status = 'pending' AND attempts = 0 AND next_attempt_at > CURRENT_TIMESTAMP;
+
+-- name: CancelOutboxMessage :exec
+UPDATE notification_outbox
+SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteFinishedOutboxMessages :execrows
+DELETE FROM notification_outbox
+WHERE status IN ('sent', 'cancelled') AND updated_at < $1;
+
+-- name: ListOutboxMessages :many
+SELECT * FROM notification_outbox
+WHERE (sqlc.narg('status')::outbox_status IS NULL OR status = sqlc.narg('status'))
+ORDER BY updated_at DESC, id
+LIMIT @row_limit OFFSET @row_offset;
+
+-- name: GetOutboxMessage :one
+SELECT * FROM notification_outbox
+WHERE id = $1;
+
+-- name: ReplayOutboxMessage :one
+UPDATE notification_outbox
+SET status = 'pending', attempts = 0, last_error = NULL, next_attempt_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'dead'
+RETURNING *;
+
+-- name: ReplayDeadOutboxMessages :execrows
+UPDATE notification_outbox
+SET status = 'pending', attempts = 0, last_error = NULL, next_attempt_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE status = 'dead';
@@ -0,0 +1,23 @@
+-- name: ListNotificationPreferences :many
+SELECT * FROM notification_preferences
+WHERE user_id = $1;
+
+-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, type, channel, enabled)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, type, channel) DO UPDATE
+SET enabled = EXCLUDED.enabled, updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetNotificationSettings :one
+SELECT * FROM notification_settings
+WHERE user_id = $1;
+
+-- name: UpsertNotificationSettings :one
+INSERT INTO notification_settings (user_id, quiet_hours_start, quiet_hours_end, digest_frequency)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET quiet_hours_start = EXCLUDED.quiet_hours_start,
+    quiet_hours_end = EXCLUDED.quiet_hours_end,
+    digest_frequency = EXCLUDED.digest_frequency,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
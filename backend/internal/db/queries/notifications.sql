@@ -0,0 +1,40 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (id, user_id, title, message, type, date)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetNotification :one
+SELECT * FROM notifications
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListNotificationsByUser :many
+SELECT * FROM notifications
+WHERE user_id = @user_id
+  AND (sqlc.narg('type')::notification_type IS NULL OR type = sqlc.narg('type'))
+  AND (sqlc.narg('is_read')::boolean IS NULL OR is_read = sqlc.narg('is_read'))
+ORDER BY date DESC, created_at DESC
+LIMIT @row_limit OFFSET @row_offset;
+
+-- name: UpdateNotification :one
+UPDATE notifications
+SET title = $3, message = $4, type = $5, is_read = $6, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteNotification :execrows
+DELETE FROM notifications
+WHERE id = $1 AND user_id = $2;
+
+-- name: MarkNotificationsRead :execrows
+UPDATE notifications
+SET is_read = TRUE, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = @user_id AND id = ANY(@notification_ids::uuid[]) AND NOT is_read;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET is_read = TRUE, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $1 AND NOT is_read;
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) FROM notifications
+WHERE user_id = $1 AND NOT is_read;
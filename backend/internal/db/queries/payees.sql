@@ -0,0 +1,67 @@
+-- name: CreatePayee :one
+INSERT INTO payees (id, user_id, name, normalized_name)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetPayee :one
+SELECT * FROM payees
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetPayeeByAlias :one
+SELECT p.* FROM payees p
+JOIN payee_aliases a ON a.payee_id = p.id
+WHERE a.user_id = $1 AND a.alias = $2;
+
+-- name: ListPayeesByUser :many
+SELECT * FROM payees
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: UpdatePayeeName :one
+UPDATE payees
+SET name = $3, normalized_name = $4, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeletePayee :execrows
+DELETE FROM payees
+WHERE id = $1 AND user_id = $2;
+
+-- name: DeletePayees :exec
+DELETE FROM payees
+WHERE user_id = @user_id AND id = ANY(@payee_ids::uuid[]);
+
+-- name: AddPayeeAlias :exec
+INSERT INTO payee_aliases (user_id, alias, payee_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, alias) DO NOTHING;
+
+-- name: ListPayeeAliases :many
+SELECT alias FROM payee_aliases
+WHERE payee_id = $1
+ORDER BY alias;
+
+-- name: MovePayeeAliases :exec
+UPDATE payee_aliases
+SET payee_id = @target_id
+WHERE user_id = @user_id AND payee_id = ANY(@source_ids::uuid[]);
+
+-- name: MovePayeeTransactions :execrows
+UPDATE transactions
+SET payee_id = @target_id, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = @user_id AND payee_id = ANY(@source_ids::uuid[]);
+
+-- name: SummarizePayeeSpending :many
+SELECT p.id AS payee_id, p.name,
+       date_trunc(@bucket::text, t.date)::timestamptz AS period,
+       COUNT(t.id) AS transaction_count,
+       COALESCE(SUM(t.base_amount), 0)::numeric AS total
+FROM transactions t
+JOIN payees p ON p.id = t.payee_id
+WHERE t.user_id = @user_id
+  AND t.type = 'expense'
+  AND (sqlc.narg('payee_id')::uuid IS NULL OR t.payee_id = sqlc.narg('payee_id'))
+  AND (sqlc.narg('from_date')::timestamptz IS NULL OR t.date >= sqlc.narg('from_date'))
+  AND (sqlc.narg('to_date')::timestamptz IS NULL OR t.date <= sqlc.narg('to_date'))
+GROUP BY p.id, period
+ORDER BY period, total DESC;
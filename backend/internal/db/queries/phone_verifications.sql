@@ -0,0 +1,26 @@
+-- name: UpsertPhoneVerification :one
+INSERT INTO phone_verifications (user_id, phone_number, code_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+SET phone_number = EXCLUDED.phone_number,
+    code_hash = EXCLUDED.code_hash,
+    attempts = 0,
+    expires_at = EXCLUDED.expires_at,
+    created_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetPhoneVerification :one
+SELECT * FROM phone_verifications
+WHERE user_id = $1;
+
+-- name: ClaimPhoneVerificationAttempt :one
+-- Uses up one attempt before the code is compared, so parallel guesses
+-- together get no more than @max_attempts tries.
+UPDATE phone_verifications
+SET attempts = attempts + 1
+WHERE user_id = @user_id AND attempts < @max_attempts AND expires_at > CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeletePhoneVerification :exec
+DELETE FROM phone_verifications
+WHERE user_id = $1;
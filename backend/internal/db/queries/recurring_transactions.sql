@@ -0,0 +1,24 @@
+-- name: CreateRecurringTransaction :one
+INSERT INTO recurring_transactions (id, user_id, description, amount, type, category_id, payee_id, frequency, interval_count, start_date, end_date, account_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING *;
+
+-- name: GetRecurringTransaction :one
+SELECT * FROM recurring_transactions
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListRecurringTransactionsByUser :many
+SELECT * FROM recurring_transactions
+WHERE user_id = $1
+ORDER BY start_date, description;
+
+-- name: UpdateRecurringTransaction :one
+UPDATE recurring_transactions
+SET description = $3, amount = $4, type = $5, category_id = $6, payee_id = $7, frequency = $8,
+    interval_count = $9, start_date = $10, end_date = $11, account_id = $12, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteRecurringTransaction :exec
+DELETE FROM recurring_transactions
+WHERE id = $1 AND user_id = $2;
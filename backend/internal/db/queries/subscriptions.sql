@@ -0,0 +1,58 @@
+-- name: ListSubscriptionScanUsers :many
+-- Users with expenses to a payee since @since.
+SELECT DISTINCT user_id FROM transactions
+WHERE type = 'expense' AND payee_id IS NOT NULL AND date >= @since;
+
+-- name: ListPayeeExpenses :many
+SELECT payee_id, base_amount AS amount, date, category_id, account_id
+FROM transactions
+WHERE user_id = @user_id
+  AND type = 'expense'
+  AND payee_id IS NOT NULL
+  AND date >= @since
+ORDER BY payee_id, date;
+
+-- name: UpsertDetectedSubscription :exec
+-- Proposes a subscription, refreshing one still pending. Confirmed and
+-- dismissed subscriptions are left alone.
+INSERT INTO detected_subscriptions (id, user_id, payee_id, frequency, amount, category_id, account_id, charge_count, last_charged_on, next_expected_date, annual_cost)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (user_id, payee_id, frequency) DO UPDATE
+SET amount = EXCLUDED.amount,
+    category_id = EXCLUDED.category_id,
+    account_id = EXCLUDED.account_id,
+    charge_count = EXCLUDED.charge_count,
+    last_charged_on = EXCLUDED.last_charged_on,
+    next_expected_date = EXCLUDED.next_expected_date,
+    annual_cost = EXCLUDED.annual_cost,
+    updated_at = CURRENT_TIMESTAMP
+WHERE detected_subscriptions.status = 'pending';
+
+-- name: DeleteStaleDetectedSubscriptions :exec
+-- Withdraws pending proposals other than the given payee and frequency
+-- pairs, those a scan found.
+DELETE FROM detected_subscriptions s
+WHERE s.user_id = @user_id
+  AND s.status = 'pending'
+  AND NOT EXISTS (
+    SELECT 1 FROM unnest(@payee_ids::uuid[], @frequencies::text[]) AS k(payee_id, frequency)
+    WHERE k.payee_id = s.payee_id AND k.frequency = s.frequency::text
+  );
+
+-- name: ListDetectedSubscriptions :many
+SELECT s.*, p.name AS payee_name
+FROM detected_subscriptions s
+JOIN payees p ON p.id = s.payee_id
+WHERE s.user_id = @user_id
+  AND (sqlc.narg('status')::subscription_status IS NULL OR s.status = sqlc.narg('status'))
+ORDER BY s.status, s.next_expected_date, p.name;
+
+-- name: GetDetectedSubscription :one
+SELECT * FROM detected_subscriptions
+WHERE id = $1 AND user_id = $2;
+
+-- name: SetDetectedSubscriptionStatus :one
+UPDATE detected_subscriptions
+SET status = $3, recurring_transaction_id = $4, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
@@ -0,0 +1,56 @@
+-- name: GetSummaryTotals :one
+-- Income, expenses, net and savings rate (percent of income saved, NULL
+-- without income) between two instants, in exact NUMERIC arithmetic.
+SELECT
+    s.income,
+    s.expenses,
+    (s.income - s.expenses)::numeric AS net,
+    (CASE WHEN s.income > 0 THEN ROUND((s.income - s.expenses) * 100 / s.income, 2) END)::numeric AS savings_rate,
+    s.uncategorized_expenses,
+    s.transaction_count
+FROM (
+    SELECT
+        COALESCE(SUM(base_amount) FILTER (WHERE type = 'income'), 0)::numeric AS income,
+        COALESCE(SUM(base_amount) FILTER (WHERE type = 'expense'), 0)::numeric AS expenses,
+        COALESCE(SUM(base_amount) FILTER (WHERE type = 'expense' AND category_id IS NULL), 0)::numeric AS uncategorized_expenses,
+        COUNT(*) AS transaction_count
+    FROM transactions
+    WHERE user_id = @user_id
+      AND date >= @period_start
+      AND date < @period_end
+) s;
+
+-- name: ListCategorySummary :many
+-- Each category's planned amount (its monthly budget limit scaled by
+-- plan_numerator / plan_denominator months), actual total and what remains,
+-- between two instants. Planned, remaining and percent_used are NULL for
+-- categories without a limit.
+SELECT
+    s.id,
+    s.name,
+    s.color,
+    s.type,
+    s.planned,
+    s.actual,
+    (s.planned - s.actual)::numeric AS remaining,
+    (CASE WHEN s.planned > 0 THEN ROUND(s.actual * 100 / s.planned, 2) END)::numeric AS percent_used,
+    s.transaction_count
+FROM (
+    SELECT
+        c.id,
+        c.name,
+        c.color,
+        c.type,
+        ROUND(c.budget_limit * @plan_numerator::int / @plan_denominator::int, 2)::numeric AS planned,
+        COALESCE(SUM(t.base_amount), 0)::numeric AS actual,
+        COUNT(t.id) AS transaction_count
+    FROM categories c
+    LEFT JOIN transactions t
+        ON t.category_id = c.id
+       AND t.type = c.type
+       AND t.date >= @period_start
+       AND t.date < @period_end
+    WHERE c.user_id = @user_id
+    GROUP BY c.id
+) s
+ORDER BY s.type, s.actual DESC, s.name;
@@ -0,0 +1,54 @@
+-- name: CreateTag :one
+INSERT INTO tags (id, user_id, name, color)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetTag :one
+SELECT * FROM tags
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListTagsByUser :many
+SELECT * FROM tags
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: UpdateTag :one
+UPDATE tags
+SET name = $3, color = $4, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteTag :execrows
+DELETE FROM tags
+WHERE id = $1 AND user_id = $2;
+
+-- name: AddTransactionTags :execrows
+INSERT INTO transaction_tags (transaction_id, tag_id)
+SELECT @transaction_id::uuid, id FROM tags
+WHERE user_id = @user_id AND id = ANY(@tag_ids::uuid[])
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteTransactionTags :exec
+DELETE FROM transaction_tags
+WHERE transaction_id = $1;
+
+-- name: ListTagsForTransactions :many
+SELECT tt.transaction_id, t.id, t.name, t.color
+FROM transaction_tags tt
+JOIN tags t ON t.id = tt.tag_id
+WHERE tt.transaction_id = ANY(@transaction_ids::uuid[])
+ORDER BY t.name;
+
+-- name: SummarizeTagSpending :many
+SELECT tg.id, tg.name, tg.color,
+       COUNT(t.id) AS transaction_count,
+       COALESCE(SUM(t.base_amount) FILTER (WHERE t.type = 'expense'), 0)::numeric AS total_expense,
+       COALESCE(SUM(t.base_amount) FILTER (WHERE t.type = 'income'), 0)::numeric AS total_income
+FROM tags tg
+LEFT JOIN transaction_tags tt ON tt.tag_id = tg.id
+LEFT JOIN transactions t ON t.id = tt.transaction_id
+  AND (sqlc.narg('from_date')::timestamptz IS NULL OR t.date >= sqlc.narg('from_date'))
+  AND (sqlc.narg('to_date')::timestamptz IS NULL OR t.date <= sqlc.narg('to_date'))
+WHERE tg.user_id = @user_id
+GROUP BY tg.id
+ORDER BY total_expense DESC, tg.name;
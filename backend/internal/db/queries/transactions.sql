@@ -0,0 +1,53 @@
+-- name: CreateTransaction :one
+INSERT INTO transactions (id, user_id, amount, description, category_id, date, type, payee_id, account_id, currency, exchange_rate, base_amount)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING *;
+
+-- name: GetTransaction :one
+SELECT * FROM transactions
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListTransactionsByUser :many
+SELECT * FROM transactions
+WHERE user_id = @user_id
+  AND (sqlc.narg('from_date')::timestamptz IS NULL OR date >= sqlc.narg('from_date'))
+  AND (sqlc.narg('to_date')::timestamptz IS NULL OR date <= sqlc.narg('to_date'))
+  AND (
+    cardinality(@tag_ids::uuid[]) = 0
+    OR (NOT @match_all_tags::boolean AND EXISTS (
+      SELECT 1 FROM transaction_tags tt
+      WHERE tt.transaction_id = transactions.id AND tt.tag_id = ANY(@tag_ids::uuid[])
+    ))
+    OR (@match_all_tags::boolean AND (
+      SELECT COUNT(DISTINCT tt.tag_id) FROM transaction_tags tt
+      WHERE tt.transaction_id = transactions.id AND tt.tag_id = ANY(@tag_ids::uuid[])
+    ) = cardinality(@tag_ids::uuid[]))
+  )
+ORDER BY date DESC, created_at DESC
+LIMIT @row_limit OFFSET @row_offset;
+
+-- name: ListCategorizedTransactions :many
+SELECT * FROM transactions
+WHERE user_id = $1 AND category_id IS NOT NULL
+ORDER BY date;
+
+-- name: UpdateTransaction :one
+UPDATE transactions
+SET amount = $3, description = $4, category_id = $5, date = $6, type = $7, payee_id = $8, account_id = $9,
+    currency = $10, exchange_rate = $11, base_amount = $12, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteTransaction :one
+DELETE FROM transactions
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: ListTransactionsWithoutPayee :many
+SELECT id, description FROM transactions
+WHERE user_id = $1 AND payee_id IS NULL AND description IS NOT NULL;
+
+-- name: SetTransactionPayee :exec
+UPDATE transactions
+SET payee_id = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2;
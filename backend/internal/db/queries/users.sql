@@ -0,0 +1,29 @@
+-- name: GetUser :one
+SELECT * FROM users
+WHERE id = $1;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1;
+
+-- name: SetUserPhone :exec
+UPDATE users
+SET phone_number = $2, phone_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: ClearUserPhone :execrows
+UPDATE users
+SET phone_number = NULL, phone_verified_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND phone_number IS NOT NULL;
+
+-- name: SetUserTimezone :exec
+UPDATE users
+SET timezone = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: LockUser :exec
+-- Holds the user's row until the transaction ends, so per-user checks such as
+-- the attachment quota see each other's writes.
+SELECT id FROM users
+WHERE id = $1
+FOR UPDATE;
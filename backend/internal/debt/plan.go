@@ -0,0 +1,304 @@
+// Package debt plans how debts are paid off: which to pay down first, what
+// each month's payments are, and what the interest comes to.
+package debt
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+)
+
+// Strategy decides which debt money beyond the minimum payments goes to.
+type Strategy string
+
+const (
+	// Avalanche pays the highest interest rate first, which costs the least
+	// interest.
+	Avalanche Strategy = "avalanche"
+	// Snowball pays the smallest balance first, which clears debts soonest.
+	Snowball Strategy = "snowball"
+	// Custom pays debts in the order the user chooses.
+	Custom Strategy = "custom"
+)
+
+// maxMonths is how long a plan may run, 50 years.
+const maxMonths = 600
+
+// ErrNeverPaidOff is returned when the payments do not clear the debts
+// within maxMonths, e.g. because they do not cover the interest.
+var ErrNeverPaidOff = errors.New("the payments would not pay the debts off within 50 years")
+
+// Payment is one month's payment towards a debt.
+type Payment struct {
+	DebtID   pgtype.UUID   `json:"debtId"`
+	DueDate  pgtype.Date   `json:"dueDate"`
+	Interest money.Decimal `json:"interest"`
+	Payment  money.Decimal `json:"payment"`
+	// Balance is what is owed after the payment.
+	Balance money.Decimal `json:"balance"`
+}
+
+// Month is one month of a plan.
+type Month struct {
+	// Month is the first day of the month.
+	Month    pgtype.Date   `json:"month"`
+	Payments []Payment     `json:"payments"`
+	Interest money.Decimal `json:"interest"`
+	Paid     money.Decimal `json:"paid"`
+	Balance  money.Decimal `json:"balance"`
+}
+
+// Summary is how one debt fares under a plan.
+type Summary struct {
+	DebtID pgtype.UUID `json:"debtId"`
+	Name   string      `json:"name"`
+	// Order is the debt's place, from 1, in line for extra payments.
+	Order         int           `json:"order"`
+	PayoffDate    pgtype.Date   `json:"payoffDate"`
+	Months        int           `json:"months"`
+	TotalInterest money.Decimal `json:"totalInterest"`
+	TotalPaid     money.Decimal `json:"totalPaid"`
+}
+
+// Plan is a month-by-month schedule paying every debt off.
+type Plan struct {
+	Strategy     Strategy      `json:"strategy"`
+	ExtraPayment money.Decimal `json:"extraPayment"`
+	// MonthlyPayment is the minimum payments plus the extra payment, paid
+	// every month until the last debt is cleared.
+	MonthlyPayment money.Decimal `json:"monthlyPayment"`
+	Months         int           `json:"months"`
+	PayoffDate     pgtype.Date   `json:"payoffDate"`
+	TotalInterest  money.Decimal `json:"totalInterest"`
+	TotalPaid      money.Decimal `json:"totalPaid"`
+	Debts          []Summary     `json:"debts"`
+	Schedule       []Month       `json:"schedule"`
+}
+
+// account is a debt as the simulation runs.
+type account struct {
+	debt     db.Debt
+	balance  *big.Rat
+	rate     *big.Rat
+	minimum  *big.Rat
+	interest *big.Rat
+	paid     *big.Rat
+	summary  *Summary
+}
+
+// Simulate pays debts off from today, in the user's time zone.
+//
+// Every month each debt accrues a twelfth of its APR on its balance and is
+// paid its minimum payment, then what is left of the monthly payment goes to
+// the debts in strategy order. The monthly payment stays the same
+// throughout, so the minimum payment of a cleared debt rolls over to the
+// next. For Custom, debts are taken in the given order, followed by any
+// left out in avalanche order.
+//
+// The schedule starts this month if no debt's due day has passed yet, and
+// next month otherwise. Debts with nothing owed are left out.
+func Simulate(debts []db.Debt, strategy Strategy, order []pgtype.UUID, extra *big.Rat, today time.Time) (Plan, error) {
+	today = recurrence.Day(today)
+	var accounts []*account
+	monthly := new(big.Rat).Set(extra)
+	for _, d := range debts {
+		a := &account{
+			debt:     d,
+			balance:  d.Balance.Rat(),
+			rate:     new(big.Rat).Quo(d.Apr.Rat(), big.NewRat(1200, 1)),
+			minimum:  d.MinimumPayment.Rat(),
+			interest: new(big.Rat),
+			paid:     new(big.Rat),
+		}
+		if a.balance.Sign() <= 0 {
+			continue
+		}
+		monthly.Add(monthly, a.minimum)
+		accounts = append(accounts, a)
+	}
+	prioritize(accounts, strategy, order)
+
+	plan := Plan{
+		Strategy:       strategy,
+		ExtraPayment:   money.Amount(extra),
+		MonthlyPayment: money.Amount(monthly),
+		TotalInterest:  money.Amount(new(big.Rat)),
+		TotalPaid:      money.Amount(new(big.Rat)),
+		Debts:          make([]Summary, len(accounts)),
+		Schedule:       []Month{},
+	}
+	for i, a := range accounts {
+		plan.Debts[i] = Summary{DebtID: a.debt.ID, Name: a.debt.Name, Order: i + 1}
+		a.summary = &plan.Debts[i]
+	}
+	if len(accounts) == 0 {
+		return plan, nil
+	}
+
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for _, a := range accounts {
+		if dueDate(month, a.debt.DueDay).Before(today) {
+			month = month.AddDate(0, 1, 0)
+			break
+		}
+	}
+
+	totalInterest, totalPaid := new(big.Rat), new(big.Rat)
+	owed := total(accounts)
+	for m := 0; owed.Sign() > 0; m++ {
+		if m == maxMonths {
+			return Plan{}, ErrNeverPaidOff
+		}
+		var open []*account
+		for _, a := range accounts {
+			if a.balance.Sign() > 0 {
+				open = append(open, a)
+			}
+		}
+		payments := make(map[*account]*big.Rat, len(open))
+		interest := make(map[*account]*big.Rat, len(open))
+		for _, a := range open {
+			interest[a] = cents(new(big.Rat).Mul(a.balance, a.rate))
+			a.balance.Add(a.balance, interest[a])
+			a.interest.Add(a.interest, interest[a])
+			payments[a] = new(big.Rat)
+		}
+		available := new(big.Rat).Set(monthly)
+		pay := func(a *account, limit *big.Rat) {
+			amount := minRat(limit, a.balance, available)
+			if amount.Sign() <= 0 {
+				return
+			}
+			a.balance.Sub(a.balance, amount)
+			available.Sub(available, amount)
+			payments[a].Add(payments[a], amount)
+		}
+		for _, a := range open {
+			pay(a, a.minimum)
+		}
+		for _, a := range open {
+			pay(a, available)
+		}
+
+		row := Month{Month: recurrence.Date(month), Payments: make([]Payment, len(open))}
+		monthInterest, monthPaid := new(big.Rat), new(big.Rat)
+		for i, a := range open {
+			due := recurrence.Date(dueDate(month, a.debt.DueDay))
+			a.paid.Add(a.paid, payments[a])
+			monthInterest.Add(monthInterest, interest[a])
+			monthPaid.Add(monthPaid, payments[a])
+			row.Payments[i] = Payment{
+				DebtID:   a.debt.ID,
+				DueDate:  due,
+				Interest: money.Amount(interest[a]),
+				Payment:  money.Amount(payments[a]),
+				Balance:  money.Amount(a.balance),
+			}
+			if a.balance.Sign() == 0 {
+				a.summary.PayoffDate = due
+				a.summary.Months = m + 1
+				if plan.PayoffDate.Time.Before(due.Time) {
+					plan.PayoffDate = due
+				}
+			}
+		}
+		remaining := total(accounts)
+		if remaining.Cmp(owed) >= 0 {
+			return Plan{}, ErrNeverPaidOff
+		}
+		owed = remaining
+		totalInterest.Add(totalInterest, monthInterest)
+		totalPaid.Add(totalPaid, monthPaid)
+		row.Interest = money.Amount(monthInterest)
+		row.Paid = money.Amount(monthPaid)
+		row.Balance = money.Amount(owed)
+		plan.Schedule = append(plan.Schedule, row)
+		month = month.AddDate(0, 1, 0)
+	}
+
+	for _, a := range accounts {
+		a.summary.TotalInterest = money.Amount(a.interest)
+		a.summary.TotalPaid = money.Amount(a.paid)
+	}
+	plan.Months = len(plan.Schedule)
+	plan.TotalInterest = money.Amount(totalInterest)
+	plan.TotalPaid = money.Amount(totalPaid)
+	return plan, nil
+}
+
+// prioritize sorts accounts into the order extra payments go to them.
+func prioritize(accounts []*account, strategy Strategy, order []pgtype.UUID) {
+	avalanche := func(a, b *account) bool {
+		if c := a.rate.Cmp(b.rate); c != 0 {
+			return c > 0
+		}
+		return a.balance.Cmp(b.balance) < 0
+	}
+	rank := make(map[pgtype.UUID]int, len(order))
+	if strategy == Custom {
+		for i, id := range order {
+			if _, ok := rank[id]; !ok {
+				rank[id] = i
+			}
+		}
+	}
+	sort.SliceStable(accounts, func(i, j int) bool {
+		a, b := accounts[i], accounts[j]
+		switch strategy {
+		case Snowball:
+			if c := a.balance.Cmp(b.balance); c != 0 {
+				return c < 0
+			}
+		case Custom:
+			ra, aok := rank[a.debt.ID]
+			rb, bok := rank[b.debt.ID]
+			switch {
+			case aok && bok:
+				return ra < rb
+			case aok != bok:
+				return aok
+			}
+		}
+		return avalanche(a, b)
+	})
+}
+
+// dueDate is the debt's due day in month, or the month's last day if it is
+// shorter.
+func dueDate(month time.Time, day int32) time.Time {
+	last := month.AddDate(0, 1, -1).Day()
+	if int(day) > last {
+		day = int32(last)
+	}
+	return time.Date(month.Year(), month.Month(), int(day), 0, 0, 0, 0, time.UTC)
+}
+
+func total(accounts []*account) *big.Rat {
+	sum := new(big.Rat)
+	for _, a := range accounts {
+		sum.Add(sum, a.balance)
+	}
+	return sum
+}
+
+func minRat(rs ...*big.Rat) *big.Rat {
+	m := rs[0]
+	for _, r := range rs[1:] {
+		if r.Cmp(m) < 0 {
+			m = r
+		}
+	}
+	return new(big.Rat).Set(m)
+}
+
+// cents rounds r to the nearest cent.
+func cents(r *big.Rat) *big.Rat {
+	rounded, _ := new(big.Rat).SetString(r.FloatString(2))
+	return rounded
+}
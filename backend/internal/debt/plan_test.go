@@ -0,0 +1,206 @@
+package debt
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+)
+
+func id(b byte) pgtype.UUID {
+	return pgtype.UUID{Bytes: [16]byte{b}, Valid: true}
+}
+
+func amount(s string) money.Decimal {
+	d, err := money.Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func newDebt(b byte, balance, apr, minimum string, dueDay int32) db.Debt {
+	return db.Debt{
+		ID:             id(b),
+		Name:           string('A' + b - 1),
+		Balance:        amount(balance),
+		Apr:            amount(apr),
+		MinimumPayment: amount(minimum),
+		DueDay:         dueDay,
+	}
+}
+
+func day(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func dateString(d pgtype.Date) string {
+	return d.Time.Format(time.DateOnly)
+}
+
+func TestSimulateOrder(t *testing.T) {
+	debts := []db.Debt{
+		newDebt(1, "1000", "20", "50", 15),
+		newDebt(2, "500", "10", "50", 15),
+		newDebt(3, "2000", "15", "50", 15),
+	}
+	tests := []struct {
+		name     string
+		strategy Strategy
+		order    []pgtype.UUID
+		want     []byte
+	}{
+		{"avalanche", Avalanche, nil, []byte{1, 3, 2}},
+		{"snowball", Snowball, nil, []byte{2, 1, 3}},
+		{"custom", Custom, []pgtype.UUID{id(2), id(1), id(3)}, []byte{2, 1, 3}},
+		{"custom leaving debts out", Custom, []pgtype.UUID{id(3)}, []byte{3, 1, 2}},
+		{"custom listing a debt twice", Custom, []pgtype.UUID{id(2), id(3), id(2)}, []byte{2, 3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := Simulate(debts, tt.strategy, tt.order, big.NewRat(100, 1), day("2026-01-01"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plan.Debts) != len(tt.want) {
+				t.Fatalf("got %d debts, want %d", len(plan.Debts), len(tt.want))
+			}
+			for i, b := range tt.want {
+				if plan.Debts[i].DebtID != id(b) || plan.Debts[i].Order != i+1 {
+					t.Errorf("debt %d is %s (order %d), want %c", i+1, plan.Debts[i].Name, plan.Debts[i].Order, 'A'+b-1)
+				}
+			}
+			// The extra goes to the first debt in line.
+			first := plan.Schedule[0].Payments
+			for _, p := range first {
+				want := "50.00"
+				if p.DebtID == id(tt.want[0]) {
+					want = "150.00"
+				}
+				if p.Payment.String() != want {
+					t.Errorf("first payment to %v = %s, want %s", p.DebtID, p.Payment, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSimulateRollsMinimumOver(t *testing.T) {
+	debts := []db.Debt{
+		newDebt(1, "100", "0", "100", 1),
+		newDebt(2, "1000", "0", "100", 1),
+	}
+	plan, err := Simulate(debts, Avalanche, nil, new(big.Rat), day("2026-01-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.MonthlyPayment.String() != "200.00" {
+		t.Errorf("monthly payment = %s, want 200.00", plan.MonthlyPayment)
+	}
+	// B gets 100 while A is open, then A's 100 as well: 900, 700, ..., 100, 0.
+	if plan.Months != 6 {
+		t.Fatalf("months = %d, want 6", plan.Months)
+	}
+	if second := plan.Schedule[1].Payments; len(second) != 1 || second[0].DebtID != id(2) || second[0].Payment.String() != "200.00" {
+		t.Errorf("second month = %+v, want 200.00 to B only", second)
+	}
+	if a := plan.Debts[0]; a.DebtID != id(1) || a.Months != 1 {
+		t.Errorf("first debt %s paid off after %d months, want A after 1", a.Name, a.Months)
+	}
+	if got := dateString(plan.PayoffDate); got != "2026-06-01" {
+		t.Errorf("payoff date = %s, want 2026-06-01", got)
+	}
+	if plan.TotalPaid.String() != "1100.00" || plan.TotalInterest.String() != "0.00" {
+		t.Errorf("paid %s with %s interest, want 1100.00 with none", plan.TotalPaid, plan.TotalInterest)
+	}
+}
+
+func TestSimulateInterest(t *testing.T) {
+	// 1% a month: 10.00 on 1000, then 5.00 on the 500 left.
+	plan, err := Simulate([]db.Debt{newDebt(1, "1000", "12", "510", 1)}, Avalanche, nil, new(big.Rat), day("2026-01-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Months != 2 {
+		t.Fatalf("months = %d, want 2", plan.Months)
+	}
+	if got := plan.Schedule[0].Payments[0].Balance.String(); got != "500.00" {
+		t.Errorf("balance after the first month = %s, want 500.00", got)
+	}
+	if plan.TotalInterest.String() != "15.00" || plan.TotalPaid.String() != "1015.00" {
+		t.Errorf("paid %s with %s interest, want 1015.00 with 15.00", plan.TotalPaid, plan.TotalInterest)
+	}
+}
+
+func TestSimulateNeverPaidOff(t *testing.T) {
+	// 2% a month is 200 of interest, more than the 150 paid.
+	debts := []db.Debt{newDebt(1, "10000", "24", "100", 1)}
+	if _, err := Simulate(debts, Avalanche, nil, big.NewRat(50, 1), day("2026-01-01")); !errors.Is(err, ErrNeverPaidOff) {
+		t.Errorf("err = %v, want ErrNeverPaidOff", err)
+	}
+	// Exactly covering the interest never pays anything down either.
+	if _, err := Simulate(debts, Avalanche, nil, big.NewRat(100, 1), day("2026-01-01")); !errors.Is(err, ErrNeverPaidOff) {
+		t.Errorf("err = %v, want ErrNeverPaidOff", err)
+	}
+}
+
+func TestSimulateSchedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		today     string
+		dueDays   []int32
+		wantMonth string
+		wantDue   []string
+	}{
+		{"due day still ahead", "2026-03-15", []int32{20}, "2026-03-01", []string{"2026-03-20", "2026-04-20", "2026-05-20"}},
+		{"due today", "2026-03-15", []int32{15}, "2026-03-01", []string{"2026-03-15", "2026-04-15", "2026-05-15"}},
+		{"due day passed", "2026-03-15", []int32{10}, "2026-04-01", []string{"2026-04-10", "2026-05-10", "2026-06-10"}},
+		{"one of two due days passed", "2026-03-15", []int32{20, 10}, "2026-04-01", []string{"2026-04-20", "2026-05-20", "2026-06-20"}},
+		{"31st in February", "2026-02-01", []int32{31}, "2026-02-01", []string{"2026-02-28", "2026-03-31", "2026-04-30"}},
+		{"31st in a leap February", "2028-02-10", []int32{31}, "2028-02-01", []string{"2028-02-29", "2028-03-31", "2028-04-30"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var debts []db.Debt
+			for i, d := range tt.dueDays {
+				debts = append(debts, newDebt(byte(i+1), "300", "0", "100", d))
+			}
+			plan, err := Simulate(debts, Avalanche, nil, new(big.Rat), day(tt.today))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plan.Schedule) != 3 {
+				t.Fatalf("schedule has %d months, want 3", len(plan.Schedule))
+			}
+			if got := dateString(plan.Schedule[0].Month); got != tt.wantMonth {
+				t.Errorf("first month = %s, want %s", got, tt.wantMonth)
+			}
+			for i, want := range tt.wantDue {
+				if got := dateString(plan.Schedule[i].Payments[0].DueDate); got != want {
+					t.Errorf("month %d due = %s, want %s", i+1, got, want)
+				}
+			}
+			if got := dateString(plan.Debts[0].PayoffDate); got != tt.wantDue[2] {
+				t.Errorf("payoff date = %s, want %s", got, tt.wantDue[2])
+			}
+		})
+	}
+}
+
+func TestSimulateSkipsClearedDebts(t *testing.T) {
+	plan, err := Simulate([]db.Debt{newDebt(1, "0", "10", "50", 1)}, Avalanche, nil, big.NewRat(10, 1), day("2026-01-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Debts) != 0 || len(plan.Schedule) != 0 || plan.Months != 0 {
+		t.Errorf("plan = %+v, want an empty one", plan)
+	}
+}
@@ -0,0 +1,237 @@
+// Package digest builds weekly and monthly spending summaries and sends them
+// to users once each period ends.
+package digest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/budget"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/mail"
+	"github.com/nyunja/30budget/backend/internal/notify"
+)
+
+const (
+	topCategoryCount    = 5
+	largestExpenseCount = 5
+)
+
+// Digest summarises a user's spending over one period.
+type Digest struct {
+	Frequency        db.DigestFrequency
+	Period           budget.Period
+	Income           *big.Rat
+	Expenses         *big.Rat
+	PreviousIncome   *big.Rat
+	PreviousExpenses *big.Rat
+	TransactionCount int64
+	TopCategories    []CategorySpend
+	// OverBudget lists categories whose spending for the month to the end
+	// of the period exceeds their monthly budget.
+	OverBudget      []CategorySpend
+	LargestExpenses []db.ListLargestExpensesRow
+}
+
+// CategorySpend is a category's expenses in a period. Limit is zero for
+// categories without a budget.
+type CategorySpend struct {
+	Name  string
+	Spent *big.Rat
+	Limit *big.Rat
+}
+
+// Periods returns the most recent period of frequency that has fully ended
+// by now in loc, and the period before it.
+func Periods(frequency db.DigestFrequency, now time.Time, loc *time.Location) (current, previous budget.Period) {
+	if frequency == db.DigestFrequencyMonthly {
+		current = budget.MonthOf(budget.MonthOf(now, loc).Start.AddDate(0, 0, -1), loc)
+		return current, budget.MonthOf(current.Start.AddDate(0, 0, -1), loc)
+	}
+	current = budget.WeekOf(budget.WeekOf(now, loc).Start.AddDate(0, 0, -1), loc)
+	return current, budget.WeekOf(current.Start.AddDate(0, 0, -1), loc)
+}
+
+// Build gathers the digest for period, comparing it with previous.
+func Build(ctx context.Context, q *db.Queries, userID pgtype.UUID, frequency db.DigestFrequency, period, previous budget.Period) (*Digest, error) {
+	totals, err := q.GetPeriodTotals(ctx, db.GetPeriodTotalsParams{
+		UserID:      userID,
+		PeriodStart: timestamptz(period.Start),
+		PeriodEnd:   timestamptz(period.End),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+	prevTotals, err := q.GetPeriodTotals(ctx, db.GetPeriodTotalsParams{
+		UserID:      userID,
+		PeriodStart: timestamptz(previous.Start),
+		PeriodEnd:   timestamptz(previous.End),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum previous transactions: %w", err)
+	}
+	d := &Digest{
+		Frequency:        frequency,
+		Period:           period,
+		Income:           totals.Income.Rat(),
+		Expenses:         totals.Expenses.Rat(),
+		PreviousIncome:   prevTotals.Income.Rat(),
+		PreviousExpenses: prevTotals.Expenses.Rat(),
+		TransactionCount: totals.TransactionCount,
+	}
+
+	categories, err := q.ListCategorySpend(ctx, db.ListCategorySpendParams{
+		UserID:      userID,
+		PeriodStart: timestamptz(period.Start),
+		PeriodEnd:   timestamptz(period.End),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list category spending: %w", err)
+	}
+	for i, c := range categories {
+		if i == topCategoryCount {
+			break
+		}
+		d.TopCategories = append(d.TopCategories, CategorySpend{Name: c.Name, Spent: c.Spent.Rat(), Limit: c.BudgetLimit.Rat()})
+	}
+
+	// Budgets are monthly, so a weekly digest checks the month so far.
+	month := budget.MonthOf(period.End.AddDate(0, 0, -1), period.End.Location())
+	monthSpend, err := q.ListCategorySpend(ctx, db.ListCategorySpendParams{
+		UserID:      userID,
+		PeriodStart: timestamptz(month.Start),
+		PeriodEnd:   timestamptz(period.End),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list monthly category spending: %w", err)
+	}
+	for _, c := range monthSpend {
+		spent, limit := c.Spent.Rat(), c.BudgetLimit.Rat()
+		if limit.Sign() > 0 && spent.Cmp(limit) > 0 {
+			d.OverBudget = append(d.OverBudget, CategorySpend{Name: c.Name, Spent: spent, Limit: limit})
+		}
+	}
+
+	d.LargestExpenses, err = q.ListLargestExpenses(ctx, db.ListLargestExpensesParams{
+		UserID:      userID,
+		PeriodStart: timestamptz(period.Start),
+		PeriodEnd:   timestamptz(period.End),
+		RowLimit:    largestExpenseCount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list largest expenses: %w", err)
+	}
+	return d, nil
+}
+
+// Empty reports whether the period had no transactions to summarise.
+func (d *Digest) Empty() bool {
+	return d.TransactionCount == 0
+}
+
+// Label names the period, e.g. "12 Oct – 18 Oct 2026" or "October 2026".
+func (d *Digest) Label() string {
+	if d.Frequency == db.DigestFrequencyMonthly {
+		return d.Period.Label()
+	}
+	return d.Period.Start.Format("2 Jan") + " – " + d.Period.End.AddDate(0, 0, -1).Format("2 Jan 2006")
+}
+
+// Comparison describes how spending changed from the previous period, or is
+// empty when there is nothing to compare with.
+func (d *Digest) Comparison() string {
+	if d.PreviousExpenses.Sign() == 0 {
+		return ""
+	}
+	change := new(big.Rat).Sub(d.Expenses, d.PreviousExpenses)
+	change.Quo(change.Mul(change, big.NewRat(100, 1)), d.PreviousExpenses)
+	percent, _ := change.Float64()
+	previous := "week"
+	if d.Frequency == db.DigestFrequencyMonthly {
+		previous = "month"
+	}
+	switch rounded := int(math.Round(percent)); {
+	case rounded > 0:
+		return fmt.Sprintf("Spending is up %d%% on the previous %s.", rounded, previous)
+	case rounded < 0:
+		return fmt.Sprintf("Spending is down %d%% on the previous %s.", -rounded, previous)
+	default:
+		return fmt.Sprintf("Spending is about the same as the previous %s.", previous)
+	}
+}
+
+// Notification is the in-app info notification for the digest.
+func (d *Digest) Notification(userID pgtype.UUID, symbol string) notify.Message {
+	title := "Your weekly summary"
+	if d.Frequency == db.DigestFrequencyMonthly {
+		title = "Your monthly summary"
+	}
+	parts := []string{fmt.Sprintf("%s: income %s%s, expenses %s%s.",
+		d.Label(), symbol, budget.FormatAmount(d.Income), symbol, budget.FormatAmount(d.Expenses))}
+	if comparison := d.Comparison(); comparison != "" {
+		parts = append(parts, comparison)
+	}
+	if len(d.TopCategories) > 0 {
+		top := d.TopCategories[0]
+		parts = append(parts, fmt.Sprintf("Top category: %s (%s%s).", top.Name, symbol, budget.FormatAmount(top.Spent)))
+	}
+	switch len(d.OverBudget) {
+	case 0:
+	case 1:
+		parts = append(parts, d.OverBudget[0].Name+" is over budget.")
+	default:
+		parts = append(parts, fmt.Sprintf("%d categories are over budget.", len(d.OverBudget)))
+	}
+	return notify.Message{
+		UserID:  userID,
+		Type:    db.NotificationTypeInfo,
+		Title:   title,
+		Message: strings.Join(parts, " "),
+	}
+}
+
+// Email renders the digest email; the caller sets the recipient.
+func (d *Digest) Email(base mail.Base, symbol string) (mail.Message, error) {
+	amount := func(r *big.Rat) string { return symbol + budget.FormatAmount(r) }
+	data := mail.DigestData{
+		Base:       base,
+		Period:     d.Label(),
+		Income:     amount(d.Income),
+		Expenses:   amount(d.Expenses),
+		Net:        amount(new(big.Rat).Sub(d.Income, d.Expenses)),
+		Comparison: d.Comparison(),
+	}
+	for _, c := range d.TopCategories {
+		line := mail.DigestCategory{Name: c.Name, Spent: amount(c.Spent)}
+		// Limits are monthly, so only a monthly digest shows them here.
+		if c.Limit.Sign() > 0 && d.Frequency == db.DigestFrequencyMonthly {
+			line.Limit = amount(c.Limit)
+		}
+		data.Categories = append(data.Categories, line)
+	}
+	for _, c := range d.OverBudget {
+		data.OverBudget = append(data.OverBudget, mail.DigestCategory{Name: c.Name, Spent: amount(c.Spent), Limit: amount(c.Limit)})
+	}
+	for _, t := range d.LargestExpenses {
+		description := t.Description.String
+		if description == "" {
+			description = "Expense"
+		}
+		data.Transactions = append(data.Transactions, mail.DigestTransaction{
+			Date:        t.Date.Time.In(d.Period.Start.Location()).Format("2 Jan"),
+			Description: description,
+			Category:    t.CategoryName.String,
+			Amount:      amount(t.Amount.Rat()),
+		})
+	}
+	return mail.Render(mail.TemplateDigest, data)
+}
+
+func timestamptz(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: true}
+}
@@ -0,0 +1,139 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/mail"
+	"github.com/nyunja/30budget/backend/internal/notify"
+	"go.uber.org/zap"
+)
+
+// Job sends each user the digest for their last complete week or month, in
+// their time zone, as an in-app info notification and by email. A digests
+// row is claimed in the same transaction that creates the notification and
+// queues the email, so each period is sent at most once even with several
+// replicas running.
+type Job struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	notify  *notify.Dispatcher
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewJob(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, dispatcher *notify.Dispatcher) *Job {
+	return &Job{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		notify:  dispatcher,
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+// Run checks for due digests every interval until ctx is cancelled.
+func (j *Job) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.sendDue(ctx)
+		}
+	}
+}
+
+func (j *Job) sendDue(ctx context.Context) {
+	users, err := j.queries.ListDigestUsers(ctx)
+	if err != nil {
+		j.logger.Error("Failed to list digest users", zap.Error(err))
+		return
+	}
+	for _, user := range users {
+		if err := j.send(ctx, user, time.Now()); err != nil {
+			j.logger.Error("Failed to send digest", zap.String("user_id", user.ID.String()), zap.Error(err))
+		}
+	}
+}
+
+// send delivers user's digest for the last period complete at now, unless
+// it has been sent already, the user is in quiet hours, or the period had no
+// transactions.
+func (j *Job) send(ctx context.Context, user db.User, now time.Time) error {
+	prefs, err := notify.LoadPreferences(ctx, j.queries, user)
+	if err != nil {
+		return err
+	}
+	if prefs.DigestFrequency == db.DigestFrequencyNone {
+		return nil
+	}
+	// Try again on a later run, once quiet hours are over.
+	if _, quiet := prefs.QuietUntil(now); quiet {
+		return nil
+	}
+	period, previous := Periods(prefs.DigestFrequency, now, prefs.Location)
+	if !user.CreatedAt.Time.Before(period.End) {
+		return nil
+	}
+
+	var n *db.Notification
+	err = pgx.BeginFunc(ctx, j.dbPool, func(tx pgx.Tx) error {
+		qtx := j.queries.WithTx(tx)
+		key := db.CreateDigestParams{
+			UserID:      user.ID,
+			Frequency:   prefs.DigestFrequency,
+			PeriodStart: pgtype.Date{Time: period.Start, Valid: true},
+		}
+		claimed, err := qtx.CreateDigest(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to record digest: %w", err)
+		}
+		if claimed == 0 {
+			return nil
+		}
+		digest, err := Build(ctx, qtx, user.ID, prefs.DigestFrequency, period, previous)
+		if err != nil {
+			return err
+		}
+		// Periods without transactions are recorded too so they are not
+		// rebuilt every run.
+		if digest.Empty() {
+			return nil
+		}
+		msg := digest.Notification(user.ID, user.CurrencySymbol)
+		email, err := digest.Email(mail.Base{
+			Name:         user.Name,
+			AppURL:       j.config.App.FrontendURL,
+			SupportEmail: j.config.Email.SupportEmail,
+		}, user.CurrencySymbol)
+		if err != nil {
+			return err
+		}
+		msg.Email = &email
+		created, err := notify.Create(ctx, qtx, msg)
+		if err != nil {
+			return err
+		}
+		n = &created
+		return qtx.SetDigestNotification(ctx, db.SetDigestNotificationParams{
+			UserID:         key.UserID,
+			Frequency:      key.Frequency,
+			PeriodStart:    key.PeriodStart,
+			NotificationID: n.ID,
+		})
+	})
+	if err != nil || n == nil {
+		return err
+	}
+	j.notify.Dispatch(ctx, *n)
+	return nil
+}
@@ -0,0 +1,204 @@
+// Package events fans real-time events, such as new notifications and budget
+// changes, out to each user's connected clients. Delivery is in-process by
+// default; with Redis pub/sub enabled every replica publishes through Redis
+// and delivers whatever it receives to its own subscribers.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// historySize is how many recent events are kept per user for
+	// Last-Event-ID resume.
+	historySize = 100
+	// replayWindow is how long a user's history is kept after their last
+	// event once they have no subscribers. Clients gone longer than this
+	// refetch instead of resuming.
+	replayWindow = time.Hour
+	// pruneInterval spaces out sweeps for idle users' histories.
+	pruneInterval = time.Minute
+	// subscriberBuffer is how many undelivered events a subscriber may queue
+	// before it is disconnected and left to resume.
+	subscriberBuffer = 32
+)
+
+// Event types published by the backend.
+const (
+	TypeNotification = "notification"
+	TypeBudget       = "budget"
+)
+
+// Event is a message for one user's clients.
+type Event struct {
+	ID     string          `json:"id"`
+	UserID string          `json:"userId"`
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data"`
+	Time   time.Time       `json:"time"`
+}
+
+// Bus carries events between replicas.
+type Bus interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// Subscription receives a user's events on C. C is closed when the
+// subscription ends, including when the subscriber falls too far behind.
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	userID string
+	hub    *Hub
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s)
+}
+
+// Hub tracks subscribers and recent events per user.
+type Hub struct {
+	mu      sync.Mutex
+	subs    map[string]map[*Subscription]struct{}
+	history map[string][]Event
+	pruned  time.Time
+	bus     Bus
+	logger  *zap.Logger
+}
+
+// NewHub returns a Hub that delivers in-process until a Bus is attached.
+func NewHub(logger *zap.Logger) *Hub {
+	return &Hub{
+		subs:    make(map[string]map[*Subscription]struct{}),
+		history: make(map[string][]Event),
+		logger:  logger,
+	}
+}
+
+// SetBus routes published events through bus. The bus is expected to hand
+// every event back to Deliver, including those published by this replica.
+func (h *Hub) SetBus(bus Bus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.bus = bus
+}
+
+// Publish sends an event of the given type to userID's clients. Publishing is
+// best effort: failures are logged rather than returned, since callers publish
+// after their changes have committed.
+func (h *Hub) Publish(ctx context.Context, userID, eventType string, data any) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		h.logger.Error("Failed to encode event", zap.String("type", eventType), zap.Error(err))
+		return
+	}
+	e := Event{
+		ID:     utils.NewUUID().String(),
+		UserID: userID,
+		Type:   eventType,
+		Data:   payload,
+		Time:   time.Now().UTC(),
+	}
+
+	h.mu.Lock()
+	bus := h.bus
+	h.mu.Unlock()
+	if bus != nil {
+		err := bus.Publish(ctx, e)
+		if err == nil {
+			return
+		}
+		// Local clients still get the event; other replicas' clients will
+		// resync when they next reconnect.
+		h.logger.Warn("Failed to publish event to bus, delivering locally", zap.Error(err))
+	}
+	h.Deliver(e)
+}
+
+// Deliver records e in its user's history and passes it to their subscribers.
+func (h *Hub) Deliver(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pruneLocked(time.Now())
+
+	history := append(h.history[e.UserID], e)
+	if len(history) > historySize {
+		history = append([]Event(nil), history[len(history)-historySize:]...)
+	}
+	h.history[e.UserID] = history
+
+	for s := range h.subs[e.UserID] {
+		select {
+		case s.ch <- e:
+		default:
+			h.logger.Warn("Dropping slow event subscriber", zap.String("user_id", e.UserID))
+			h.removeLocked(s)
+		}
+	}
+}
+
+// Subscribe registers a subscriber for userID. When lastEventID is set, the
+// events published after it are returned for replay; resumed is false if that
+// event is no longer in the history, in which case the client should refetch
+// its state.
+func (h *Hub) Subscribe(userID, lastEventID string) (sub *Subscription, missed []Event, resumed bool) {
+	ch := make(chan Event, subscriberBuffer)
+	sub = &Subscription{C: ch, ch: ch, userID: userID, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*Subscription]struct{})
+	}
+	h.subs[userID][sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+	history := h.history[userID]
+	for i, e := range history {
+		if e.ID == lastEventID {
+			return sub, append([]Event(nil), history[i+1:]...), true
+		}
+	}
+	return sub, nil, false
+}
+
+// pruneLocked drops the history of users without subscribers whose last
+// event is older than replayWindow, at most once per pruneInterval.
+func (h *Hub) pruneLocked(now time.Time) {
+	if now.Sub(h.pruned) < pruneInterval {
+		return
+	}
+	h.pruned = now
+	for userID, history := range h.history {
+		if len(h.subs[userID]) == 0 && now.Sub(history[len(history)-1].Time) > replayWindow {
+			delete(h.history, userID)
+		}
+	}
+}
+
+func (h *Hub) unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(s)
+}
+
+func (h *Hub) removeLocked(s *Subscription) {
+	subs := h.subs[s.userID]
+	if _, ok := subs[s]; !ok {
+		return
+	}
+	delete(subs, s)
+	if len(subs) == 0 {
+		delete(h.subs, s.userID)
+	}
+	close(s.ch)
+}
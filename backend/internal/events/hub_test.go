@@ -0,0 +1,223 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func event(userID, id string, at time.Time) Event {
+	return Event{ID: id, UserID: userID, Type: TypeNotification, Data: []byte(`{}`), Time: at}
+}
+
+// receive returns the events waiting on sub without blocking, and whether
+// its channel has been closed.
+func receive(sub *Subscription) (events []Event, closed bool) {
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				return events, true
+			}
+			events = append(events, e)
+		default:
+			return events, false
+		}
+	}
+}
+
+func ids(events []Event) []string {
+	out := make([]string, len(events))
+	for i, e := range events {
+		out[i] = e.ID
+	}
+	return out
+}
+
+func TestHubFanOut(t *testing.T) {
+	h := NewHub(zap.NewNop())
+	a1, _, _ := h.Subscribe("a", "")
+	a2, _, _ := h.Subscribe("a", "")
+	b, _, _ := h.Subscribe("b", "")
+
+	now := time.Now()
+	h.Deliver(event("a", "a-1", now))
+	h.Deliver(event("b", "b-1", now))
+	h.Deliver(event("a", "a-2", now))
+
+	for _, sub := range []*Subscription{a1, a2} {
+		if got, _ := receive(sub); fmt.Sprint(ids(got)) != "[a-1 a-2]" {
+			t.Errorf("a's subscriber got %v, want [a-1 a-2]", ids(got))
+		}
+	}
+	if got, _ := receive(b); fmt.Sprint(ids(got)) != "[b-1]" {
+		t.Errorf("b's subscriber got %v, want [b-1]", ids(got))
+	}
+
+	a1.Close()
+	if _, closed := receive(a1); !closed {
+		t.Error("closed subscription's channel is open")
+	}
+	a1.Close() // closing twice is harmless
+	h.Deliver(event("a", "a-3", now))
+	if got, _ := receive(a2); fmt.Sprint(ids(got)) != "[a-3]" {
+		t.Errorf("remaining subscriber got %v, want [a-3]", ids(got))
+	}
+}
+
+func TestHubReplay(t *testing.T) {
+	h := NewHub(zap.NewNop())
+	now := time.Now()
+	for i := 1; i <= 3; i++ {
+		h.Deliver(event("a", fmt.Sprintf("e-%d", i), now))
+	}
+	h.Deliver(event("b", "other", now))
+
+	tests := []struct {
+		lastEventID string
+		missed      []string
+		resumed     bool
+	}{
+		{"", nil, true},
+		{"e-1", []string{"e-2", "e-3"}, true},
+		{"e-3", []string{}, true},
+		{"unknown", nil, false},
+		// Another user's event is not in this user's history.
+		{"other", nil, false},
+	}
+	for _, tt := range tests {
+		sub, missed, resumed := h.Subscribe("a", tt.lastEventID)
+		if resumed != tt.resumed || fmt.Sprint(ids(missed)) != fmt.Sprint(tt.missed) {
+			t.Errorf("Subscribe after %q = %v, %v; want %v, %v", tt.lastEventID, ids(missed), resumed, tt.missed, tt.resumed)
+		}
+		sub.Close()
+	}
+}
+
+func TestHubHistoryLimit(t *testing.T) {
+	h := NewHub(zap.NewNop())
+	now := time.Now()
+	for i := range historySize + 10 {
+		h.Deliver(event("a", fmt.Sprintf("e-%d", i), now))
+	}
+	if _, _, resumed := h.Subscribe("a", "e-9"); resumed {
+		t.Error("resumed from an event past the history")
+	}
+	_, missed, resumed := h.Subscribe("a", "e-10")
+	if !resumed || len(missed) != historySize-1 || missed[len(missed)-1].ID != fmt.Sprintf("e-%d", historySize+9) {
+		t.Errorf("resumed %v with %d missed events, want the %d after the oldest kept", resumed, len(missed), historySize-1)
+	}
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	h := NewHub(zap.NewNop())
+	slow, _, _ := h.Subscribe("a", "")
+	fast, _, _ := h.Subscribe("a", "")
+
+	now := time.Now()
+	for i := range subscriberBuffer {
+		h.Deliver(event("a", fmt.Sprintf("e-%d", i), now))
+		receive(fast)
+	}
+	if _, closed := receive(fast); closed {
+		t.Fatal("reading subscriber was dropped")
+	}
+	// One more than the slow subscriber's buffer holds.
+	h.Deliver(event("a", "overflow", now))
+
+	got, closed := receive(slow)
+	if !closed {
+		t.Error("slow subscriber was not dropped")
+	}
+	if len(got) != subscriberBuffer {
+		t.Errorf("slow subscriber got %d events before being dropped, want %d", len(got), subscriberBuffer)
+	}
+	if got, closed := receive(fast); closed || fmt.Sprint(ids(got)) != "[overflow]" {
+		t.Errorf("fast subscriber got %v (closed %v), want [overflow]", ids(got), closed)
+	}
+	if n := len(h.subs["a"]); n != 1 {
+		t.Errorf("%d subscribers left, want 1", n)
+	}
+	slow.Close() // already dropped; must not close the channel again
+
+	// The dropped client resumes from the last event it saw.
+	_, missed, resumed := h.Subscribe("a", got[len(got)-1].ID)
+	if !resumed || fmt.Sprint(ids(missed)) != "[overflow]" {
+		t.Errorf("resume = %v, %v; want [overflow], true", ids(missed), resumed)
+	}
+}
+
+func TestHubPrunesIdleHistory(t *testing.T) {
+	h := NewHub(zap.NewNop())
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	h.Deliver(event("idle", "old", now.Add(-replayWindow-time.Minute)))
+	h.Deliver(event("recent", "new", now.Add(-replayWindow+time.Minute)))
+	h.Deliver(event("watched", "old", now.Add(-2*replayWindow)))
+	sub, _, _ := h.Subscribe("watched", "")
+	defer sub.Close()
+
+	h.mu.Lock()
+	h.pruned = time.Time{}
+	h.pruneLocked(now)
+	h.mu.Unlock()
+
+	if _, ok := h.history["idle"]; ok {
+		t.Error("idle user's history was kept")
+	}
+	if _, ok := h.history["recent"]; !ok {
+		t.Error("history within the replay window was dropped")
+	}
+	if _, ok := h.history["watched"]; !ok {
+		t.Error("history of a user with a subscriber was dropped")
+	}
+
+	// Sweeps are at most once per pruneInterval.
+	h.Deliver(event("idle", "old", now.Add(-2*replayWindow)))
+	h.mu.Lock()
+	h.pruned = now
+	h.pruneLocked(now.Add(pruneInterval / 2))
+	_, kept := h.history["idle"]
+	h.pruneLocked(now.Add(pruneInterval))
+	_, stillKept := h.history["idle"]
+	h.mu.Unlock()
+	if !kept || stillKept {
+		t.Errorf("history kept %v before the interval and %v after, want true then false", kept, stillKept)
+	}
+}
+
+type fakeBus struct {
+	err       error
+	published []Event
+}
+
+func (b *fakeBus) Publish(ctx context.Context, e Event) error {
+	b.published = append(b.published, e)
+	return b.err
+}
+
+func TestHubPublishThroughBus(t *testing.T) {
+	h := NewHub(zap.NewNop())
+	sub, _, _ := h.Subscribe("a", "")
+	defer sub.Close()
+	bus := &fakeBus{}
+	h.SetBus(bus)
+
+	h.Publish(context.Background(), "a", TypeBudget, map[string]string{"spent": "10.00"})
+	if len(bus.published) != 1 || bus.published[0].UserID != "a" || string(bus.published[0].Data) != `{"spent":"10.00"}` {
+		t.Fatalf("bus got %+v", bus.published)
+	}
+	// The bus hands events back; nothing is delivered twice.
+	if got, _ := receive(sub); len(got) != 0 {
+		t.Errorf("delivered %v locally as well as through the bus", ids(got))
+	}
+
+	bus.err = errors.New("redis down")
+	h.Publish(context.Background(), "a", TypeBudget, map[string]string{"spent": "20.00"})
+	if got, _ := receive(sub); len(got) != 1 || got[0].ID != bus.published[1].ID {
+		t.Errorf("got %v after the bus failed, want the event delivered locally", ids(got))
+	}
+}
@@ -0,0 +1,291 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RedisChannel is the pub/sub channel events travel on.
+const RedisChannel = "30budget:events"
+
+// maxIdleConns is how many publishing connections are kept open between
+// publishes. More are dialled when publishes overlap.
+const maxIdleConns = 4
+
+// RedisBus publishes events on a Redis pub/sub channel and delivers the events
+// it receives to a Hub. It speaks just enough RESP for PUBLISH and SUBSCRIBE.
+type RedisBus struct {
+	rawURL   string
+	password string
+	logger   *zap.Logger
+
+	mu   sync.Mutex
+	idle []*redisConn
+}
+
+// NewRedisBus validates rawURL (redis:// or rediss://) and returns a bus.
+// password is used when the URL carries none.
+func NewRedisBus(rawURL, password string, logger *zap.Logger) (*RedisBus, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("invalid REDIS_URL: unsupported scheme %q", u.Scheme)
+	}
+	return &RedisBus{rawURL: rawURL, password: password, logger: logger}, nil
+}
+
+// Publish sends e to every replica subscribed to the channel.
+func (b *RedisBus) Publish(ctx context.Context, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	// Retry once on a fresh connection in case the pooled one went stale.
+	for attempt := 0; ; attempt++ {
+		var conn *redisConn
+		if attempt == 0 {
+			conn = b.takeIdle()
+		}
+		if conn == nil {
+			if conn, err = dialRedis(ctx, b.rawURL, b.password); err != nil {
+				return err
+			}
+		}
+		_, err = conn.do(ctx, "PUBLISH", RedisChannel, string(payload))
+		if err == nil {
+			b.putIdle(conn)
+			return nil
+		}
+		conn.Close()
+		if attempt > 0 {
+			return err
+		}
+	}
+}
+
+// takeIdle returns a pooled connection, or nil if there is none. Each
+// publish has a connection to itself, so a slow round trip holds up no
+// other publisher.
+func (b *RedisBus) takeIdle() *redisConn {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.idle) == 0 {
+		return nil
+	}
+	conn := b.idle[len(b.idle)-1]
+	b.idle = b.idle[:len(b.idle)-1]
+	return conn
+}
+
+// putIdle returns conn to the pool, or closes it if the pool is full.
+func (b *RedisBus) putIdle(conn *redisConn) {
+	b.mu.Lock()
+	if len(b.idle) < maxIdleConns {
+		b.idle = append(b.idle, conn)
+		conn = nil
+	}
+	b.mu.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// Run subscribes to the channel and delivers received events to hub until ctx
+// is cancelled, reconnecting with backoff when the connection drops.
+func (b *RedisBus) Run(ctx context.Context, hub *Hub) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		err := b.subscribe(ctx, hub, func() { backoff = time.Second })
+		if ctx.Err() != nil {
+			return
+		}
+		b.logger.Warn("Redis event subscription failed, retrying", zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+func (b *RedisBus) subscribe(ctx context.Context, hub *Hub, connected func()) error {
+	conn, err := dialRedis(ctx, b.rawURL, b.password)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := conn.send("SUBSCRIBE", RedisChannel); err != nil {
+		return err
+	}
+	connected()
+	for {
+		reply, err := conn.read()
+		if err != nil {
+			return err
+		}
+		// Pushed messages look like ["message", channel, payload].
+		msg, ok := reply.([]any)
+		if !ok || len(msg) != 3 || msg[0] != "message" {
+			continue
+		}
+		payload, _ := msg[2].(string)
+		var e Event
+		if err := json.Unmarshal([]byte(payload), &e); err != nil {
+			b.logger.Warn("Ignoring malformed event from Redis", zap.Error(err))
+			continue
+		}
+		hub.Deliver(e)
+	}
+}
+
+// redisConn is a single RESP connection.
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dialRedis(ctx context.Context, rawURL, password string) (*redisConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	dialer := &net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}
+	var conn net.Conn
+	if u.Scheme == "rediss" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: u.Hostname()}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+
+	username := u.User.Username()
+	if p, ok := u.User.Password(); ok {
+		password = p
+	}
+	if password != "" {
+		args := []string{"AUTH", password}
+		if username != "" {
+			args = []string{"AUTH", username, password}
+		}
+		if _, err := c.do(ctx, args...); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("redis AUTH failed: %w", err)
+		}
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" && db != "0" {
+		if _, err := c.do(ctx, "SELECT", db); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("redis SELECT failed: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func (c *redisConn) Close() error {
+	return c.conn.Close()
+}
+
+// do sends a command and reads its reply.
+func (c *redisConn) do(ctx context.Context, args ...string) (any, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(5 * time.Second)
+	}
+	c.conn.SetDeadline(deadline)
+	defer c.conn.SetDeadline(time.Time{})
+	if err := c.send(args...); err != nil {
+		return nil, err
+	}
+	return c.read()
+}
+
+// send writes a command as a RESP array of bulk strings.
+func (c *redisConn) send(args ...string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return fmt.Errorf("redis write failed: %w", err)
+	}
+	return nil
+}
+
+// read parses one RESP reply. Bulk strings are returned as strings, integers
+// as int64 and arrays as []any; error replies become Go errors.
+func (c *redisConn) read() (any, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis read failed: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, fmt.Errorf("redis read failed: %w", err)
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: bad array length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = c.read(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
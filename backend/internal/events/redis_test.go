@@ -0,0 +1,271 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func reader(stream string) *redisConn {
+	return &redisConn{r: bufio.NewReader(strings.NewReader(stream))}
+}
+
+func TestRedisRead(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   any
+	}{
+		{"simple string", "+OK\r\n", "OK"},
+		{"integer", ":42\r\n", int64(42)},
+		{"negative integer", ":-1\r\n", int64(-1)},
+		{"bulk string", "$5\r\nhello\r\n", "hello"},
+		{"empty bulk string", "$0\r\n\r\n", ""},
+		{"bulk string with CRLF inside", "$7\r\na\r\nb\r\nc\r\n", "a\r\nb\r\nc"},
+		{"bulk string with UTF-8", "$6\r\nnaïve\r\n", "naïve"},
+		{"null bulk string", "$-1\r\n", nil},
+		{"null array", "*-1\r\n", nil},
+		{"empty array", "*0\r\n", []any{}},
+		{
+			"pushed message",
+			"*3\r\n$7\r\nmessage\r\n$15\r\n30budget:events\r\n$12\r\n{\"id\":\"e-1\"}\r\n",
+			[]any{"message", "30budget:events", `{"id":"e-1"}`},
+		},
+		{
+			"subscribe confirmation",
+			"*3\r\n$9\r\nsubscribe\r\n$15\r\n30budget:events\r\n:1\r\n",
+			[]any{"subscribe", "30budget:events", int64(1)},
+		},
+		{"nested array", "*2\r\n*1\r\n+a\r\n$-1\r\n", []any{[]any{"a"}, nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reader(tt.stream).read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("read = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedisReadErrors(t *testing.T) {
+	tests := map[string]string{
+		"error reply":             "-ERR unknown command\r\n",
+		"empty line":              "\r\n",
+		"unknown type":            "!oops\r\n",
+		"bad bulk length":         "$x\r\nab\r\n",
+		"bad array length":        "*two\r\n",
+		"bad integer":             ":4.2\r\n",
+		"truncated bulk":          "$10\r\nshort\r\n",
+		"truncated array":         "*2\r\n+one\r\n",
+		"no line ending":          "+OK",
+		"error inside an array":   "*2\r\n+ok\r\n-ERR nope\r\n",
+		"end of stream":           "",
+		"truncated bulk trailer":  "$5\r\nhello",
+		"bulk length past buffer": "$99999\r\nabc\r\n",
+	}
+	for name, stream := range tests {
+		if got, err := reader(stream).read(); err == nil {
+			t.Errorf("%s: read = %#v, want an error", name, got)
+		}
+	}
+	if _, err := reader("-WRONGPASS invalid password\r\n").read(); err == nil || !strings.Contains(err.Error(), "WRONGPASS invalid password") {
+		t.Errorf("error reply = %v, want Redis's message", err)
+	}
+}
+
+func TestRedisReadSequence(t *testing.T) {
+	c := reader("+OK\r\n:1\r\n$3\r\nfoo\r\n")
+	for _, want := range []any{"OK", int64(1), "foo"} {
+		got, err := c.read()
+		if err != nil || got != want {
+			t.Fatalf("read = %#v, %v; want %#v", got, err, want)
+		}
+	}
+}
+
+// fakeRedis answers PUBLISH with :1 on every connection, recording the
+// commands it receives. Replies on connections opened while hold is set wait
+// until it is closed.
+type fakeRedis struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	commands [][]string
+	conns    int
+	hold     chan struct{}
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{ln: ln}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			f.mu.Lock()
+			f.conns++
+			hold := f.hold
+			f.mu.Unlock()
+			go f.serve(conn, hold)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn, hold chan struct{}) {
+	defer conn.Close()
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+	for {
+		reply, err := c.read()
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, a := range reply.([]any) {
+			args = append(args, a.(string))
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, args)
+		f.mu.Unlock()
+		if hold != nil {
+			<-hold
+		}
+		conn.Write([]byte(":1\r\n"))
+	}
+}
+
+func (f *fakeRedis) url() string {
+	return "redis://" + f.ln.Addr().String()
+}
+
+func TestRedisBusPublish(t *testing.T) {
+	f := newFakeRedis(t)
+	bus, err := NewRedisBus(f.url(), "", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := Event{ID: "e-1", UserID: "u-1", Type: TypeBudget, Data: json.RawMessage(`{"spent":"10.00"}`), Time: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)}
+	for range 3 {
+		if err := bus.Publish(context.Background(), e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conns != 1 {
+		t.Errorf("dialled %d connections for sequential publishes, want 1", f.conns)
+	}
+	if len(f.commands) != 3 {
+		t.Fatalf("got %d commands, want 3", len(f.commands))
+	}
+	cmd := f.commands[0]
+	if len(cmd) != 3 || cmd[0] != "PUBLISH" || cmd[1] != RedisChannel {
+		t.Fatalf("command = %q", cmd)
+	}
+	var got Event
+	if err := json.Unmarshal([]byte(cmd[2]), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != e.ID || got.UserID != e.UserID || string(got.Data) != string(e.Data) || !got.Time.Equal(e.Time) {
+		t.Errorf("published %+v, want %+v", got, e)
+	}
+}
+
+func TestRedisBusPublishDoesNotSerialize(t *testing.T) {
+	f := newFakeRedis(t)
+	bus, err := NewRedisBus(f.url(), "", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := Event{ID: "e-1", UserID: "u-1", Type: TypeBudget, Data: json.RawMessage(`{}`)}
+
+	// The first publish's connection answers only once released.
+	release := make(chan struct{})
+	f.mu.Lock()
+	f.hold = release
+	f.mu.Unlock()
+	slow := make(chan error, 1)
+	go func() { slow <- bus.Publish(context.Background(), e) }()
+	for {
+		f.mu.Lock()
+		n := len(f.commands)
+		f.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	f.mu.Lock()
+	f.hold = nil
+	f.mu.Unlock()
+
+	// Another publish goes through on its own connection meanwhile.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := bus.Publish(ctx, e); err != nil {
+		t.Fatalf("publish during a slow one: %v", err)
+	}
+	select {
+	case err := <-slow:
+		t.Fatalf("slow publish finished early: %v", err)
+	default:
+	}
+
+	close(release)
+	if err := <-slow; err != nil {
+		t.Fatal(err)
+	}
+	if n := len(bus.idle); n != 2 {
+		t.Errorf("%d idle connections, want both kept", n)
+	}
+}
+
+func TestRedisBusPublishRedials(t *testing.T) {
+	f := newFakeRedis(t)
+	bus, err := NewRedisBus(f.url(), "", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := Event{ID: "e-1", Data: json.RawMessage(`{}`)}
+	if err := bus.Publish(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	// The pooled connection goes stale.
+	bus.idle[0].Close()
+	if err := bus.Publish(context.Background(), e); err != nil {
+		t.Fatalf("publish on a stale connection: %v", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conns != 2 {
+		t.Errorf("dialled %d connections, want a fresh one after the stale", f.conns)
+	}
+}
+
+func TestNewRedisBusRejectsBadURLs(t *testing.T) {
+	for _, u := range []string{"http://localhost:6379", "localhost:6379", "redis://%zz"} {
+		if _, err := NewRedisBus(u, "", zap.NewNop()); err == nil {
+			t.Errorf("NewRedisBus(%q) = nil error", u)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Package forecast projects account balances day by day to the end of a
+// budget period, from what is scheduled and how much is usually spent.
+package forecast
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/bill"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+)
+
+// Account is where a projection starts: an account, or the money recorded
+// against none when ID is NULL.
+type Account struct {
+	ID      pgtype.UUID
+	Name    string
+	Balance *big.Rat
+	// DailySpend is the discretionary spending expected each day.
+	DailySpend *big.Rat
+}
+
+// Inputs is what is scheduled between Today and Last, both calendar dates.
+type Inputs struct {
+	Today time.Time
+	Last  time.Time
+	// Bills are the unpaid bill occurrences due up to Last, overdue ones
+	// included.
+	Bills     []bill.Occurrence
+	Recurring []db.RecurringTransaction
+}
+
+// Point is the projected balance at the end of a day.
+type Point struct {
+	Date     pgtype.Date   `json:"date"`
+	Income   money.Decimal `json:"income"`
+	Expenses money.Decimal `json:"expenses"`
+	Balance  money.Decimal `json:"balance"`
+	// Lowest marks the first day the balance is at its minimum.
+	Lowest bool `json:"lowest"`
+}
+
+// Series is the projection of one account, or of all of them combined.
+type Series struct {
+	AccountID      pgtype.UUID   `json:"accountId"`
+	Name           string        `json:"name"`
+	CurrentBalance money.Decimal `json:"currentBalance"`
+	DailySpend     money.Decimal `json:"dailySpend"`
+	EndingBalance  money.Decimal `json:"endingBalance"`
+	LowestBalance  money.Decimal `json:"lowestBalance"`
+	LowestDate     pgtype.Date   `json:"lowestDate"`
+	Points         []Point       `json:"points"`
+}
+
+// flows are an account's current balance and its money in and out on each
+// day of the forecast.
+type flows struct {
+	balance  *big.Rat
+	spend    *big.Rat
+	income   []*big.Rat
+	expenses []*big.Rat
+}
+
+func newFlows(days int) *flows {
+	f := &flows{balance: new(big.Rat), spend: new(big.Rat), income: make([]*big.Rat, days), expenses: make([]*big.Rat, days)}
+	for i := range days {
+		f.income[i], f.expenses[i] = new(big.Rat), new(big.Rat)
+	}
+	return f
+}
+
+func (f *flows) add(o *flows) {
+	f.balance.Add(f.balance, o.balance)
+	f.spend.Add(f.spend, o.spend)
+	for i := range f.income {
+		f.income[i].Add(f.income[i], o.income[i])
+		f.expenses[i].Add(f.expenses[i], o.expenses[i])
+	}
+}
+
+// Project forecasts each account and their total.
+//
+// Today's point is the current balance less the bills overdue or due today.
+// Recurring transactions and discretionary spending are applied from
+// tomorrow, as whatever happened today has already been recorded.
+func Project(accounts []Account, in Inputs) (series []Series, total Series) {
+	today, last := recurrence.Day(in.Today), recurrence.Day(in.Last)
+	days := int(last.Sub(today).Hours()/24) + 1
+	sum := newFlows(days)
+	for _, a := range accounts {
+		f := newFlows(days)
+		f.balance.Set(a.Balance)
+		f.spend.Set(a.DailySpend)
+		for i := 1; i < days; i++ {
+			f.expenses[i].Add(f.expenses[i], a.DailySpend)
+		}
+		for _, o := range in.Bills {
+			if o.AccountID != a.ID {
+				continue
+			}
+			i := max(int(recurrence.Day(o.DueDate.Time).Sub(today).Hours()/24), 0)
+			if i < days {
+				f.expenses[i].Add(f.expenses[i], o.Amount.Rat())
+			}
+		}
+		for _, rt := range in.Recurring {
+			if rt.AccountID != a.ID {
+				continue
+			}
+			rule := recurrence.New(rt.Frequency, rt.IntervalCount, rt.StartDate, rt.EndDate)
+			for _, d := range rule.Between(today.AddDate(0, 0, 1), last) {
+				i := int(d.Sub(today).Hours() / 24)
+				if rt.Type == db.TransactionTypeIncome {
+					f.income[i].Add(f.income[i], rt.Amount.Rat())
+				} else {
+					f.expenses[i].Add(f.expenses[i], rt.Amount.Rat())
+				}
+			}
+		}
+		sum.add(f)
+		series = append(series, f.series(a.ID, a.Name, today))
+	}
+	return series, sum.series(pgtype.UUID{}, "Total", today)
+}
+
+// series runs the balance forward day by day.
+func (f *flows) series(id pgtype.UUID, name string, today time.Time) Series {
+	s := Series{
+		AccountID:      id,
+		Name:           name,
+		CurrentBalance: money.Amount(f.balance),
+		DailySpend:     money.Amount(f.spend),
+		Points:         make([]Point, len(f.income)),
+	}
+	balance := new(big.Rat).Set(f.balance)
+	var lowest *big.Rat
+	lowestDay := 0
+	for i := range f.income {
+		balance.Add(balance, f.income[i])
+		balance.Sub(balance, f.expenses[i])
+		if lowest == nil || balance.Cmp(lowest) < 0 {
+			lowest, lowestDay = new(big.Rat).Set(balance), i
+		}
+		s.Points[i] = Point{
+			Date:     recurrence.Date(today.AddDate(0, 0, i)),
+			Income:   money.Amount(f.income[i]),
+			Expenses: money.Amount(f.expenses[i]),
+			Balance:  money.Amount(balance),
+		}
+	}
+	s.Points[lowestDay].Lowest = true
+	s.EndingBalance = money.Amount(balance)
+	s.LowestBalance = money.Amount(lowest)
+	s.LowestDate = s.Points[lowestDay].Date
+	return s
+}
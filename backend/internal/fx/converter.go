@@ -0,0 +1,191 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+)
+
+// rateDecimals is the precision exchange rates are stored with.
+const rateDecimals = 10
+
+// maxRateAge is how many days old a rate may be and still be used for a
+// day, e.g. for today before the day's rates are published.
+const maxRateAge = 7
+
+// maxSettled bounds how many days Converter remembers the provider's
+// earlier-dated answers for.
+const maxSettled = 10000
+
+// Store keeps the rates a Converter has looked up. *db.Queries is the one
+// used outside tests.
+type Store interface {
+	FindExchangeRate(ctx context.Context, arg db.FindExchangeRateParams) (db.FindExchangeRateRow, error)
+	UpsertExchangeRate(ctx context.Context, arg db.UpsertExchangeRateParams) error
+}
+
+// Converter finds the rate between two currencies on a day from the rates
+// stored in the database, asking the provider for those it lacks.
+type Converter struct {
+	provider Provider
+	source   string
+
+	mu sync.Mutex
+	// settled maps a currency pair and past day the provider answered with
+	// an earlier date's rate, e.g. a Sunday, to that date, so the rate stored
+	// under it is used without asking again.
+	settled map[settledKey]time.Time
+}
+
+type settledKey struct {
+	from, to string
+	day      time.Time
+}
+
+// NewConverter returns a Converter that records rates from provider as
+// coming from source, e.g. the FX_PROVIDER name.
+func NewConverter(provider Provider, source string) *Converter {
+	return &Converter{provider: provider, source: source, settled: make(map[settledKey]time.Time)}
+}
+
+// Rate returns what one unit of from bought in to on day, a calendar date.
+//
+// A rate stored for the day, in either direction, is used first. Otherwise
+// the provider is asked and its answer stored under the date it is for,
+// which may be earlier than day, and failing that the latest rate stored
+// within maxRateAge days is used. Rates are rounded to rateDecimals places.
+func (c *Converter) Rate(ctx context.Context, q Store, from, to string, day time.Time) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+	day = recurrence.Day(day)
+	since := day
+	if date, ok := c.settledDate(from, to, day); ok {
+		since = date
+	}
+	if rate, err := stored(ctx, q, from, to, since, day); err == nil {
+		return rate, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+
+	fetched, providerErr := c.provider.Rate(ctx, from, to, day)
+	if providerErr == nil {
+		date := recurrence.Day(fetched.Date)
+		if fetched.Date.IsZero() || date.After(day) {
+			date = day
+		}
+		rate := round(fetched.Rate)
+		if err := q.UpsertExchangeRate(ctx, db.UpsertExchangeRateParams{
+			BaseCurrency:  from,
+			QuoteCurrency: to,
+			RateDate:      recurrence.Date(date),
+			Rate:          Decimal(rate),
+			Source:        c.source,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to save exchange rate: %w", err)
+		}
+		if date.Before(day) {
+			c.settle(from, to, day, date)
+		}
+		return rate, nil
+	}
+
+	rate, err := stored(ctx, q, from, to, day.AddDate(0, 0, -maxRateAge), day)
+	if err == nil {
+		return rate, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	if errors.Is(providerErr, ErrNoRate) {
+		return nil, fmt.Errorf("%w from %s to %s on %s", ErrNoRate, from, to, day.Format(time.DateOnly))
+	}
+	return nil, fmt.Errorf("failed to fetch exchange rate: %w", providerErr)
+}
+
+// Convert returns amount in from converted to to at the rate on day,
+// rounded to to's minor units.
+func (c *Converter) Convert(ctx context.Context, q Store, amount *big.Rat, from, to string, day time.Time) (*big.Rat, error) {
+	rate, err := c.Rate(ctx, q, from, to, day)
+	if err != nil {
+		return nil, err
+	}
+	return money.FromRat(new(big.Rat).Mul(amount, rate), money.MinorUnits(to)).Rat(), nil
+}
+
+// settledDate returns the date of the rate the provider gave for day, when
+// that was earlier than day.
+func (c *Converter) settledDate(from, to string, day time.Time) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	date, ok := c.settled[settledKey{from, to, day}]
+	return date, ok
+}
+
+// settle remembers that the provider's rate for day is the one for date.
+// Only days before yesterday are remembered, since until a day is over in
+// every time zone the provider may still publish its rate.
+func (c *Converter) settle(from, to string, day, date time.Time) {
+	if !day.Before(recurrence.Day(time.Now()).AddDate(0, 0, -1)) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.settled) >= maxSettled {
+		clear(c.settled)
+	}
+	c.settled[settledKey{from, to, day}] = date
+}
+
+// Import stores rates, e.g. read by ReadCSV, as coming from source.
+func Import(ctx context.Context, q Store, rates []Rate, source string) error {
+	for _, r := range rates {
+		if err := q.UpsertExchangeRate(ctx, db.UpsertExchangeRateParams{
+			BaseCurrency:  r.Base,
+			QuoteCurrency: r.Quote,
+			RateDate:      recurrence.Date(r.Date),
+			Rate:          Decimal(r.Rate),
+			Source:        source,
+		}); err != nil {
+			return fmt.Errorf("failed to save exchange rate: %w", err)
+		}
+	}
+	return nil
+}
+
+// stored returns the latest rate from from to to in the database dated from
+// since to until.
+func stored(ctx context.Context, q Store, from, to string, since, until time.Time) (*big.Rat, error) {
+	row, err := q.FindExchangeRate(ctx, db.FindExchangeRateParams{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Since:        recurrence.Date(since),
+		Until:        recurrence.Date(until),
+	})
+	if err != nil {
+		return nil, err
+	}
+	rate := row.Rate.Rat()
+	if row.BaseCurrency != from {
+		rate.Inv(rate)
+	}
+	return round(rate), nil
+}
+
+// Decimal converts an exchange rate to a Decimal with rateDecimals places.
+func Decimal(rate *big.Rat) money.Decimal {
+	return money.FromRat(rate, rateDecimals)
+}
+
+func round(r *big.Rat) *big.Rat {
+	return Decimal(r).Rat()
+}
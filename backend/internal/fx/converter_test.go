@@ -0,0 +1,193 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nyunja/30budget/backend/internal/db"
+)
+
+// memStore is an in-memory Store that looks rates up as the
+// FindExchangeRate query does.
+type memStore struct {
+	rows []db.UpsertExchangeRateParams
+}
+
+func (m *memStore) UpsertExchangeRate(ctx context.Context, arg db.UpsertExchangeRateParams) error {
+	for i, r := range m.rows {
+		if r.BaseCurrency == arg.BaseCurrency && r.QuoteCurrency == arg.QuoteCurrency && r.RateDate.Time.Equal(arg.RateDate.Time) {
+			m.rows[i] = arg
+			return nil
+		}
+	}
+	m.rows = append(m.rows, arg)
+	return nil
+}
+
+func (m *memStore) FindExchangeRate(ctx context.Context, arg db.FindExchangeRateParams) (db.FindExchangeRateRow, error) {
+	var matches []db.UpsertExchangeRateParams
+	for _, r := range m.rows {
+		forward := r.BaseCurrency == arg.FromCurrency && r.QuoteCurrency == arg.ToCurrency
+		backward := r.BaseCurrency == arg.ToCurrency && r.QuoteCurrency == arg.FromCurrency
+		if (forward || backward) && !r.RateDate.Time.Before(arg.Since.Time) && !r.RateDate.Time.After(arg.Until.Time) {
+			matches = append(matches, r)
+		}
+	}
+	if len(matches) == 0 {
+		return db.FindExchangeRateRow{}, pgx.ErrNoRows
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if !matches[i].RateDate.Time.Equal(matches[j].RateDate.Time) {
+			return matches[i].RateDate.Time.After(matches[j].RateDate.Time)
+		}
+		return matches[i].BaseCurrency == arg.FromCurrency
+	})
+	r := matches[0]
+	return db.FindExchangeRateRow{BaseCurrency: r.BaseCurrency, Rate: r.Rate, RateDate: r.RateDate}, nil
+}
+
+func date(s string) time.Time {
+	d, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func rat(s string) *big.Rat {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		panic(s)
+	}
+	return r
+}
+
+func TestConverterCachesRates(t *testing.T) {
+	fake := NewFake()
+	fake.Set("USD", "KES", date("2026-01-05"), rat("129.25"))
+	store := &memStore{}
+	c := NewConverter(fake, "fake")
+	ctx := context.Background()
+
+	for range 3 {
+		rate, err := c.Rate(ctx, store, "USD", "KES", date("2026-01-05"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rate.Cmp(rat("129.25")) != 0 {
+			t.Errorf("rate = %s, want 129.25", rate.FloatString(2))
+		}
+	}
+	if fake.Calls() != 1 {
+		t.Errorf("provider asked %d times, want once", fake.Calls())
+	}
+	if len(store.rows) != 1 || store.rows[0].Source != "fake" {
+		t.Errorf("stored %+v", store.rows)
+	}
+
+	// The stored rate also serves the inverse pair.
+	rate, err := c.Rate(ctx, store, "KES", "USD", date("2026-01-05"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := round(new(big.Rat).Inv(rat("129.25"))); rate.Cmp(want) != 0 {
+		t.Errorf("inverse rate = %s, want %s", rate.FloatString(10), want.FloatString(10))
+	}
+	if fake.Calls() != 1 {
+		t.Errorf("provider asked %d times for the inverse pair", fake.Calls())
+	}
+
+	if rate, err := c.Rate(ctx, store, "KES", "KES", date("2026-01-05")); err != nil || rate.Cmp(big.NewRat(1, 1)) != 0 {
+		t.Errorf("same-currency rate = %v, %v", rate, err)
+	}
+}
+
+func TestConverterStoresProviderDate(t *testing.T) {
+	fake := NewFake()
+	friday := date("2026-01-02")
+	fake.Set("EUR", "KES", friday, rat("141.5"))
+	store := &memStore{}
+	c := NewConverter(fake, "fake")
+	ctx := context.Background()
+
+	sunday := date("2026-01-04")
+	rate, err := c.Rate(ctx, store, "EUR", "KES", sunday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Cmp(rat("141.5")) != 0 {
+		t.Errorf("rate = %s, want 141.5", rate.FloatString(2))
+	}
+	if len(store.rows) != 1 || !store.rows[0].RateDate.Time.Equal(friday) {
+		t.Fatalf("stored %+v, want one rate dated %s", store.rows, friday.Format(time.DateOnly))
+	}
+
+	// Asking for Sunday again uses Friday's stored rate.
+	if _, err := c.Rate(ctx, store, "EUR", "KES", sunday); err != nil {
+		t.Fatal(err)
+	}
+	if fake.Calls() != 1 {
+		t.Errorf("provider asked %d times, want once", fake.Calls())
+	}
+
+	// Monday's rate, once published, is not shadowed by Friday's.
+	monday := date("2026-01-05")
+	fake.Set("EUR", "KES", monday, rat("142"))
+	rate, err = c.Rate(ctx, store, "EUR", "KES", monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Cmp(rat("142")) != 0 {
+		t.Errorf("Monday's rate = %s, want 142", rate.FloatString(2))
+	}
+}
+
+func TestConverterFallsBackToStored(t *testing.T) {
+	store := &memStore{}
+	ctx := context.Background()
+	if err := Import(ctx, store, []Rate{{Base: "USD", Quote: "UGX", Date: date("2026-03-10"), Rate: rat("3700")}}, "import"); err != nil {
+		t.Fatal(err)
+	}
+	fake := NewFake()
+	c := NewConverter(fake, "fake")
+
+	rate, err := c.Rate(ctx, store, "USD", "UGX", date("2026-03-14"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.Cmp(rat("3700")) != 0 {
+		t.Errorf("rate = %s, want the stored 3700", rate.FloatString(2))
+	}
+	if fake.Calls() != 1 {
+		t.Errorf("provider asked %d times, want once before falling back", fake.Calls())
+	}
+
+	if _, err := c.Rate(ctx, store, "USD", "UGX", date("2026-03-18")); !errors.Is(err, ErrNoRate) {
+		t.Errorf("rate eight days after the last stored = %v, want ErrNoRate", err)
+	}
+}
+
+func TestTableMaxAge(t *testing.T) {
+	fake := NewFake()
+	fake.Set("GBP", "KES", date("2026-05-01"), rat("170"))
+	ctx := context.Background()
+
+	if r, err := fake.Rate(ctx, "GBP", "KES", date("2026-05-08")); err != nil || !r.Date.Equal(date("2026-05-01")) {
+		t.Errorf("rate a week later = %+v, %v", r, err)
+	}
+	if _, err := fake.Rate(ctx, "GBP", "KES", date("2026-05-09")); !errors.Is(err, ErrNoRate) {
+		t.Errorf("rate eight days later = %v, want ErrNoRate", err)
+	}
+	if _, err := fake.Rate(ctx, "GBP", "KES", date("2026-04-30")); !errors.Is(err, ErrNoRate) {
+		t.Errorf("rate before the first = %v, want ErrNoRate", err)
+	}
+	r, err := fake.Rate(ctx, "KES", "GBP", date("2026-05-03"))
+	if err != nil || r.Rate.Cmp(big.NewRat(1, 170)) != 0 {
+		t.Errorf("inverse rate = %+v, %v", r, err)
+	}
+}
@@ -0,0 +1,54 @@
+package fx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ReadCSV parses rates with the columns date (YYYY-MM-DD), base, quote and
+// rate, e.g. "2026-01-05,USD,KES,129.25". A header row naming the columns
+// is skipped.
+func ReadCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+	var rates []Rate
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rates, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(record[0], "date") {
+			continue
+		}
+		rate, err := parseRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+}
+
+func parseRecord(record []string) (Rate, error) {
+	day, err := time.Parse(time.DateOnly, record[0])
+	if err != nil {
+		return Rate{}, errors.New("date must be in YYYY-MM-DD format")
+	}
+	base, quote := strings.ToUpper(record[1]), strings.ToUpper(record[2])
+	if !ValidCurrency(base) || !ValidCurrency(quote) || base == quote {
+		return Rate{}, errors.New("base and quote must be two different three-letter currency codes")
+	}
+	value, ok := new(big.Rat).SetString(record[3])
+	if !ok || value.Sign() <= 0 {
+		return Rate{}, errors.New("rate must be a positive number")
+	}
+	return Rate{Base: base, Quote: quote, Date: day, Rate: value}, nil
+}
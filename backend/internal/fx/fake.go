@@ -0,0 +1,45 @@
+package fx
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+)
+
+// Fake serves the rates set on it. It is the default provider, so without a
+// rates source only rates imported into the database convert, and it stands
+// in for a real provider in tests.
+type Fake struct {
+	mu    sync.Mutex
+	rates table
+	calls int
+}
+
+// NewFake returns a Fake that knows no rates.
+func NewFake() *Fake {
+	return &Fake{rates: make(table)}
+}
+
+// Set records that one unit of base bought rate units of quote on day.
+func (f *Fake) Set(base, quote string, day time.Time, rate *big.Rat) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rates.add(Rate{Base: base, Quote: quote, Date: recurrence.Day(day), Rate: new(big.Rat).Set(rate)})
+}
+
+func (f *Fake) Rate(ctx context.Context, base, quote string, day time.Time) (Rate, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return f.rates.find(base, quote, day)
+}
+
+// Calls returns how many rates have been looked up so far.
+func (f *Fake) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
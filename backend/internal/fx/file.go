@@ -0,0 +1,36 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// File serves rates loaded from a CSV file, in the format ReadCSV reads,
+// when it is created.
+type File struct {
+	rates table
+}
+
+// NewFile loads the rates in the CSV file at path.
+func NewFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open FX_RATES_FILE: %w", err)
+	}
+	defer f.Close()
+	rates, err := ReadCSV(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	t := make(table)
+	for _, r := range rates {
+		t.add(r)
+	}
+	return &File{rates: t}, nil
+}
+
+func (f *File) Rate(ctx context.Context, base, quote string, day time.Time) (Rate, error) {
+	return f.rates.find(base, quote, day)
+}
@@ -0,0 +1,101 @@
+// Package fx converts money between currencies at the exchange rate on a
+// given day. Rates come from a provider selected by FX_PROVIDER: a CSV file,
+// an HTTP rates API, or a fake that knows only the rates it is given. Rates
+// once looked up are kept in the exchange_rates table.
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+)
+
+// ErrNoRate is returned when no rate between two currencies is known for a
+// day.
+var ErrNoRate = errors.New("no exchange rate available")
+
+// codePattern matches ISO 4217 currency codes, e.g. KES.
+var codePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ValidCurrency reports whether code is a three-letter upper-case currency
+// code.
+func ValidCurrency(code string) bool {
+	return codePattern.MatchString(code)
+}
+
+// Rate is what one unit of Base bought in Quote on Date.
+type Rate struct {
+	Base  string
+	Quote string
+	Date  time.Time
+	Rate  *big.Rat
+}
+
+// Provider looks up exchange rates.
+type Provider interface {
+	// Rate returns the rate from base to quote on day, a calendar date, or
+	// the latest before it that the provider knows. It fails with ErrNoRate
+	// when there is none.
+	Rate(ctx context.Context, base, quote string, day time.Time) (Rate, error)
+}
+
+// New returns the provider configured in cfg.
+func New(cfg config.FXConfig) (Provider, error) {
+	switch cfg.Provider {
+	case "file":
+		return NewFile(cfg.RatesFile)
+	case "http":
+		return NewHTTP(HTTPOptions{BaseURL: cfg.HTTPURL})
+	case "fake", "":
+		return NewFake(), nil
+	default:
+		return nil, fmt.Errorf("unknown fx provider %q", cfg.Provider)
+	}
+}
+
+// table holds rates by currency pair, each pair's sorted by date.
+type table map[[2]string][]Rate
+
+// add stores r, replacing any rate for the same pair and date.
+func (t table) add(r Rate) {
+	key := [2]string{r.Base, r.Quote}
+	rates := t[key]
+	i := sort.Search(len(rates), func(i int) bool { return !rates[i].Date.Before(r.Date) })
+	if i < len(rates) && rates[i].Date.Equal(r.Date) {
+		rates[i] = r
+		return
+	}
+	rates = append(rates, Rate{})
+	copy(rates[i+1:], rates[i:])
+	rates[i] = r
+	t[key] = rates
+}
+
+// find returns the latest rate from base to quote on or before day, taking
+// the inverse of a quote to base rate when that is later. Rates more than
+// maxRateAge days older than day are not used.
+func (t table) find(base, quote string, day time.Time) (Rate, error) {
+	latest := func(rates []Rate) (Rate, bool) {
+		i := sort.Search(len(rates), func(i int) bool { return rates[i].Date.After(day) })
+		if i == 0 {
+			return Rate{}, false
+		}
+		return rates[i-1], true
+	}
+	direct, ok := latest(t[[2]string{base, quote}])
+	inverse, iok := latest(t[[2]string{quote, base}])
+	if iok && (!ok || inverse.Date.After(direct.Date)) {
+		direct, ok = Rate{Base: base, Quote: quote, Date: inverse.Date, Rate: new(big.Rat).Inv(inverse.Rate)}, true
+	}
+	if !ok || direct.Date.Before(recurrence.Day(day).AddDate(0, 0, -maxRateAge)) {
+		return Rate{}, ErrNoRate
+	}
+	return direct, nil
+}
@@ -0,0 +1,97 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultHTTPURL is the Frankfurter API, which publishes the European
+// Central Bank's reference rates.
+const DefaultHTTPURL = "https://api.frankfurter.app"
+
+// HTTPOptions configures the HTTP provider. BaseURL and Client are
+// optional.
+type HTTPOptions struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// HTTP looks rates up from an API compatible with Frankfurter's:
+// GET {BaseURL}/{date}?from={base}&to={quote} answers with the rate on that
+// date or the last business day before it. BaseURL can point at a local
+// stand-in for development.
+type HTTP struct {
+	opts   HTTPOptions
+	client *http.Client
+}
+
+// NewHTTP returns an HTTP provider.
+func NewHTTP(opts HTTPOptions) (*HTTP, error) {
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultHTTPURL
+	}
+	if _, err := url.Parse(opts.BaseURL); err != nil {
+		return nil, fmt.Errorf("invalid FX_HTTP_URL: %w", err)
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &HTTP{opts: opts, client: client}, nil
+}
+
+type httpRatesResponse struct {
+	Date  string                 `json:"date"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+func (h *HTTP) Rate(ctx context.Context, base, quote string, day time.Time) (Rate, error) {
+	query := url.Values{"from": {base}, "to": {quote}}
+	endpoint := h.opts.BaseURL + "/" + day.Format(time.DateOnly) + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Rate{}, fmt.Errorf("failed to build rates request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return Rate{}, fmt.Errorf("rates request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	// Frankfurter answers 404 for dates before its data and 422 for
+	// currencies it does not publish.
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity {
+		return Rate{}, ErrNoRate
+	}
+	if resp.StatusCode >= 300 {
+		return Rate{}, fmt.Errorf("rates: %s: %s", resp.Status, strings.TrimSpace(string(raw)))
+	}
+
+	var result httpRatesResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return Rate{}, fmt.Errorf("rates: unexpected response: %w", err)
+	}
+	value, ok := result.Rates[quote]
+	if !ok {
+		return Rate{}, ErrNoRate
+	}
+	rate, ok := new(big.Rat).SetString(value.String())
+	if !ok || rate.Sign() <= 0 {
+		return Rate{}, fmt.Errorf("rates: invalid rate %q", value)
+	}
+	date, err := time.Parse(time.DateOnly, result.Date)
+	if err != nil {
+		date = day
+	}
+	return Rate{Base: base, Quote: quote, Date: date, Rate: rate}, nil
+}
@@ -0,0 +1,303 @@
+// Package goal tracks savings goals: the contributions made towards them, how
+// they are progressing and the milestones they reach.
+package goal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/budget"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/notify"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+	"github.com/nyunja/30budget/backend/internal/utils"
+)
+
+// Milestones are the percentages of a goal's target that notify when
+// reached.
+var Milestones = []int{25, 50, 75, 100}
+
+// minPaceDays is the shortest stretch the saving pace is averaged over, so
+// that a first contribution does not promise an early completion.
+const minPaceDays = 30
+
+// daysPerMonth is the length of an average month.
+var daysPerMonth = big.NewRat(365, 12)
+
+// Progress is how far a goal is towards its target.
+type Progress struct {
+	Saved           money.Decimal `json:"saved"`
+	Remaining       money.Decimal `json:"remaining"`
+	PercentComplete float64       `json:"percentComplete"`
+	// MonthlyPace is the average contributed per month since the goal was
+	// set.
+	MonthlyPace money.Decimal `json:"monthlyPace"`
+	// RequiredMonthly is what must be contributed each month from now on to
+	// reach the target by the target date. It is null without a target date
+	// or once the target is reached.
+	RequiredMonthly money.Decimal `json:"requiredMonthlyContribution"`
+	// ProjectedCompletion is when the target is reached at the current
+	// pace, or was reached. It is null when nothing has been saved.
+	ProjectedCompletion pgtype.Date `json:"projectedCompletionDate"`
+	// OnTrack reports whether the target is reached by the target date at
+	// the current pace. It is null without a target date.
+	OnTrack *bool `json:"onTrack"`
+}
+
+// Evaluate works out g's progress at now, in the user's time zone, given the
+// total saved towards it.
+func Evaluate(g db.Goal, saved *big.Rat, now time.Time) Progress {
+	today := recurrence.Day(now)
+	target := g.TargetAmount.Rat()
+	remaining := new(big.Rat).Sub(target, saved)
+	if remaining.Sign() < 0 {
+		remaining.SetInt64(0)
+	}
+	percent, _ := new(big.Rat).Quo(new(big.Rat).Mul(saved, big.NewRat(100, 1)), target).Float64()
+	p := Progress{
+		Saved:           money.Amount(saved),
+		Remaining:       money.Amount(remaining),
+		PercentComplete: math.Round(percent*10) / 10,
+		MonthlyPace:     money.Amount(new(big.Rat)),
+	}
+
+	// Average over the days since the goal was set, counting today.
+	paceDays := days(recurrence.Day(g.CreatedAt.Time.In(now.Location())), today) + 1
+	if paceDays < minPaceDays {
+		paceDays = minPaceDays
+	}
+	daily := new(big.Rat)
+	if saved.Sign() > 0 {
+		daily.Quo(saved, big.NewRat(int64(paceDays), 1))
+		p.MonthlyPace = money.Amount(new(big.Rat).Mul(daily, daysPerMonth))
+	}
+
+	switch {
+	case remaining.Sign() == 0 && g.AchievedAt.Valid:
+		p.ProjectedCompletion = recurrence.Date(g.AchievedAt.Time.In(now.Location()))
+	case remaining.Sign() == 0:
+		p.ProjectedCompletion = recurrence.Date(today)
+	case daily.Sign() > 0:
+		p.ProjectedCompletion = recurrence.Date(today.AddDate(0, 0, ceil(new(big.Rat).Quo(remaining, daily))))
+	}
+
+	if g.TargetDate.Valid {
+		if remaining.Sign() > 0 {
+			// At least one month's contribution is due, however close or
+			// past the target date is.
+			months := new(big.Rat).Quo(big.NewRat(int64(days(today, g.TargetDate.Time)), 1), daysPerMonth)
+			if months.Cmp(big.NewRat(1, 1)) < 0 {
+				months.SetInt64(1)
+			}
+			p.RequiredMonthly = money.Amount(new(big.Rat).Quo(remaining, months))
+		}
+		onTrack := p.ProjectedCompletion.Valid && !p.ProjectedCompletion.Time.After(g.TargetDate.Time)
+		p.OnTrack = &onTrack
+	}
+	return p
+}
+
+// Contribute records amount, dated at, towards g and checks its milestones.
+// The returned notification, if any, should be dispatched once committed.
+func Contribute(ctx context.Context, q *db.Queries, g db.Goal, amount money.Decimal, at pgtype.Timestamptz, transactionID pgtype.UUID, note pgtype.Text) (db.GoalContribution, *db.Notification, error) {
+	c, err := q.CreateGoalContribution(ctx, db.CreateGoalContributionParams{
+		ID:            utils.NewUUID(),
+		GoalID:        g.ID,
+		UserID:        g.UserID,
+		Amount:        amount,
+		Date:          at,
+		TransactionID: transactionID,
+		Note:          note,
+	})
+	if err != nil {
+		return db.GoalContribution{}, nil, fmt.Errorf("failed to record goal contribution: %w", err)
+	}
+	n, err := Refresh(ctx, q, g)
+	if err != nil {
+		return db.GoalContribution{}, nil, err
+	}
+	return c, n, nil
+}
+
+// Match records t as a contribution to each goal it counts towards,
+// replacing any it made before an edit: an income or expense in a goal's
+// category adds its amount, and income into or expenses out of a goal's
+// account add or take away theirs. The milestones of every goal affected are
+// then re-checked.
+//
+// Run it with transaction-scoped queries after t is saved, then dispatch
+// the returned notifications once committed.
+func Match(ctx context.Context, q *db.Queries, t db.Transaction) ([]db.Notification, error) {
+	affected, err := q.DeleteGoalContributionsByTransaction(ctx, t.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove goal contributions: %w", err)
+	}
+	if t.CategoryID.Valid || t.AccountID.Valid {
+		goals, err := q.ListGoalsForTransaction(ctx, db.ListGoalsForTransactionParams{
+			UserID:     t.UserID,
+			CategoryID: t.CategoryID,
+			AccountID:  t.AccountID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list goals: %w", err)
+		}
+		for _, g := range goals {
+			amount := t.BaseAmount.Rat()
+			if !g.CategoryID.Valid && t.Type == db.TransactionTypeExpense {
+				amount.Neg(amount)
+			}
+			if _, err := q.CreateGoalContribution(ctx, db.CreateGoalContributionParams{
+				ID:            utils.NewUUID(),
+				GoalID:        g.ID,
+				UserID:        t.UserID,
+				Amount:        money.Amount(amount),
+				Date:          t.Date,
+				TransactionID: t.ID,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to record goal contribution: %w", err)
+			}
+			affected = append(affected, g.ID)
+		}
+	}
+	return refreshAll(ctx, q, t.UserID, affected)
+}
+
+// Unmatch removes the contributions made by a transaction and re-checks the
+// milestones of the goals they were made to. Call it before the transaction
+// is deleted.
+func Unmatch(ctx context.Context, q *db.Queries, userID, transactionID pgtype.UUID) ([]db.Notification, error) {
+	affected, err := q.DeleteGoalContributionsByTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove goal contributions: %w", err)
+	}
+	return refreshAll(ctx, q, userID, affected)
+}
+
+// refreshAll refreshes each of the goals once.
+func refreshAll(ctx context.Context, q *db.Queries, userID pgtype.UUID, goalIDs []pgtype.UUID) ([]db.Notification, error) {
+	var notifications []db.Notification
+	seen := make(map[pgtype.UUID]bool)
+	for _, id := range goalIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		g, err := q.GetGoal(ctx, db.GetGoalParams{ID: id, UserID: userID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get goal: %w", err)
+		}
+		n, err := Refresh(ctx, q, g)
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			notifications = append(notifications, *n)
+		}
+	}
+	return notifications, nil
+}
+
+// Refresh checks g's milestones against what has been saved towards it.
+//
+// Each milestone notifies once: reaching it records a goal_milestones row,
+// and only newly reached milestones raise a success notification (the
+// highest one, when several are reached at once). Milestones no longer
+// reached, e.g. after a withdrawal, are cleared so they can fire again.
+// Reaching the whole target marks the goal achieved.
+func Refresh(ctx context.Context, q *db.Queries, g db.Goal) (*db.Notification, error) {
+	savedNumeric, err := q.GetGoalSaved(ctx, g.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum goal contributions: %w", err)
+	}
+	saved := savedNumeric.Rat()
+	target := g.TargetAmount.Rat()
+
+	reached := make(map[int32]bool)
+	var fresh []int
+	for _, m := range Milestones {
+		// saved/target >= m/100, kept exact by cross-multiplying.
+		lhs := new(big.Rat).Mul(saved, big.NewRat(100, 1))
+		rhs := new(big.Rat).Mul(target, big.NewRat(int64(m), 1))
+		if lhs.Cmp(rhs) < 0 {
+			continue
+		}
+		reached[int32(m)] = true
+		inserted, err := q.CreateGoalMilestone(ctx, db.CreateGoalMilestoneParams{GoalID: g.ID, Percent: int32(m)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to record goal milestone: %w", err)
+		}
+		if inserted > 0 {
+			fresh = append(fresh, m)
+		}
+	}
+	milestones, err := q.ListGoalMilestones(ctx, g.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goal milestones: %w", err)
+	}
+	for _, m := range milestones {
+		if reached[m.Percent] {
+			continue
+		}
+		if err := q.DeleteGoalMilestone(ctx, db.DeleteGoalMilestoneParams{GoalID: g.ID, Percent: m.Percent}); err != nil {
+			return nil, fmt.Errorf("failed to clear goal milestone: %w", err)
+		}
+	}
+
+	achieved := reached[100]
+	if achieved != g.AchievedAt.Valid {
+		at := pgtype.Timestamptz{Time: time.Now(), Valid: achieved}
+		if err := q.SetGoalAchieved(ctx, db.SetGoalAchievedParams{ID: g.ID, AchievedAt: at}); err != nil {
+			return nil, fmt.Errorf("failed to mark goal achieved: %w", err)
+		}
+	}
+	if len(fresh) == 0 {
+		return nil, nil
+	}
+
+	milestone := fresh[len(fresh)-1]
+	user, err := q.GetUser(ctx, g.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	n, err := notify.Create(ctx, q, milestoneMessage(user, g, milestone, saved, target))
+	if err != nil {
+		return nil, err
+	}
+	if err := q.SetGoalMilestoneNotification(ctx, db.SetGoalMilestoneNotificationParams{
+		GoalID:         g.ID,
+		Percent:        int32(milestone),
+		NotificationID: n.ID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to link goal milestone notification: %w", err)
+	}
+	return &n, nil
+}
+
+func milestoneMessage(user db.User, g db.Goal, milestone int, saved, target *big.Rat) notify.Message {
+	money := func(r *big.Rat) string { return user.CurrencySymbol + budget.FormatAmount(r) }
+	msg := notify.Message{UserID: g.UserID, Type: db.NotificationTypeSuccess}
+	if milestone >= 100 {
+		msg.Title = fmt.Sprintf("Goal reached: %s", g.Name)
+		msg.Message = fmt.Sprintf("You have saved %s towards %s, reaching your target of %s.", money(saved), g.Name, money(target))
+		return msg
+	}
+	msg.Title = fmt.Sprintf("%s is %d%% funded", g.Name, milestone)
+	msg.Message = fmt.Sprintf("You have saved %s of your %s target for %s, with %s to go.",
+		money(saved), money(target), g.Name, money(new(big.Rat).Sub(target, saved)))
+	return msg
+}
+
+// ceil rounds a non-negative r up to a whole number.
+func ceil(r *big.Rat) int {
+	n := new(big.Int).Add(r.Num(), new(big.Int).Sub(r.Denom(), big.NewInt(1)))
+	return int(n.Quo(n, r.Denom()).Int64())
+}
+
+func days(from, to time.Time) int {
+	return int(recurrence.Day(to).Sub(recurrence.Day(from)).Hours() / 24)
+}
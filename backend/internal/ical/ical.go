@@ -0,0 +1,191 @@
+// Package ical renders iCalendar (RFC 5545) feeds of all-day recurring
+// events, such as bills and paydays, for calendar apps to subscribe to.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+)
+
+const (
+	productID = "-//30Budget//Budget Calendar//EN"
+	// maxLineOctets is the longest a content line may be before folding.
+	maxLineOctets = 75
+)
+
+// Alarm is a reminder shown Before the start of the event's day, or
+// AtHour hours into it when Before is zero.
+type Alarm struct {
+	Before time.Duration
+	AtHour int
+}
+
+// Event is an all-day event repeating on a recurrence rule.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Rule        recurrence.Rule
+	Updated     time.Time
+	Alarm       *Alarm
+}
+
+// Calendar is a named set of events.
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Write renders c as an iCalendar document.
+func (c Calendar) Write(w io.Writer) error {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + productID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	lw.line("X-WR-CALNAME:" + escape(c.Name))
+	now := time.Now()
+	for _, e := range c.Events {
+		e.write(lw, now)
+	}
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+func (e Event) write(lw *lineWriter, now time.Time) {
+	stamp := e.Updated
+	if stamp.IsZero() {
+		stamp = now
+	}
+	lw.line("BEGIN:VEVENT")
+	lw.line("UID:" + e.UID)
+	lw.line("DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"))
+	lw.line("DTSTART;VALUE=DATE:" + date(e.Rule.Start))
+	lw.line("DTEND;VALUE=DATE:" + date(e.Rule.Start.AddDate(0, 0, 1)))
+	if rrule := RRule(e.Rule); rrule != "" {
+		lw.line("RRULE:" + rrule)
+	}
+	lw.line("SUMMARY:" + escape(e.Summary))
+	if e.Description != "" {
+		lw.line("DESCRIPTION:" + escape(e.Description))
+	}
+	lw.line("TRANSP:TRANSPARENT")
+	if e.Alarm != nil {
+		lw.line("BEGIN:VALARM")
+		lw.line("ACTION:DISPLAY")
+		lw.line("DESCRIPTION:" + escape(e.Summary))
+		if e.Alarm.Before > 0 {
+			lw.line("TRIGGER:-" + duration(e.Alarm.Before))
+		} else {
+			lw.line("TRIGGER:" + duration(time.Duration(e.Alarm.AtHour)*time.Hour))
+		}
+		lw.line("END:VALARM")
+	}
+	lw.line("END:VEVENT")
+}
+
+// RRule expresses r as an RRULE value, or "" for a one-off date.
+//
+// Monthly and yearly rules starting after the 28th use BYSETPOS to pick the
+// last of the candidate days in shorter months, matching recurrence's
+// clamping; a plain BYMONTHDAY would skip those months instead.
+func RRule(r recurrence.Rule) string {
+	var parts []string
+	switch r.Frequency {
+	case db.RecurrenceFrequencyDaily:
+		parts = append(parts, "FREQ=DAILY")
+	case db.RecurrenceFrequencyWeekly:
+		parts = append(parts, "FREQ=WEEKLY")
+	case db.RecurrenceFrequencyMonthly:
+		parts = append(parts, "FREQ=MONTHLY")
+		if day := r.Start.Day(); day > 28 {
+			parts = append(parts, "BYMONTHDAY="+dayRange(day), "BYSETPOS=-1")
+		}
+	case db.RecurrenceFrequencyYearly:
+		parts = append(parts, "FREQ=YEARLY")
+		if r.Start.Month() == time.February && r.Start.Day() == 29 {
+			parts = append(parts, "BYMONTH=2", "BYMONTHDAY=28,29", "BYSETPOS=-1")
+		}
+	default:
+		return ""
+	}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if !r.End.IsZero() {
+		parts = append(parts, "UNTIL="+date(r.End))
+	}
+	return strings.Join(parts, ";")
+}
+
+// dayRange lists the days from 28 to day.
+func dayRange(day int) string {
+	days := make([]string, 0, day-27)
+	for d := 28; d <= day; d++ {
+		days = append(days, fmt.Sprint(d))
+	}
+	return strings.Join(days, ",")
+}
+
+func date(t time.Time) string {
+	return t.Format("20060102")
+}
+
+// duration formats d as a DURATION value in whole days, hours or minutes.
+func duration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("P%dD", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("PT%dH", d/time.Hour)
+	default:
+		return fmt.Sprintf("PT%dM", d/time.Minute)
+	}
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// escape makes s safe to use as a TEXT value.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// lineWriter writes CRLF-terminated content lines, folding long ones, and
+// keeps the first error.
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(s) > limit {
+		// Fold on a UTF-8 boundary so no character is split.
+		cut := limit
+		for cut > 0 && !utf8Start(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts.
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	_, lw.err = io.WriteString(lw.w, b.String())
+}
+
+func utf8Start(c byte) bool {
+	return c&0xC0 != 0x80
+}
@@ -0,0 +1,188 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestRRule(t *testing.T) {
+	tests := []struct {
+		name string
+		rule recurrence.Rule
+		want string
+	}{
+		{"once", recurrence.Rule{Frequency: db.RecurrenceFrequencyOnce, Interval: 1, Start: day("2026-03-10")}, ""},
+		{"daily", recurrence.Rule{Frequency: db.RecurrenceFrequencyDaily, Interval: 1, Start: day("2026-03-10")}, "FREQ=DAILY"},
+		{"every two weeks", recurrence.Rule{Frequency: db.RecurrenceFrequencyWeekly, Interval: 2, Start: day("2026-03-10")}, "FREQ=WEEKLY;INTERVAL=2"},
+		{"monthly on the 28th", recurrence.Rule{Frequency: db.RecurrenceFrequencyMonthly, Interval: 1, Start: day("2026-01-28")}, "FREQ=MONTHLY"},
+		{"monthly on the 29th", recurrence.Rule{Frequency: db.RecurrenceFrequencyMonthly, Interval: 1, Start: day("2026-01-29")}, "FREQ=MONTHLY;BYMONTHDAY=28,29;BYSETPOS=-1"},
+		{"monthly on the 30th", recurrence.Rule{Frequency: db.RecurrenceFrequencyMonthly, Interval: 1, Start: day("2026-04-30")}, "FREQ=MONTHLY;BYMONTHDAY=28,29,30;BYSETPOS=-1"},
+		{"quarterly on the 31st", recurrence.Rule{Frequency: db.RecurrenceFrequencyMonthly, Interval: 3, Start: day("2026-01-31")}, "FREQ=MONTHLY;BYMONTHDAY=28,29,30,31;BYSETPOS=-1;INTERVAL=3"},
+		{"yearly", recurrence.Rule{Frequency: db.RecurrenceFrequencyYearly, Interval: 1, Start: day("2026-02-28")}, "FREQ=YEARLY"},
+		{"yearly on 29 February", recurrence.Rule{Frequency: db.RecurrenceFrequencyYearly, Interval: 1, Start: day("2028-02-29")}, "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=28,29;BYSETPOS=-1"},
+		{"until", recurrence.Rule{Frequency: db.RecurrenceFrequencyMonthly, Interval: 1, Start: day("2026-01-15"), End: day("2026-12-15")}, "FREQ=MONTHLY;UNTIL=20261215"},
+		{"31st until", recurrence.Rule{Frequency: db.RecurrenceFrequencyMonthly, Interval: 2, Start: day("2026-01-31"), End: day("2027-01-31")}, "FREQ=MONTHLY;BYMONTHDAY=28,29,30,31;BYSETPOS=-1;INTERVAL=2;UNTIL=20270131"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RRule(tt.rule); got != tt.want {
+				t.Errorf("RRule = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := map[string]string{
+		"Rent":                  "Rent",
+		"Rent, water; power":    `Rent\, water\; power`,
+		`C:\bills`:              `C:\\bills`,
+		"line one\nline two":    `line one\nline two`,
+		"windows\r\nmac\rend":   `windows\nmac\nend`,
+		`already \, escaped`:    `already \\\, escaped`,
+		"colons: stay as they":  "colons: stay as they",
+		"quotes \"stay\" too":   `quotes "stay" too`,
+		"unicode ünïcødé, too!": `unicode ünïcødé\, too!`,
+	}
+	for in, want := range tests {
+		if got := escape(in); got != want {
+			t.Errorf("escape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLineFolding(t *testing.T) {
+	a74 := strings.Repeat("a", 74)
+	a75 := strings.Repeat("a", 75)
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"short", "SUMMARY:Rent", "SUMMARY:Rent\r\n"},
+		{"exactly 75 octets", a75, a75 + "\r\n"},
+		{"76 octets", a75 + "b", a75 + "\r\n b\r\n"},
+		{"continuation lines hold 74 octets", a75 + a74 + "b", a75 + "\r\n " + a74 + "\r\n b\r\n"},
+		// "é" is two octets; splitting at 75 would cut it in half.
+		{"multi-byte character at the fold", a74 + "é", a74 + "\r\n é\r\n"},
+		{"multi-byte character before the fold", strings.Repeat("a", 73) + "éb", strings.Repeat("a", 73) + "é\r\n b\r\n"},
+		// "€" is three octets.
+		{"three-octet characters", strings.Repeat("€", 26), strings.Repeat("€", 25) + "\r\n €\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			lw := &lineWriter{w: &b}
+			lw.line(tt.in)
+			if lw.err != nil {
+				t.Fatal(lw.err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got %q\nwant %q", b.String(), tt.want)
+			}
+			for _, l := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+				if len(l) > maxLineOctets || !utf8.ValidString(l) {
+					t.Errorf("line %q is %d octets or not valid UTF-8", l, len(l))
+				}
+			}
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(b.String(), "\r\n"), "\r\n ", ""); unfolded != tt.in {
+				t.Errorf("unfolds to %q", unfolded)
+			}
+		})
+	}
+}
+
+func TestCalendarWrite(t *testing.T) {
+	c := Calendar{
+		Name: "Bills, paydays",
+		Events: []Event{
+			{
+				UID:         "bill-1@30budget",
+				Summary:     "Rent; flat 4",
+				Description: "KES 25,000.00\nPay landlord",
+				Rule:        recurrence.Rule{Frequency: db.RecurrenceFrequencyMonthly, Interval: 1, Start: day("2026-01-31")},
+				Updated:     time.Date(2026, 1, 2, 8, 30, 0, 0, time.FixedZone("EAT", 3*60*60)),
+				Alarm:       &Alarm{Before: 3 * 24 * time.Hour},
+			},
+			{
+				UID:     "recurring-2@30budget",
+				Summary: "Payday",
+				Rule:    recurrence.Rule{Frequency: db.RecurrenceFrequencyOnce, Interval: 1, Start: day("2026-02-25")},
+				Updated: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+				Alarm:   &Alarm{AtHour: 9},
+			},
+		},
+	}
+	var b strings.Builder
+	if err := c.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//30Budget//Budget Calendar//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		`X-WR-CALNAME:Bills\, paydays`,
+		"BEGIN:VEVENT",
+		"UID:bill-1@30budget",
+		"DTSTAMP:20260102T053000Z",
+		"DTSTART;VALUE=DATE:20260131",
+		"DTEND;VALUE=DATE:20260201",
+		"RRULE:FREQ=MONTHLY;BYMONTHDAY=28,29,30,31;BYSETPOS=-1",
+		`SUMMARY:Rent\; flat 4`,
+		`DESCRIPTION:KES 25\,000.00\nPay landlord`,
+		"TRANSP:TRANSPARENT",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		`DESCRIPTION:Rent\; flat 4`,
+		"TRIGGER:-P3D",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:recurring-2@30budget",
+		"DTSTAMP:20260102T000000Z",
+		"DTSTART;VALUE=DATE:20260225",
+		"DTEND;VALUE=DATE:20260226",
+		"SUMMARY:Payday",
+		"TRANSP:TRANSPARENT",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Payday",
+		"TRIGGER:PT9H",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		24 * time.Hour:   "P1D",
+		72 * time.Hour:   "P3D",
+		9 * time.Hour:    "PT9H",
+		90 * time.Minute: "PT90M",
+	}
+	for d, want := range tests {
+		if got := duration(d); got != want {
+			t.Errorf("duration(%s) = %s, want %s", d, got, want)
+		}
+	}
+}
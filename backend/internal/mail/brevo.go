@@ -0,0 +1,91 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultBrevoURL is Brevo's transactional email API.
+const DefaultBrevoURL = "https://api.brevo.com/v3"
+
+// BrevoOptions configures the Brevo sender. BaseURL and Client are optional
+// and mainly exist so the sender can be pointed at a fake server.
+type BrevoOptions struct {
+	APIKey  string
+	From    Address
+	BaseURL string
+	Client  *http.Client
+}
+
+// Brevo sends email through Brevo's transactional email API.
+type Brevo struct {
+	opts   BrevoOptions
+	client *http.Client
+}
+
+// NewBrevo validates opts and returns a Brevo sender.
+func NewBrevo(opts BrevoOptions) (*Brevo, error) {
+	if opts.APIKey == "" {
+		return nil, errors.New("BREVO_API_KEY is required for brevo email")
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultBrevoURL
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Brevo{opts: opts, client: client}, nil
+}
+
+type brevoContact struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type brevoEmail struct {
+	Sender      brevoContact   `json:"sender"`
+	To          []brevoContact `json:"to"`
+	Subject     string         `json:"subject"`
+	HTMLContent string         `json:"htmlContent,omitempty"`
+	TextContent string         `json:"textContent,omitempty"`
+}
+
+func (b *Brevo) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(brevoEmail{
+		Sender:      brevoContact{Email: b.opts.From.Email, Name: b.opts.From.Name},
+		To:          []brevoContact{{Email: msg.To.Email, Name: msg.To.Name}},
+		Subject:     msg.Subject,
+		HTMLContent: msg.HTML,
+		TextContent: msg.Text,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode brevo email: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.opts.BaseURL+"/smtp/email", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build brevo request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("api-key", b.opts.APIKey)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("brevo request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("brevo: %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
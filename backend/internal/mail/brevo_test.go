@@ -0,0 +1,89 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBrevoSend(t *testing.T) {
+	var got brevoEmail
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/smtp/email" {
+			t.Errorf("request = %s %s, want POST /smtp/email", r.Method, r.URL.Path)
+		}
+		if key := r.Header.Get("api-key"); key != "secret" {
+			t.Errorf("api-key = %q, want secret", key)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"messageId":"<1@brevo>"}`))
+	}))
+	defer srv.Close()
+
+	b, err := NewBrevo(BrevoOptions{
+		APIKey:  "secret",
+		From:    Address{Email: "budget@example.com", Name: "30Budget"},
+		BaseURL: srv.URL + "/",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.Send(context.Background(), Message{
+		To:      Address{Email: "amina@example.com", Name: "Amina"},
+		Subject: "Your weekly digest",
+		HTML:    "<p>Hi</p>",
+		Text:    "Hi",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	want := brevoEmail{
+		Sender:      brevoContact{Email: "budget@example.com", Name: "30Budget"},
+		To:          []brevoContact{{Email: "amina@example.com", Name: "Amina"}},
+		Subject:     "Your weekly digest",
+		HTMLContent: "<p>Hi</p>",
+		TextContent: "Hi",
+	}
+	if got.Sender != want.Sender || len(got.To) != 1 || got.To[0] != want.To[0] ||
+		got.Subject != want.Subject || got.HTMLContent != want.HTMLContent || got.TextContent != want.TextContent {
+		t.Errorf("body = %+v, want %+v", got, want)
+	}
+}
+
+func TestBrevoSendError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code":"unauthorized","message":"Key not found"}`))
+	}))
+	defer srv.Close()
+
+	b, err := NewBrevo(BrevoOptions{APIKey: "wrong", From: Address{Email: "budget@example.com"}, BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.Send(context.Background(), Message{To: Address{Email: "amina@example.com"}, Subject: "Hi", Text: "Hi"})
+	if err == nil {
+		t.Fatal("Send succeeded, want error")
+	}
+	for _, part := range []string{"401", "Key not found"} {
+		if !strings.Contains(err.Error(), part) {
+			t.Errorf("error %q does not mention %q", err, part)
+		}
+	}
+}
+
+func TestNewBrevoRequiresKey(t *testing.T) {
+	if _, err := NewBrevo(BrevoOptions{}); err == nil {
+		t.Error("NewBrevo without an API key succeeded")
+	}
+}
@@ -0,0 +1,58 @@
+// Package mail sends transactional email. The provider is selected by
+// EMAIL_PROVIDER: Brevo's HTTP API, any SMTP server, or a development sink
+// that logs messages and optionally writes them to disk.
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nyunja/30budget/backend/internal/config"
+	"go.uber.org/zap"
+)
+
+// Address is an email address with an optional display name.
+type Address struct {
+	Email string
+	Name  string
+}
+
+// Message is a single email with HTML and plain-text bodies.
+type Message struct {
+	To      Address
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Sender delivers email.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the sender configured in cfg.
+func New(cfg config.EmailConfig, logger *zap.Logger) (Sender, error) {
+	from := Address{Email: cfg.FromEmail, Name: cfg.FromName}
+	if from.Email == "" {
+		return nil, errors.New("FROM_EMAIL is required to send email")
+	}
+	switch cfg.Provider {
+	case "brevo":
+		return NewBrevo(BrevoOptions{APIKey: cfg.BrevoAPIKey, From: from})
+	case "smtp":
+		return NewSMTP(SMTPOptions{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     from,
+		})
+	case "log", "":
+		return NewSink(from, "", logger), nil
+	case "file":
+		return NewSink(from, cfg.OutputDir, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown email provider %q", cfg.Provider)
+	}
+}
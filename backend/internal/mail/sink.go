@@ -0,0 +1,59 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Sink is a development stand-in that logs each message and, when dir is
+// set, writes it there as an .eml file that any mail client can open.
+type Sink struct {
+	from   Address
+	dir    string
+	logger *zap.Logger
+}
+
+// NewSink returns a Sink. An empty dir only logs.
+func NewSink(from Address, dir string, logger *zap.Logger) *Sink {
+	return &Sink{from: from, dir: dir, logger: logger}
+}
+
+func (s *Sink) Send(ctx context.Context, msg Message) error {
+	fields := []zap.Field{zap.String("to", msg.To.Email), zap.String("subject", msg.Subject)}
+	if s.dir == "" {
+		s.logger.Info("Email not sent (log provider)", append(fields, zap.String("text", msg.Text))...)
+		return nil
+	}
+
+	now := time.Now()
+	body, err := buildMIME(s.from, msg, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create email directory: %w", err)
+	}
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), safeName(msg.To.Email))
+	path := filepath.Join(s.dir, name)
+	if err := os.WriteFile(path, body, 0o640); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	s.logger.Info("Email written to file", append(fields, zap.String("path", path))...)
+	return nil
+}
+
+func safeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '@':
+			return r
+		}
+		return '_'
+	}, s)
+}
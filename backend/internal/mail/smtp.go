@@ -0,0 +1,182 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// SMTPOptions configures the SMTP sender. Port 465 uses implicit TLS; other
+// ports upgrade with STARTTLS when the server offers it.
+type SMTPOptions struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     Address
+}
+
+// SMTP sends email through an SMTP server.
+type SMTP struct {
+	opts SMTPOptions
+}
+
+// NewSMTP validates opts and returns an SMTP sender.
+func NewSMTP(opts SMTPOptions) (*SMTP, error) {
+	if opts.Host == "" {
+		return nil, errors.New("SMTP_HOST is required for smtp email")
+	}
+	if opts.Port == "" {
+		opts.Port = "587"
+	}
+	return &SMTP{opts: opts}, nil
+}
+
+// smtpTimeout bounds a whole SMTP exchange, so a server that stops
+// responding cannot hold up the caller, e.g. the outbox worker.
+const smtpTimeout = time.Minute
+
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	body, err := buildMIME(s.opts.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(s.opts.Host, s.opts.Port)
+	tlsConfig := &tls.Config{ServerName: s.opts.Host}
+
+	netDialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	if s.opts.Port == "465" {
+		conn, err = (&tls.Dialer{NetDialer: netDialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = netDialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("smtp connect failed: %w", err)
+	}
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp connect failed: %w", err)
+	}
+	// Cancelling ctx aborts any read or write in progress.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, s.opts.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake failed: %w", err)
+	}
+	defer client.Close()
+	if s.opts.Port != "465" {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("smtp starttls failed: %w", err)
+			}
+		}
+	}
+	if s.opts.Username != "" {
+		auth := smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.opts.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+	if err := client.Mail(s.opts.From.Email); err != nil {
+		return fmt.Errorf("smtp send failed: %w", err)
+	}
+	if err := client.Rcpt(msg.To.Email); err != nil {
+		return fmt.Errorf("smtp send failed: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp send failed: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("smtp send failed: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp send failed: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildMIME renders msg as a multipart/alternative RFC 5322 message.
+func buildMIME(from Address, msg Message, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	var out bytes.Buffer
+	headers := [][2]string{
+		{"From", formatAddress(from)},
+		{"To", formatAddress(msg.To)},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(from.Email)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&out, "%s: %s\r\n", h[0], h[1])
+	}
+	out.WriteString("\r\n")
+
+	parts := []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+	for _, p := range parts {
+		if p.body == "" {
+			continue
+		}
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(p.body)); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build email: %w", err)
+	}
+	out.Write(buf.Bytes())
+	return out.Bytes(), nil
+}
+
+func formatAddress(a Address) string {
+	return (&mail.Address{Name: a.Name, Address: a.Email}).String()
+}
+
+func messageID(from string) string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	domain := "localhost"
+	if _, d, ok := strings.Cut(from, "@"); ok {
+		domain = d
+	}
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
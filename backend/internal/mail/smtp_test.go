@@ -0,0 +1,54 @@
+package mail
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// A server that accepts the connection but never greets must not block Send
+// past its context.
+func TestSMTPSendHonoursContext(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, c := range conns {
+				c.Close()
+			}
+		}()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	s, err := NewSMTP(SMTPOptions{Host: host, Port: port, From: Address{Email: "budget@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Send(ctx, Message{To: Address{Email: "amina@example.com"}, Subject: "Hi", Text: "Hi"})
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Send to a silent server succeeded")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Send did not return after its context expired")
+	}
+}
@@ -0,0 +1,107 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Template names accepted by Render.
+const (
+	TemplateAlert         = "alert"
+	TemplatePasswordReset = "password_reset"
+	TemplateDigest        = "digest"
+)
+
+// Base holds the fields every template uses.
+type Base struct {
+	Name         string
+	AppURL       string
+	SupportEmail string
+}
+
+// AlertData renders TemplateAlert for a single notification.
+type AlertData struct {
+	Base
+	Title   string
+	Message string
+}
+
+// PasswordResetData renders TemplatePasswordReset.
+type PasswordResetData struct {
+	Base
+	ResetURL  string
+	ExpiresIn string
+}
+
+// DigestData renders TemplateDigest. Comparison is a sentence comparing the
+// period with the one before, e.g. "Spending is up 12% on the previous week."
+type DigestData struct {
+	Base
+	Period        string
+	Income        string
+	Expenses      string
+	Net           string
+	Comparison    string
+	Categories    []DigestCategory
+	OverBudget    []DigestCategory
+	Transactions  []DigestTransaction
+	Notifications []DigestNotification
+}
+
+// DigestCategory is one budget line in a digest. Limit is empty for
+// categories without a budget.
+type DigestCategory struct {
+	Name  string
+	Spent string
+	Limit string
+}
+
+// DigestTransaction is one of the period's largest expenses.
+type DigestTransaction struct {
+	Date        string
+	Description string
+	Category    string
+	Amount      string
+}
+
+// DigestNotification is one alert listed in a digest.
+type DigestNotification struct {
+	Title   string
+	Message string
+}
+
+// Render executes the named template with data and returns a message with the
+// subject and both bodies filled in; the caller sets the recipient.
+func Render(name string, data any) (Message, error) {
+	text, err := texttemplate.ParseFS(templateFS, "templates/"+name+".txt")
+	if err != nil {
+		return Message{}, fmt.Errorf("unknown email template %q: %w", name, err)
+	}
+	html, err := htmltemplate.ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html")
+	if err != nil {
+		return Message{}, fmt.Errorf("unknown email template %q: %w", name, err)
+	}
+
+	var subject, textBody, htmlBody bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render email subject: %w", err)
+	}
+	if err := text.ExecuteTemplate(&textBody, name+".txt", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render email text: %w", err)
+	}
+	if err := html.ExecuteTemplate(&htmlBody, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render email html: %w", err)
+	}
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(textBody.String()) + "\n",
+		HTML:    htmlBody.String(),
+	}, nil
+}
@@ -0,0 +1,23 @@
+package money
+
+// minorUnits lists the ISO 4217 currencies whose minor unit is not a
+// hundredth: UGX amounts are whole shillings, KWD ones are to the fils.
+var minorUnits = map[string]int32{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0,
+	"JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0, "RWF": 0,
+	"UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3,
+	"OMR": 3, "TND": 3,
+}
+
+// MinorUnits returns how many decimal places amounts in currency may have:
+// 0 for currencies such as UGX and JPY, 3 for ones such as KWD and BHD, and
+// otherwise 2.
+func MinorUnits(currency string) int32 {
+	if n, ok := minorUnits[currency]; ok {
+		return n
+	}
+	return Scale
+}
@@ -0,0 +1,299 @@
+// Package money holds Decimal, the exact decimal type every NUMERIC column
+// and amount in the API is carried in, along with the per-currency rules for
+// how many decimal places an amount may have.
+package money
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Scale is the fewest decimal places amounts are rendered with. Currencies
+// with fewer minor units still render Scale places, e.g. 1500.00 UGX; those
+// with more, such as KWD, render all of theirs.
+const Scale = 2
+
+// MaxDigits is how many digits amount columns, NUMERIC(19, 3), hold before
+// the decimal point.
+const MaxDigits = 16
+
+// maxExponent bounds the exponent Parse accepts, e.g. 1e20. Anything larger
+// cannot be a real amount.
+const maxExponent = 20
+
+// Decimal is an exact decimal number, coef × 10^-scale. Like pgtype.Numeric
+// its zero value is the SQL NULL, so an amount missing from a request body
+// can be told apart from 0.
+//
+// A Decimal is immutable: operations return a new value and never change
+// their operands, so it can be copied freely.
+type Decimal struct {
+	coef  *big.Int // nil is zero
+	scale int32
+	valid bool
+}
+
+// Null is the SQL NULL. It marshals to JSON null and counts as zero in
+// arithmetic.
+var Null = Decimal{}
+
+// decimalPattern matches a plain or exponent decimal literal, e.g. -12.50 or
+// 1.5e3.
+var decimalPattern = regexp.MustCompile(`^([+-]?)(\d*)(?:\.(\d*))?(?:[eE]([+-]?\d+))?$`)
+
+// New returns coef × 10^-scale, e.g. New(1250, 2) is 12.50. scale must not
+// be negative.
+func New(coef int64, scale int32) Decimal {
+	return Decimal{coef: big.NewInt(coef), scale: scale, valid: true}
+}
+
+// Parse reads a decimal literal such as 1500, -12.50 or 1.5e3. It keeps the
+// literal's decimal places, so Parse("12.50") renders as 12.50.
+func Parse(s string) (Decimal, error) {
+	m := decimalPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil || m[2]+m[3] == "" {
+		return Null, fmt.Errorf("invalid decimal %q", s)
+	}
+	coef, _ := new(big.Int).SetString(m[2]+m[3], 10)
+	if m[1] == "-" {
+		coef.Neg(coef)
+	}
+	scale := int64(len(m[3]))
+	if m[4] != "" {
+		var exp int64
+		if _, err := fmt.Sscan(m[4], &exp); err != nil || exp > maxExponent || exp < -maxExponent {
+			return Null, fmt.Errorf("invalid decimal %q", s)
+		}
+		scale -= exp
+	}
+	if scale < 0 {
+		coef.Mul(coef, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{coef: coef, scale: int32(scale), valid: true}, nil
+}
+
+// FromRat converts r to a Decimal rounded to places decimal places, halves
+// away from zero.
+func FromRat(r *big.Rat, places int32) Decimal {
+	num := new(big.Int).Mul(r.Num(), pow10(places))
+	q, m := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	// Round half away from zero: |2m| >= denom.
+	if m.Sign() != 0 && new(big.Int).Abs(new(big.Int).Lsh(m, 1)).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(m.Sign())))
+	}
+	return Decimal{coef: q, scale: places, valid: true}
+}
+
+// Amount converts r to an amount, rounded to Scale decimal places.
+func Amount(r *big.Rat) Decimal {
+	return FromRat(r, Scale)
+}
+
+// Valid reports whether d is a number rather than Null.
+func (d Decimal) Valid() bool {
+	return d.valid
+}
+
+// Rat returns d as a new exact rational. Null is zero.
+func (d Decimal) Rat() *big.Rat {
+	if d.coef == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(d.coef, pow10(d.scale))
+}
+
+// Float64 returns the nearest float64 to d, for display-only calculations
+// such as percentages.
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	if d.coef == nil {
+		return 0
+	}
+	return d.coef.Sign()
+}
+
+// IsZero reports whether d is zero or Null.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp compares d and e, returning -1, 0 or +1.
+func (d Decimal) Cmp(e Decimal) int {
+	return d.Rat().Cmp(e.Rat())
+}
+
+// Add returns d + e with the larger of their scales.
+func (d Decimal) Add(e Decimal) Decimal {
+	scale := max(d.scale, e.scale)
+	return Decimal{coef: new(big.Int).Add(d.rescaled(scale), e.rescaled(scale)), scale: scale, valid: true}
+}
+
+// Sub returns d - e with the larger of their scales.
+func (d Decimal) Sub(e Decimal) Decimal {
+	return d.Add(e.Neg())
+}
+
+// Mul returns the exact product d × e.
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{coef: new(big.Int).Mul(d.int(), e.int()), scale: d.scale + e.scale, valid: true}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.int()), scale: d.scale, valid: true}
+}
+
+// Round returns d rounded to places decimal places, halves away from zero.
+// A Decimal with fewer places is padded, so Round(2) of 15 is 15.00.
+func (d Decimal) Round(places int32) Decimal {
+	if !d.valid {
+		return d
+	}
+	if places >= d.scale {
+		return Decimal{coef: d.rescaled(places), scale: places, valid: true}
+	}
+	return FromRat(d.Rat(), places)
+}
+
+// RoundTo returns d rounded to the minor units of currency and rendered with
+// at least Scale decimal places, e.g. 1500.50 rounds to 1501.00 in UGX.
+func (d Decimal) RoundTo(currency string) Decimal {
+	if !d.valid {
+		return d
+	}
+	minor := MinorUnits(currency)
+	return d.Round(minor).Round(max(minor, Scale))
+}
+
+// Fits reports whether d has no more precision than currency's minor units,
+// i.e. whether rounding it to them would change nothing.
+func (d Decimal) Fits(currency string) bool {
+	return d.Cmp(d.RoundTo(currency)) == 0
+}
+
+// InRange reports whether d fits an amount column: it has fewer than
+// MaxDigits digits before the decimal point.
+func (d Decimal) InRange() bool {
+	limit := new(big.Rat).SetInt(pow10(MaxDigits))
+	return new(big.Rat).Abs(d.Rat()).Cmp(limit) < 0
+}
+
+// String renders d in plain decimal notation with its own decimal places, or
+// "NULL".
+func (d Decimal) String() string {
+	if !d.valid {
+		return "NULL"
+	}
+	s := d.int().String()
+	if d.scale <= 0 {
+		return s
+	}
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	if n := int(d.scale) + 1 - len(s); n > 0 {
+		s = strings.Repeat("0", n) + s
+	}
+	cut := len(s) - int(d.scale)
+	return sign + s[:cut] + "." + s[cut:]
+}
+
+// MarshalJSON renders d as a JSON number, or null for Null.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if !d.valid {
+		return []byte("null"), nil
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number, a string holding one, or null.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		*d = Null
+		return nil
+	}
+	s := string(b)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// ScanNumeric implements pgtype.NumericScanner so Decimal can be scanned from
+// NUMERIC columns. Trailing zeros past Scale places are dropped, so 1500.000
+// from a three-place column renders as 1500.00 while 1.234 keeps its places.
+func (d *Decimal) ScanNumeric(n pgtype.Numeric) error {
+	switch {
+	case !n.Valid:
+		*d = Null
+	case n.NaN || n.InfinityModifier != pgtype.Finite:
+		return errors.New("cannot scan NaN or infinity into a Decimal")
+	case n.Int == nil:
+		*d = New(0, 0)
+	case n.Exp >= 0:
+		*d = Decimal{coef: new(big.Int).Mul(n.Int, pow10(n.Exp)), valid: true}
+	default:
+		*d = Decimal{coef: new(big.Int).Set(n.Int), scale: -n.Exp, valid: true}
+		d.trim()
+	}
+	return nil
+}
+
+// NumericValue implements pgtype.NumericValuer so Decimal can be written to
+// NUMERIC columns.
+func (d Decimal) NumericValue() (pgtype.Numeric, error) {
+	if !d.valid {
+		return pgtype.Numeric{}, nil
+	}
+	return pgtype.Numeric{Int: d.int(), Exp: -d.scale, Valid: true}, nil
+}
+
+// trim drops trailing zeros past Scale decimal places. It is only used on a
+// Decimal being built, which is why it may change d.
+func (d *Decimal) trim() {
+	ten := big.NewInt(10)
+	q, m := new(big.Int), new(big.Int)
+	for d.scale > Scale {
+		q.QuoRem(d.coef, ten, m)
+		if m.Sign() != 0 {
+			return
+		}
+		d.coef.Set(q)
+		d.scale--
+	}
+}
+
+// int returns the coefficient, zero for nil.
+func (d Decimal) int() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// rescaled returns the coefficient of d at a scale no smaller than its own.
+func (d Decimal) rescaled(scale int32) *big.Int {
+	return new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
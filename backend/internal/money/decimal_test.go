@@ -0,0 +1,241 @@
+package money
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1500", "1500"},
+		{"12.50", "12.50"},
+		{"-0.05", "-0.05"},
+		{"+7.", "7"},
+		{".5", "0.5"},
+		{" 42 ", "42"},
+		{"1.5e3", "1500"},
+		{"2E-3", "0.002"},
+		{"1e20", "100000000000000000000"},
+	}
+	for _, tt := range tests {
+		d, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, in := range []string{"", "-", ".", "abc", "1.2.3", "1,000", "0x10", "1e", "1e21", "1e-21", "1e1000", "NaN", "Infinity"} {
+		if d, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %s, want error", in, d)
+		}
+	}
+}
+
+func TestFromRat(t *testing.T) {
+	tests := []struct {
+		r      *big.Rat
+		places int32
+		want   string
+	}{
+		{big.NewRat(1, 3), 2, "0.33"},
+		{big.NewRat(2, 3), 2, "0.67"},
+		{big.NewRat(5, 1000), 2, "0.01"},
+		{big.NewRat(-5, 1000), 2, "-0.01"},
+		{big.NewRat(-4, 1000), 2, "0.00"},
+		{big.NewRat(25, 10), 0, "3"},
+		{big.NewRat(-25, 10), 0, "-3"},
+		{big.NewRat(7, 1), 2, "7.00"},
+	}
+	for _, tt := range tests {
+		if got := FromRat(tt.r, tt.places).String(); got != tt.want {
+			t.Errorf("FromRat(%s, %d) = %s, want %s", tt.r, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestRoundTo(t *testing.T) {
+	tests := []struct {
+		in, currency string
+		want         string
+		fits         bool
+	}{
+		{"1500", "KES", "1500.00", true},
+		{"1500.5", "KES", "1500.50", true},
+		{"1500.505", "KES", "1500.51", false},
+		{"1500.50", "UGX", "1501.00", false},
+		{"1500.49", "UGX", "1500.00", false},
+		{"1500.00", "UGX", "1500.00", true},
+		{"-0.5", "JPY", "-1.00", false},
+		{"12.345", "KWD", "12.345", true},
+		{"12.3455", "KWD", "12.346", false},
+		{"12.3", "BHD", "12.300", true},
+	}
+	for _, tt := range tests {
+		d, err := Parse(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := d.RoundTo(tt.currency).String(); got != tt.want {
+			t.Errorf("%s.RoundTo(%s) = %s, want %s", tt.in, tt.currency, got, tt.want)
+		}
+		if got := d.Fits(tt.currency); got != tt.fits {
+			t.Errorf("%s.Fits(%s) = %v, want %v", tt.in, tt.currency, got, tt.fits)
+		}
+	}
+	if got := Null.RoundTo("KES"); got.Valid() {
+		t.Errorf("Null.RoundTo = %s, want Null", got)
+	}
+}
+
+func TestInRange(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"0", true},
+		{"9999999999999999.999", true},
+		{"-9999999999999999.999", true},
+		{"10000000000000000", false},
+		{"-1e16", false},
+		{"1e20", false},
+	}
+	for _, tt := range tests {
+		d, err := Parse(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := d.InRange(); got != tt.want {
+			t.Errorf("%s.InRange() = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := New(1250, 2), New(5, 0)
+	if got := a.Add(b).String(); got != "17.50" {
+		t.Errorf("12.50 + 5 = %s", got)
+	}
+	if got := b.Sub(a).String(); got != "-7.50" {
+		t.Errorf("5 - 12.50 = %s", got)
+	}
+	if got := a.Mul(New(3, 1)).String(); got != "3.750" {
+		t.Errorf("12.50 × 0.3 = %s", got)
+	}
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 || New(50, 1).Cmp(b) != 0 {
+		t.Error("Cmp ordered 12.50 and 5 wrongly")
+	}
+	// Operations never change their operands.
+	if a.String() != "12.50" || b.String() != "5" {
+		t.Errorf("operands changed to %s and %s", a, b)
+	}
+	if !Null.IsZero() || Null.Add(b).String() != "5" {
+		t.Error("Null does not count as zero")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var v struct {
+		Amount  Decimal `json:"amount"`
+		Text    Decimal `json:"text"`
+		Limit   Decimal `json:"limit"`
+		Missing Decimal `json:"missing"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount": 1500.50, "text": "12.345", "limit": null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !v.Amount.Valid() || v.Amount.String() != "1500.50" || v.Text.String() != "12.345" {
+		t.Errorf("decoded %s and %s", v.Amount, v.Text)
+	}
+	if v.Limit.Valid() || v.Missing.Valid() {
+		t.Error("null and missing amounts decoded as numbers")
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"amount":1500.50,"text":12.345,"limit":null,"missing":null}`; string(b) != want {
+		t.Errorf("encoded %s, want %s", b, want)
+	}
+
+	for _, in := range []string{`"abc"`, `true`, `{}`, `"1e99"`} {
+		var d Decimal
+		if err := json.Unmarshal([]byte(in), &d); err == nil {
+			t.Errorf("Unmarshal(%s) = %s, want error", in, d)
+		}
+	}
+}
+
+// TestNumericCodec round-trips through pgx's NUMERIC codec in both wire
+// formats, as a query argument and a scanned column would.
+func TestNumericCodec(t *testing.T) {
+	m := pgtype.NewMap()
+	for _, in := range []string{"0", "1500.00", "-12.345", "9999999999999999.999", "0.0000000001", "1000000"} {
+		d, err := Parse(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+			buf, err := m.Encode(pgtype.NumericOID, format, d, nil)
+			if err != nil {
+				t.Fatalf("encode %s: %v", in, err)
+			}
+			var got Decimal
+			if err := m.Scan(pgtype.NumericOID, format, buf, &got); err != nil {
+				t.Fatalf("scan %s: %v", in, err)
+			}
+			if got.Cmp(d) != 0 || !got.Valid() {
+				t.Errorf("format %d: %s came back as %s", format, in, got)
+			}
+		}
+	}
+
+	var got Decimal
+	if err := m.Scan(pgtype.NumericOID, pgtype.BinaryFormatCode, nil, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Valid() {
+		t.Errorf("NULL scanned as %s", got)
+	}
+	buf, err := m.Encode(pgtype.NumericOID, pgtype.BinaryFormatCode, Null, nil)
+	if err != nil || buf != nil {
+		t.Errorf("Null encoded as %v, %v; want SQL NULL", buf, err)
+	}
+	if err := m.Scan(pgtype.NumericOID, pgtype.TextFormatCode, []byte("NaN"), &got); err == nil {
+		t.Error("NaN scanned without error")
+	}
+}
+
+func TestScanNumericTrimsZeros(t *testing.T) {
+	tests := []struct {
+		n    pgtype.Numeric
+		want string
+	}{
+		{pgtype.Numeric{Int: big.NewInt(1500000), Exp: -3, Valid: true}, "1500.00"},
+		{pgtype.Numeric{Int: big.NewInt(12340), Exp: -3, Valid: true}, "12.34"},
+		{pgtype.Numeric{Int: big.NewInt(12345), Exp: -3, Valid: true}, "12.345"},
+		{pgtype.Numeric{Int: big.NewInt(1292500000000), Exp: -10, Valid: true}, "129.25"},
+		{pgtype.Numeric{Int: big.NewInt(15), Exp: 2, Valid: true}, "1500"},
+		{pgtype.Numeric{Int: big.NewInt(150), Exp: -1, Valid: true}, "15.0"},
+	}
+	for _, tt := range tests {
+		var d Decimal
+		if err := d.ScanNumeric(tt.n); err != nil {
+			t.Fatal(err)
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("ScanNumeric(%de%d) = %s, want %s", tt.n.Int, tt.n.Exp, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package networth
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/fx"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+	"go.uber.org/zap"
+)
+
+// Job snapshots each user's net worth once a day.
+type Job struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	rates   *fx.Converter
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewJob(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, rates *fx.Converter) *Job {
+	return &Job{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		rates:   rates,
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+// Run checks for due snapshots every interval until ctx is cancelled.
+func (j *Job) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.snapshotAll(ctx)
+		}
+	}
+}
+
+// snapshotAll records yesterday's closing net worth, in each user's time
+// zone, for users who do not have it yet. Missed days are not backfilled.
+func (j *Job) snapshotAll(ctx context.Context) {
+	now := time.Now()
+	users, err := j.queries.ListNetWorthSnapshotUsers(ctx)
+	if err != nil {
+		j.logger.Error("Failed to list users for net worth snapshots", zap.Error(err))
+		return
+	}
+	for _, u := range users {
+		loc, err := time.LoadLocation(u.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		yesterday := recurrence.Day(now.In(loc)).AddDate(0, 0, -1)
+		if u.Latest.Valid && !u.Latest.Time.Before(yesterday) {
+			continue
+		}
+		if _, err := Snapshot(ctx, j.queries, j.rates, u.ID, yesterday, loc); err != nil {
+			j.logger.Error("Failed to snapshot net worth", zap.String("user_id", u.ID.String()), zap.Error(err))
+		}
+	}
+}
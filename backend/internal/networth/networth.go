@@ -0,0 +1,165 @@
+// Package networth adds up what a user owns and owes: account balances,
+// debts, and items such as property valued by hand.
+package networth
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/fx"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+)
+
+// Sources of assets and liabilities.
+const (
+	SourceAccount = "account"
+	SourceDebt    = "debt"
+	SourceItem    = "item"
+)
+
+// Line is one asset or liability.
+type Line struct {
+	Source string `json:"source"`
+	// ID is the account, debt or item; it is null for money recorded
+	// against no account.
+	ID   pgtype.UUID `json:"id"`
+	Name string      `json:"name"`
+	// Value is what the line is worth or, for a liability, what is owed, in
+	// the user's currency.
+	Value money.Decimal `json:"value"`
+	// Currency and Amount are the line's own currency and its value in it,
+	// which differ from the user's for accounts held in another currency.
+	Currency string        `json:"currency"`
+	Amount   money.Decimal `json:"amount"`
+}
+
+// Statement is a user's net worth on a day and what makes it up.
+type Statement struct {
+	Date             pgtype.Date   `json:"date"`
+	Assets           []Line        `json:"assets"`
+	Liabilities      []Line        `json:"liabilities"`
+	TotalAssets      money.Decimal `json:"totalAssets"`
+	TotalLiabilities money.Decimal `json:"totalLiabilities"`
+	NetWorth         money.Decimal `json:"netWorth"`
+}
+
+// Compute draws up the statement for day, a calendar date, from account
+// balances as of asOf, current debt balances, and each item's latest
+// valuation on or before day.
+//
+// Open accounts, and money recorded against no account, count as assets
+// when in credit and as liabilities when overdrawn, like a credit card.
+// Accounts in another currency are converted to the user's at day's rate.
+func Compute(ctx context.Context, q *db.Queries, rates *fx.Converter, userID pgtype.UUID, day, asOf time.Time) (Statement, error) {
+	user, err := q.GetUser(ctx, userID)
+	if err != nil {
+		return Statement{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	s := Statement{Date: recurrence.Date(day), Assets: []Line{}, Liabilities: []Line{}}
+	add := func(source string, id pgtype.UUID, name, currency string, amount, value *big.Rat, liability bool) {
+		if value.Sign() < 0 {
+			amount = new(big.Rat).Neg(amount)
+			value = new(big.Rat).Neg(value)
+			liability = !liability
+		}
+		line := Line{Source: source, ID: id, Name: name, Value: money.Amount(value), Currency: currency, Amount: money.Amount(amount)}
+		if liability {
+			s.Liabilities = append(s.Liabilities, line)
+		} else {
+			s.Assets = append(s.Assets, line)
+		}
+	}
+
+	at := pgtype.Timestamptz{Time: asOf, Valid: true}
+	balances, err := q.ListAccountBalances(ctx, db.ListAccountBalancesParams{AsOf: at, UserID: userID})
+	if err != nil {
+		return Statement{}, fmt.Errorf("failed to get account balances: %w", err)
+	}
+	for _, b := range balances {
+		if b.Archived {
+			continue
+		}
+		balance := b.Balance.Rat()
+		value, err := rates.Convert(ctx, q, balance, b.Currency, user.Currency, day)
+		if err != nil {
+			return Statement{}, fmt.Errorf("failed to convert %s balance: %w", b.Name, err)
+		}
+		add(SourceAccount, b.ID, b.Name, b.Currency, balance, value, false)
+	}
+	unassigned, err := q.GetUnassignedBalance(ctx, db.GetUnassignedBalanceParams{UserID: userID, AsOf: at})
+	if err != nil {
+		return Statement{}, fmt.Errorf("failed to get unassigned balance: %w", err)
+	}
+	if balance := unassigned.Rat(); balance.Sign() != 0 {
+		add(SourceAccount, pgtype.UUID{}, "Unassigned", user.Currency, balance, balance, false)
+	}
+
+	debts, err := q.ListDebtsByUser(ctx, userID)
+	if err != nil {
+		return Statement{}, fmt.Errorf("failed to list debts: %w", err)
+	}
+	for _, d := range debts {
+		if balance := d.Balance.Rat(); balance.Sign() > 0 {
+			add(SourceDebt, d.ID, d.Name, user.Currency, balance, balance, true)
+		}
+	}
+
+	items, err := q.ListNetWorthItemValues(ctx, db.ListNetWorthItemValuesParams{AsOf: s.Date, UserID: userID})
+	if err != nil {
+		return Statement{}, fmt.Errorf("failed to list net worth items: %w", err)
+	}
+	for _, i := range items {
+		if i.Value.Valid() {
+			value := i.Value.Rat()
+			add(SourceItem, i.ID, i.Name, user.Currency, value, value, i.Kind == db.NetWorthKindLiability)
+		}
+	}
+
+	assets, liabilities := sum(s.Assets, ""), sum(s.Liabilities, "")
+	s.TotalAssets = money.Amount(assets)
+	s.TotalLiabilities = money.Amount(liabilities)
+	s.NetWorth = money.Amount(new(big.Rat).Sub(assets, liabilities))
+	return s, nil
+}
+
+// Snapshot records the statement for the end of day, a calendar date, in
+// loc.
+func Snapshot(ctx context.Context, q *db.Queries, rates *fx.Converter, userID pgtype.UUID, day time.Time, loc *time.Location) (db.NetWorthSnapshot, error) {
+	endOfDay := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+	s, err := Compute(ctx, q, rates, userID, day, endOfDay)
+	if err != nil {
+		return db.NetWorthSnapshot{}, err
+	}
+	snapshot, err := q.UpsertNetWorthSnapshot(ctx, db.UpsertNetWorthSnapshotParams{
+		UserID:             userID,
+		SnapshotDate:       s.Date,
+		AccountAssets:      money.Amount(sum(s.Assets, SourceAccount)),
+		ItemAssets:         money.Amount(sum(s.Assets, SourceItem)),
+		TotalAssets:        s.TotalAssets,
+		AccountLiabilities: money.Amount(sum(s.Liabilities, SourceAccount)),
+		DebtLiabilities:    money.Amount(sum(s.Liabilities, SourceDebt)),
+		ItemLiabilities:    money.Amount(sum(s.Liabilities, SourceItem)),
+		TotalLiabilities:   s.TotalLiabilities,
+		NetWorth:           s.NetWorth,
+	})
+	if err != nil {
+		return db.NetWorthSnapshot{}, fmt.Errorf("failed to save net worth snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// sum adds up the lines from source, or all of them if source is empty.
+func sum(lines []Line, source string) *big.Rat {
+	total := new(big.Rat)
+	for _, l := range lines {
+		if source == "" || l.Source == source {
+			total.Add(total, l.Value.Rat())
+		}
+	}
+	return total
+}
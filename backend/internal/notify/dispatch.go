@@ -0,0 +1,119 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/events"
+	"github.com/nyunja/30budget/backend/internal/mail"
+	"github.com/nyunja/30budget/backend/internal/sms"
+	"go.uber.org/zap"
+)
+
+// Dispatcher delivers notifications once the change that created them has
+// committed: in-app to connected clients straight away, and email and SMS
+// from the outbox Create writes them to (see Run).
+type Dispatcher struct {
+	queries *db.Queries
+	hub     *events.Hub
+	mailer  mail.Sender
+	sms     sms.Sender
+	wake    chan struct{}
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewDispatcher(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger, hub *events.Hub, mailer mail.Sender, texter sms.Sender) *Dispatcher {
+	return &Dispatcher{
+		queries: db.New(dbPool),
+		hub:     hub,
+		mailer:  mailer,
+		sms:     texter,
+		wake:    make(chan struct{}, 1),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+// Publish sends a real-time event to the user's connected clients.
+func (d *Dispatcher) Publish(ctx context.Context, userID pgtype.UUID, eventType string, data any) {
+	d.hub.Publish(context.WithoutCancel(ctx), userID.String(), eventType, data)
+}
+
+// Dispatch pushes n to the user's connected clients, if they want it
+// in-app, and wakes the outbox worker so its email and SMS go out without
+// waiting for the next poll. Call it after the transaction that created n
+// has committed.
+func (d *Dispatcher) Dispatch(ctx context.Context, n db.Notification) {
+	go d.publishNotification(context.WithoutCancel(ctx), n)
+	d.Wake()
+}
+
+// Wake asks the outbox worker to check for due messages now.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) publishNotification(ctx context.Context, n db.Notification) {
+	user, err := d.queries.GetUser(ctx, n.UserID)
+	if err != nil {
+		d.logger.Error("Failed to load user for notification", zap.String("notification_id", n.ID.String()), zap.Error(err))
+		return
+	}
+	prefs, err := LoadPreferences(ctx, d.queries, user)
+	if err != nil {
+		d.logger.Error("Failed to load notification preferences", zap.String("notification_id", n.ID.String()), zap.Error(err))
+		return
+	}
+	if prefs.Enabled(n.Type, db.NotificationChannelInApp) {
+		d.Publish(ctx, n.UserID, events.TypeNotification, n)
+	}
+}
+
+// send delivers one outbox message.
+func (d *Dispatcher) send(ctx context.Context, user db.User, n db.Notification, m db.NotificationOutbox) error {
+	switch m.Channel {
+	case db.NotificationChannelEmail:
+		if m.Payload == nil {
+			return d.email(ctx, user, n)
+		}
+		var msg mail.Message
+		if err := json.Unmarshal(m.Payload, &msg); err != nil {
+			return fmt.Errorf("invalid email payload: %w", err)
+		}
+		msg.To = mail.Address{Email: user.Email, Name: user.Name}
+		return d.mailer.Send(ctx, msg)
+	case db.NotificationChannelSms:
+		return d.sms.Send(ctx, user.PhoneNumber.String, smsBody(n))
+	}
+	return fmt.Errorf("unsupported delivery channel %q", m.Channel)
+}
+
+func (d *Dispatcher) email(ctx context.Context, user db.User, n db.Notification) error {
+	msg, err := mail.Render(mail.TemplateAlert, mail.AlertData{
+		Base: mail.Base{
+			Name:         user.Name,
+			AppURL:       d.config.App.FrontendURL,
+			SupportEmail: d.config.Email.SupportEmail,
+		},
+		Title:   n.Title,
+		Message: n.Message,
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = mail.Address{Email: user.Email, Name: user.Name}
+	return d.mailer.Send(ctx, msg)
+}
+
+func smsBody(n db.Notification) string {
+	return "30Budget: " + n.Title + ". " + n.Message
+}
@@ -0,0 +1,53 @@
+// Package notify creates user notifications on behalf of the rest of the
+// backend. Everything that raises a notification goes through Create so that
+// delivery concerns live in one place.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/mail"
+	"github.com/nyunja/30budget/backend/internal/utils"
+)
+
+// Message is a notification to raise for a user.
+type Message struct {
+	UserID  pgtype.UUID
+	Type    db.NotificationType
+	Title   string
+	Message string
+	// Date defaults to now.
+	Date time.Time
+	// Email, if set, is sent instead of the standard alert email when the
+	// user wants Type by email. The recipient is filled in when it is sent.
+	Email *mail.Message
+}
+
+// Create stores msg as an in-app notification and queues its email and SMS
+// deliveries in the outbox. Pass a transaction-scoped *db.Queries so the
+// notification and its deliveries commit with the change that caused them,
+// then call Dispatcher.Dispatch once the transaction has committed.
+func Create(ctx context.Context, q *db.Queries, msg Message) (db.Notification, error) {
+	if msg.Date.IsZero() {
+		msg.Date = time.Now()
+	}
+	n, err := q.CreateNotification(ctx, db.CreateNotificationParams{
+		ID:      utils.NewUUID(),
+		UserID:  msg.UserID,
+		Title:   msg.Title,
+		Message: msg.Message,
+		Type:    msg.Type,
+		Date:    pgtype.Timestamptz{Time: msg.Date, Valid: true},
+	})
+	if err != nil {
+		return db.Notification{}, fmt.Errorf("failed to create notification: %w", err)
+	}
+	if err := enqueue(ctx, q, n, msg.Email); err != nil {
+		return db.Notification{}, err
+	}
+	return n, nil
+}
@@ -0,0 +1,210 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/mail"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// outboxBatchSize caps how many messages one claim takes.
+	outboxBatchSize = 100
+	// outboxLease is how long a claimed message is left alone before
+	// another worker may retry it, should the one sending it die.
+	outboxLease = 5 * time.Minute
+	// outboxRetention is how long sent and cancelled messages are kept.
+	outboxRetention = 7 * 24 * time.Hour
+
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = 6 * time.Hour
+)
+
+// enqueue writes n's email and SMS deliveries to the outbox according to the
+// user's preferences, scheduled for the end of quiet hours if they are in
+// them. email replaces the standard alert email when set; it is only sent if
+// the user wants n's type by email, like any other.
+func enqueue(ctx context.Context, q *db.Queries, n db.Notification, email *mail.Message) error {
+	user, err := q.GetUser(ctx, n.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	prefs, err := LoadPreferences(ctx, q, user)
+	if err != nil {
+		return err
+	}
+	next := time.Now()
+	if until, quiet := prefs.QuietUntil(next); quiet {
+		next = until
+	}
+
+	params := db.CreateOutboxMessageParams{
+		NotificationID: n.ID,
+		UserID:         n.UserID,
+		NextAttemptAt:  pgtype.Timestamptz{Time: next, Valid: true},
+	}
+	if prefs.Enabled(n.Type, db.NotificationChannelEmail) {
+		params.ID = utils.NewUUID()
+		params.Channel = db.NotificationChannelEmail
+		params.Payload = nil
+		if email != nil {
+			if params.Payload, err = json.Marshal(email); err != nil {
+				return fmt.Errorf("failed to encode email: %w", err)
+			}
+		}
+		if err := q.CreateOutboxMessage(ctx, params); err != nil {
+			return fmt.Errorf("failed to queue email: %w", err)
+		}
+	}
+	if wantsSMS(user, prefs, n.Type) {
+		params.ID = utils.NewUUID()
+		params.Channel = db.NotificationChannelSms
+		params.Payload = nil
+		if err := q.CreateOutboxMessage(ctx, params); err != nil {
+			return fmt.Errorf("failed to queue SMS: %w", err)
+		}
+	}
+	return nil
+}
+
+func wantsSMS(user db.User, prefs Preferences, t db.NotificationType) bool {
+	return prefs.Enabled(t, db.NotificationChannelSms) && user.PhoneNumber.Valid && user.PhoneVerifiedAt.Valid
+}
+
+// Run is the outbox worker. It sends due messages every interval, or sooner
+// when woken by Dispatch, until ctx is cancelled. Failed sends are retried
+// with exponential backoff; after App.NotificationMaxAttempts attempts a
+// message is marked dead and left for an admin to replay.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+			d.deliverDue(ctx)
+		case <-ticker.C:
+			d.deliverDue(ctx)
+			d.pruneOutbox(ctx)
+		}
+	}
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	for {
+		messages, err := d.queries.ClaimOutboxMessages(ctx, db.ClaimOutboxMessagesParams{
+			LeaseUntil: pgtype.Timestamptz{Time: time.Now().Add(outboxLease), Valid: true},
+			RowLimit:   outboxBatchSize,
+		})
+		if err != nil {
+			d.logger.Error("Failed to claim outbox messages", zap.Error(err))
+			return
+		}
+		for _, m := range messages {
+			d.deliver(ctx, m)
+		}
+		if len(messages) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+// deliver attempts one claimed message and records the outcome.
+func (d *Dispatcher) deliver(ctx context.Context, m db.NotificationOutbox) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	log := d.logger.With(zap.String("outbox_id", m.ID.String()), zap.String("channel", string(m.Channel)))
+
+	n, err := d.queries.GetNotification(ctx, db.GetNotificationParams{ID: m.NotificationID, UserID: m.UserID})
+	if err != nil {
+		d.fail(ctx, log, m, fmt.Errorf("failed to load notification: %w", err))
+		return
+	}
+	user, err := d.queries.GetUser(ctx, m.UserID)
+	if err != nil {
+		d.fail(ctx, log, m, fmt.Errorf("failed to load user: %w", err))
+		return
+	}
+	prefs, err := LoadPreferences(ctx, d.queries, user)
+	if err != nil {
+		d.fail(ctx, log, m, err)
+		return
+	}
+
+	// Preferences may have changed since the message was queued.
+	wanted := prefs.Enabled(n.Type, m.Channel)
+	if m.Channel == db.NotificationChannelSms {
+		wanted = wantsSMS(user, prefs, n.Type)
+	}
+	if !wanted {
+		if err := d.queries.CancelOutboxMessage(ctx, m.ID); err != nil {
+			log.Error("Failed to cancel outbox message", zap.Error(err))
+		}
+		return
+	}
+	if until, quiet := prefs.QuietUntil(time.Now()); quiet {
+		if err := d.queries.DeferOutboxMessage(ctx, db.DeferOutboxMessageParams{
+			ID:            m.ID,
+			NextAttemptAt: pgtype.Timestamptz{Time: until, Valid: true},
+		}); err != nil {
+			log.Error("Failed to defer outbox message", zap.Error(err))
+		}
+		return
+	}
+
+	if err := d.send(ctx, user, n, m); err != nil {
+		d.fail(ctx, log, m, err)
+		return
+	}
+	if err := d.queries.MarkOutboxMessageSent(ctx, m.ID); err != nil {
+		log.Error("Failed to mark outbox message sent", zap.Error(err))
+	}
+}
+
+// fail schedules a retry for m, or dead-letters it once it has used all its
+// attempts. Errors saying the notification or user is gone are not retried.
+func (d *Dispatcher) fail(ctx context.Context, log *zap.Logger, m db.NotificationOutbox, cause error) {
+	params := db.MarkOutboxMessageFailedParams{
+		ID:            m.ID,
+		Status:        db.OutboxStatusPending,
+		LastError:     pgtype.Text{String: cause.Error(), Valid: true},
+		NextAttemptAt: pgtype.Timestamptz{Time: time.Now().Add(backoff(int(m.Attempts))), Valid: true},
+	}
+	if int(m.Attempts) >= d.config.App.NotificationMaxAttempts || errors.Is(cause, pgx.ErrNoRows) {
+		params.Status = db.OutboxStatusDead
+		log.Error("Outbox message dead-lettered", zap.Int32("attempts", m.Attempts), zap.Error(cause))
+	} else {
+		log.Warn("Outbox message failed, will retry", zap.Int32("attempts", m.Attempts), zap.Time("next_attempt_at", params.NextAttemptAt.Time), zap.Error(cause))
+	}
+	if err := d.queries.MarkOutboxMessageFailed(ctx, params); err != nil {
+		log.Error("Failed to record outbox failure", zap.Error(err))
+	}
+}
+
+func (d *Dispatcher) pruneOutbox(ctx context.Context) {
+	before := pgtype.Timestamptz{Time: time.Now().Add(-outboxRetention), Valid: true}
+	if _, err := d.queries.DeleteFinishedOutboxMessages(ctx, before); err != nil {
+		d.logger.Error("Failed to prune outbox", zap.Error(err))
+	}
+}
+
+// backoff is the delay before retrying after the given number of attempts:
+// doubling from outboxBaseBackoff up to outboxMaxBackoff, with up to 20%
+// jitter so messages that failed together do not retry together.
+func backoff(attempts int) time.Duration {
+	delay := outboxMaxBackoff
+	if attempts < 20 {
+		delay = min(outboxBaseBackoff<<max(attempts-1, 0), outboxMaxBackoff)
+	}
+	return delay + rand.N(delay/5)
+}
@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+)
+
+// Types and Channels list every notification type and delivery channel.
+var (
+	Types    = []db.NotificationType{db.NotificationTypeInfo, db.NotificationTypeWarning, db.NotificationTypeAlert, db.NotificationTypeSuccess}
+	Channels = []db.NotificationChannel{db.NotificationChannelInApp, db.NotificationChannelEmail, db.NotificationChannelSms}
+)
+
+// Supported reports whether channel can carry notifications of type t. SMS is
+// reserved for alerts.
+func Supported(t db.NotificationType, channel db.NotificationChannel) bool {
+	return channel != db.NotificationChannelSms || t == db.NotificationTypeAlert
+}
+
+// DefaultEnabled is whether a channel is on for a type before the user
+// changes anything: everything in-app; info, which digests are sent as,
+// warnings and alerts by email; and alerts by SMS.
+func DefaultEnabled(t db.NotificationType, channel db.NotificationChannel) bool {
+	switch channel {
+	case db.NotificationChannelInApp:
+		return true
+	case db.NotificationChannelEmail:
+		return t != db.NotificationTypeSuccess
+	case db.NotificationChannelSms:
+		return t == db.NotificationTypeAlert
+	}
+	return false
+}
+
+type preferenceKey struct {
+	t       db.NotificationType
+	channel db.NotificationChannel
+}
+
+// Preferences are a user's notification settings with defaults filled in.
+type Preferences struct {
+	Location        *time.Location
+	QuietHoursStart pgtype.Time
+	QuietHoursEnd   pgtype.Time
+	DigestFrequency db.DigestFrequency
+	overrides       map[preferenceKey]bool
+}
+
+// LoadPreferences reads user's notification preferences.
+func LoadPreferences(ctx context.Context, q *db.Queries, user db.User) (Preferences, error) {
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	prefs := Preferences{
+		Location:        loc,
+		DigestFrequency: db.DigestFrequencyWeekly,
+		overrides:       make(map[preferenceKey]bool),
+	}
+
+	settings, err := q.GetNotificationSettings(ctx, user.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Preferences{}, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	if err == nil {
+		prefs.QuietHoursStart = settings.QuietHoursStart
+		prefs.QuietHoursEnd = settings.QuietHoursEnd
+		prefs.DigestFrequency = settings.DigestFrequency
+	}
+
+	rows, err := q.ListNotificationPreferences(ctx, user.ID)
+	if err != nil {
+		return Preferences{}, fmt.Errorf("failed to list notification preferences: %w", err)
+	}
+	for _, row := range rows {
+		prefs.overrides[preferenceKey{row.Type, row.Channel}] = row.Enabled
+	}
+	return prefs, nil
+}
+
+// Enabled reports whether notifications of type t go out over channel.
+func (p Preferences) Enabled(t db.NotificationType, channel db.NotificationChannel) bool {
+	if !Supported(t, channel) {
+		return false
+	}
+	if enabled, ok := p.overrides[preferenceKey{t, channel}]; ok {
+		return enabled
+	}
+	return DefaultEnabled(t, channel)
+}
+
+// QuietUntil reports whether at falls inside the user's quiet hours and, if
+// so, when they end. Quiet hours may wrap past midnight, e.g. 22:00-07:00.
+func (p Preferences) QuietUntil(at time.Time) (time.Time, bool) {
+	if !p.QuietHoursStart.Valid || !p.QuietHoursEnd.Valid {
+		return time.Time{}, false
+	}
+	start := time.Duration(p.QuietHoursStart.Microseconds) * time.Microsecond
+	end := time.Duration(p.QuietHoursEnd.Microseconds) * time.Microsecond
+	if start == end {
+		return time.Time{}, false
+	}
+
+	local := at.In(p.Location)
+	clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+	endOn := func(dayOffset int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+dayOffset, 0, 0, 0, 0, p.Location).Add(end)
+	}
+	switch {
+	case start < end && clock >= start && clock < end:
+		return endOn(0), true
+	case start > end && clock >= start:
+		return endOn(1), true
+	case start > end && clock < end:
+		return endOn(0), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,52 @@
+// Package payee normalizes merchant and payee names so that the many ways a
+// bank or M-Pesa statement spells the same merchant resolve to one payee.
+package payee
+
+import (
+	"strings"
+	"unicode"
+)
+
+// noiseWords appear in statement descriptions but say nothing about who was paid.
+var noiseWords = map[string]bool{
+	"pos": true, "till": true, "paybill": true, "buy": true, "goods": true, "ref": true,
+	"purchase": true, "payment": true, "to": true, "from": true, "ltd": true, "limited": true,
+	"plc": true, "inc": true, "co": true, "ke": true, "kenya": true,
+}
+
+// Normalize reduces a name or description to the key used to match payees:
+// lower case, punctuation dropped, and numbers and statement noise removed.
+// "NAIVAS WESTGATE 0034" and "Naivas Westgate" both become "naivas westgate".
+func Normalize(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	})
+	var kept []string
+	for _, f := range fields {
+		if noiseWords[f] || hasDigit(f) {
+			continue
+		}
+		kept = append(kept, f)
+	}
+	key := strings.Join(kept, " ")
+	if len(key) > 255 {
+		key = key[:255]
+	}
+	return key
+}
+
+// DisplayName turns a raw description into a readable payee name,
+// e.g. "NAIVAS WESTGATE 0034" becomes "Naivas Westgate".
+func DisplayName(s string) string {
+	words := strings.Fields(Normalize(s))
+	for i, w := range words {
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+func hasDigit(s string) bool {
+	return strings.IndexFunc(s, unicode.IsDigit) >= 0
+}
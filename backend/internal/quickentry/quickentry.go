@@ -0,0 +1,357 @@
+// Package quickentry parses short free-text notes such as "lunch 450 yesterday
+// cash" or "salary 85000 on 25th" into transaction drafts. Parsing is purely
+// rule based and deterministic: the same text and reference time always give
+// the same draft.
+package quickentry
+
+import (
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+)
+
+// Draft is the structured reading of a quick-entry note. It is returned to the
+// client for confirmation and is never saved directly.
+type Draft struct {
+	Amount       money.Decimal      `json:"amount"`
+	Currency     string             `json:"currency,omitempty"`
+	Date         time.Time          `json:"date"`
+	Type         db.TransactionType `json:"type"`
+	Description  string             `json:"description"`
+	AccountHint  string             `json:"accountHint,omitempty"`
+	CategoryHint string             `json:"categoryHint,omitempty"`
+	// Missing lists the fields that could not be read from the text.
+	Missing []string `json:"missing,omitempty"`
+}
+
+var (
+	amountPattern   = regexp.MustCompile(`^(?:(kes|ksh|kshs|usd|ugx|tzs|ngn|eur|gbp)\.?)?(\d{1,3}(?:,\d{3})+|\d+)(?:\.(\d{1,2}))?(k|m)?(?:/=|/-)?$`)
+	isoDatePattern  = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	slashPattern    = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})(?:/(\d{2}|\d{4}))?$`)
+	ordinalPattern  = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)$`)
+	agoNumberWords  = map[string]int{"a": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6, "seven": 7}
+	currencyAliases = map[string]string{"kes": "KES", "ksh": "KES", "kshs": "KES", "usd": "USD", "ugx": "UGX", "tzs": "TZS", "ngn": "NGN", "eur": "EUR", "gbp": "GBP"}
+)
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January, "feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March, "apr": time.April, "april": time.April, "may": time.May,
+	"jun": time.June, "june": time.June, "jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August, "sep": time.September, "sept": time.September,
+	"september": time.September, "oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November, "dec": time.December, "december": time.December,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday, "mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday, "wed": time.Wednesday,
+	"wednesday": time.Wednesday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"thursday": time.Thursday, "fri": time.Friday, "friday": time.Friday, "sat": time.Saturday,
+	"saturday": time.Saturday,
+}
+
+// accountHints maps words to the account they usually refer to.
+var accountHints = map[string]string{
+	"cash": "cash", "mpesa": "mpesa", "m-pesa": "mpesa", "airtel": "airtel money",
+	"bank": "bank", "card": "card", "debit": "card", "visa": "card", "mastercard": "card",
+	"credit": "credit card", "fuliza": "fuliza", "mshwari": "m-shwari", "m-shwari": "m-shwari",
+}
+
+var incomeWords = map[string]bool{
+	"salary": true, "income": true, "received": true, "receive": true, "got": true, "refund": true,
+	"bonus": true, "payday": true, "wages": true, "wage": true, "dividend": true, "dividends": true,
+	"interest": true, "commission": true, "allowance": true, "stipend": true, "sold": true,
+}
+
+// verbWords say what happened rather than what the money was for, so they
+// are never used as the category hint.
+var verbWords = map[string]bool{
+	"got": true, "received": true, "receive": true, "sold": true, "bought": true, "sent": true,
+}
+
+// fillerWords are dropped from the description once their meaning is used.
+var fillerWords = map[string]bool{
+	"on": true, "via": true, "with": true, "using": true, "by": true, "paid": true, "for": true,
+	"spent": true, "of": true, "the": true, "at": true, "in": true, "last": true, "ago": true,
+	"days": true, "day": true, "weeks": true, "week": true, "this": true, "from": true,
+}
+
+// Parse reads text relative to now, whose location is used for relative and
+// day-of-month dates. The draft's date is always midnight in that location,
+// and today when the text names no date. Unrecognized words become the
+// description.
+func Parse(text string, now time.Time) Draft {
+	p := parser{
+		words: strings.Fields(strings.ToLower(strings.TrimSpace(text))),
+		orig:  strings.Fields(strings.TrimSpace(text)),
+		now:   now,
+		used:  make(map[int]bool),
+	}
+	return p.parse()
+}
+
+type parser struct {
+	words []string
+	orig  []string
+	now   time.Time
+	used  map[int]bool
+}
+
+func (p *parser) parse() Draft {
+	draft := Draft{Type: db.TransactionTypeExpense}
+
+	date, ok := p.date()
+	if !ok {
+		date = p.today()
+	}
+	draft.Date = date
+
+	if amount, currency, ok := p.amount(); ok {
+		draft.Amount = amount
+		draft.Currency = currency
+	} else {
+		draft.Missing = append(draft.Missing, "amount")
+	}
+
+	for i, w := range p.words {
+		if p.used[i] {
+			continue
+		}
+		w = strings.Trim(w, ".,;:!?")
+		if hint, ok := accountHints[w]; ok && draft.AccountHint == "" {
+			draft.AccountHint = hint
+			p.used[i] = true
+			continue
+		}
+		if incomeWords[w] {
+			draft.Type = db.TransactionTypeIncome
+		}
+	}
+
+	var description []string
+	for i, w := range p.words {
+		if p.used[i] || fillerWords[strings.Trim(w, ".,;:!?")] {
+			continue
+		}
+		description = append(description, strings.Trim(p.orig[i], ".,;:!?"))
+	}
+	draft.Description = strings.Join(description, " ")
+	for _, w := range description {
+		if w = strings.ToLower(w); !verbWords[w] {
+			draft.CategoryHint = w
+			break
+		}
+	}
+	if len(description) == 0 {
+		draft.Missing = append(draft.Missing, "description")
+	}
+	return draft
+}
+
+// amount takes the first word that reads as an amount, e.g. 450, 85,000,
+// 1.5k, KES450 or 2,500/=.
+func (p *parser) amount() (money.Decimal, string, bool) {
+	for i, w := range p.words {
+		if p.used[i] {
+			continue
+		}
+		m := amountPattern.FindStringSubmatch(strings.Trim(w, ".,;:!?"))
+		if m == nil {
+			continue
+		}
+		text := strings.ReplaceAll(m[2], ",", "")
+		if m[3] != "" {
+			text += "." + m[3]
+		}
+		value, ok := new(big.Rat).SetString(text)
+		if !ok || value.Sign() <= 0 {
+			continue
+		}
+		switch m[4] {
+		case "k":
+			value.Mul(value, big.NewRat(1000, 1))
+		case "m":
+			value.Mul(value, big.NewRat(1000000, 1))
+		}
+		p.used[i] = true
+		currency := currencyAliases[m[1]]
+		// A currency written as its own word just before the amount.
+		if i > 0 && !p.used[i-1] && currency == "" {
+			if c, ok := currencyAliases[strings.Trim(p.words[i-1], ".")]; ok {
+				currency = c
+				p.used[i-1] = true
+			}
+		}
+		return money.Amount(value), currency, true
+	}
+	return money.Null, "", false
+}
+
+// date looks for the first date expression in the text.
+func (p *parser) date() (time.Time, bool) {
+	today := p.today()
+	for i := 0; i < len(p.words); i++ {
+		w := strings.Trim(p.words[i], ".,;:!?")
+		next := ""
+		if i+1 < len(p.words) {
+			next = strings.Trim(p.words[i+1], ".,;:!?")
+		}
+
+		switch w {
+		case "today", "tonight":
+			p.used[i] = true
+			return today, true
+		case "yesterday":
+			p.used[i] = true
+			return today.AddDate(0, 0, -1), true
+		case "tomorrow":
+			p.used[i] = true
+			return today.AddDate(0, 0, 1), true
+		case "last":
+			if wd, ok := weekdays[next]; ok {
+				p.used[i], p.used[i+1] = true, true
+				return previousWeekday(today, wd), true
+			}
+			if next == "week" {
+				p.used[i], p.used[i+1] = true, true
+				return today.AddDate(0, 0, -7), true
+			}
+		}
+
+		// "3 days ago", "two weeks ago"
+		if i+2 < len(p.words) && strings.Trim(p.words[i+2], ".,;:!?") == "ago" {
+			n, err := strconv.Atoi(w)
+			if err != nil {
+				n = agoNumberWords[w]
+			}
+			if n > 0 {
+				switch strings.TrimSuffix(next, "s") {
+				case "day":
+					p.used[i], p.used[i+1], p.used[i+2] = true, true, true
+					return today.AddDate(0, 0, -n), true
+				case "week":
+					p.used[i], p.used[i+1], p.used[i+2] = true, true, true
+					return today.AddDate(0, 0, -7*n), true
+				}
+			}
+		}
+
+		if wd, ok := weekdays[w]; ok {
+			p.used[i] = true
+			return previousWeekday(today, wd), true
+		}
+
+		if m := isoDatePattern.FindStringSubmatch(w); m != nil {
+			if t, err := time.ParseInLocation(time.DateOnly, w, p.now.Location()); err == nil {
+				p.used[i] = true
+				return t, true
+			}
+		}
+
+		if m := slashPattern.FindStringSubmatch(w); m != nil {
+			day, _ := strconv.Atoi(m[1])
+			month, _ := strconv.Atoi(m[2])
+			if t, ok := p.dayMonthYear(day, time.Month(month), m[3]); ok {
+				p.used[i] = true
+				return t, true
+			}
+		}
+
+		// "25th", "25th oct", "oct 25", "25 october"
+		day := 0
+		if m := ordinalPattern.FindStringSubmatch(w); m != nil {
+			day, _ = strconv.Atoi(m[1])
+		}
+		if month, ok := months[w]; ok {
+			if d, ok := dayNumber(next); ok {
+				if t, ok := p.dayMonthYear(d, month, ""); ok {
+					p.used[i], p.used[i+1] = true, true
+					return t, true
+				}
+			}
+		}
+		if d, ok := dayNumber(w); ok {
+			if month, ok := months[next]; ok {
+				if t, ok := p.dayMonthYear(d, month, ""); ok {
+					p.used[i], p.used[i+1] = true, true
+					return t, true
+				}
+			}
+		}
+		if day > 0 {
+			if t, ok := p.dayOfMonth(day); ok {
+				p.used[i] = true
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (p *parser) today() time.Time {
+	return time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+}
+
+// dayMonthYear builds a date, taking the most recent occurrence when the year
+// is omitted so that "25 dec" typed in January means last December.
+func (p *parser) dayMonthYear(day int, month time.Month, year string) (time.Time, bool) {
+	if month < time.January || month > time.December || day < 1 || day > 31 {
+		return time.Time{}, false
+	}
+	y := p.now.Year()
+	if year != "" {
+		y, _ = strconv.Atoi(year)
+		if y < 100 {
+			y += 2000
+		}
+	}
+	t := time.Date(y, month, day, 0, 0, 0, 0, p.now.Location())
+	if t.Day() != day {
+		return time.Time{}, false
+	}
+	if year == "" && t.After(p.now) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t, true
+}
+
+// dayOfMonth resolves a bare day of the month such as "on 25th" to this
+// month, or to last month if that day has not happened yet.
+func (p *parser) dayOfMonth(day int) (time.Time, bool) {
+	if day < 1 || day > 31 {
+		return time.Time{}, false
+	}
+	t := time.Date(p.now.Year(), p.now.Month(), day, 0, 0, 0, 0, p.now.Location())
+	if t.Day() != day || t.After(p.now) {
+		t = time.Date(p.now.Year(), p.now.Month()-1, day, 0, 0, 0, 0, p.now.Location())
+		if t.Day() != day {
+			return time.Time{}, false
+		}
+	}
+	return t, true
+}
+
+func dayNumber(w string) (int, bool) {
+	if m := ordinalPattern.FindStringSubmatch(w); m != nil {
+		w = m[1]
+	}
+	if len(w) > 2 {
+		return 0, false
+	}
+	d, err := strconv.Atoi(w)
+	return d, err == nil && d >= 1 && d <= 31
+}
+
+// previousWeekday returns the most recent wd strictly before today.
+func previousWeekday(today time.Time, wd time.Weekday) time.Time {
+	diff := int(today.Weekday() - wd)
+	if diff <= 0 {
+		diff += 7
+	}
+	return today.AddDate(0, 0, -diff)
+}
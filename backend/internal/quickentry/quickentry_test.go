@@ -0,0 +1,72 @@
+package quickentry
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nyunja/30budget/backend/internal/db"
+)
+
+func TestParse(t *testing.T) {
+	eat := time.FixedZone("EAT", 3*60*60)
+	// A Wednesday afternoon.
+	now := time.Date(2026, 10, 21, 15, 30, 0, 0, eat)
+
+	tests := []struct {
+		text        string
+		amount      string
+		currency    string
+		date        string
+		typ         db.TransactionType
+		description string
+		account     string
+		category    string
+		missing     []string
+	}{
+		{"lunch 450 yesterday cash", "450.00", "", "2026-10-20", db.TransactionTypeExpense, "lunch", "cash", "lunch", nil},
+		{"Salary 85,000 on 25th", "85000.00", "", "2026-09-25", db.TransactionTypeIncome, "Salary", "", "salary", nil},
+		{"coffee 300 today", "300.00", "", "2026-10-21", db.TransactionTypeExpense, "coffee", "", "coffee", nil},
+		{"fare 100 tonight", "100.00", "", "2026-10-21", db.TransactionTypeExpense, "fare", "", "fare", nil},
+		{"taxi 1.5k", "1500.00", "", "2026-10-21", db.TransactionTypeExpense, "taxi", "", "taxi", nil},
+		{"usd 20 netflix last friday via card", "20.00", "USD", "2026-10-16", db.TransactionTypeExpense, "netflix", "card", "netflix", nil},
+		{"groceries KES2,500/= 3 days ago mpesa", "2500.00", "KES", "2026-10-18", db.TransactionTypeExpense, "groceries", "mpesa", "groceries", nil},
+		{"rent 2026-10-01", "NULL", "", "2026-10-01", db.TransactionTypeExpense, "rent", "", "rent", []string{"amount"}},
+		{"25 dec gift 2000", "2000.00", "", "2025-12-25", db.TransactionTypeExpense, "gift", "", "gift", nil},
+		{"got 500 from john", "500.00", "", "2026-10-21", db.TransactionTypeIncome, "got john", "", "john", nil},
+		{"450", "450.00", "", "2026-10-21", db.TransactionTypeExpense, "", "", "", []string{"description"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			d := Parse(tt.text, now)
+			if got := d.Amount.String(); got != tt.amount {
+				t.Errorf("amount = %s, want %s", got, tt.amount)
+			}
+			if d.Currency != tt.currency {
+				t.Errorf("currency = %q, want %q", d.Currency, tt.currency)
+			}
+			want, err := time.ParseInLocation(time.DateOnly, tt.date, eat)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !d.Date.Equal(want) || d.Date.Location() != eat {
+				t.Errorf("date = %s, want midnight %s", d.Date, tt.date)
+			}
+			if d.Type != tt.typ {
+				t.Errorf("type = %s, want %s", d.Type, tt.typ)
+			}
+			if d.Description != tt.description {
+				t.Errorf("description = %q, want %q", d.Description, tt.description)
+			}
+			if d.AccountHint != tt.account {
+				t.Errorf("account hint = %q, want %q", d.AccountHint, tt.account)
+			}
+			if d.CategoryHint != tt.category {
+				t.Errorf("category hint = %q, want %q", d.CategoryHint, tt.category)
+			}
+			if !slices.Equal(d.Missing, tt.missing) {
+				t.Errorf("missing = %v, want %v", d.Missing, tt.missing)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package recurrence computes the dates of repeating schedules such as bills
+// and recurring transactions.
+package recurrence
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+)
+
+// Rule repeats every Interval units of Frequency from Start, up to and
+// including End when it is set. Dates are calendar days at midnight UTC.
+//
+// Monthly and yearly rules keep Start's day of the month, moving it back to
+// the last day of shorter months: a rule starting on 31 January falls on
+// 28 (or 29) February and 31 March.
+type Rule struct {
+	Frequency db.RecurrenceFrequency
+	Interval  int
+	Start     time.Time
+	End       time.Time
+}
+
+// New builds a rule from stored columns.
+func New(frequency db.RecurrenceFrequency, interval int32, start, end pgtype.Date) Rule {
+	r := Rule{Frequency: frequency, Interval: int(interval), Start: Day(start.Time)}
+	if end.Valid {
+		r.End = Day(end.Time)
+	}
+	return r
+}
+
+// Validate checks that the rule describes a schedule.
+func (r Rule) Validate() error {
+	switch r.Frequency {
+	case db.RecurrenceFrequencyOnce, db.RecurrenceFrequencyDaily, db.RecurrenceFrequencyWeekly,
+		db.RecurrenceFrequencyMonthly, db.RecurrenceFrequencyYearly:
+	default:
+		return errors.New("frequency must be once, daily, weekly, monthly or yearly")
+	}
+	if r.Interval < 1 {
+		return errors.New("interval must be at least 1")
+	}
+	if r.Start.IsZero() {
+		return errors.New("start date is required")
+	}
+	if !r.End.IsZero() && r.End.Before(r.Start) {
+		return errors.New("end date must not be before the start date")
+	}
+	return nil
+}
+
+// Occurrence returns the nth date of the schedule, counting Start as 0,
+// ignoring End.
+func (r Rule) Occurrence(n int) time.Time {
+	switch r.Frequency {
+	case db.RecurrenceFrequencyDaily:
+		return r.Start.AddDate(0, 0, n*r.Interval)
+	case db.RecurrenceFrequencyWeekly:
+		return r.Start.AddDate(0, 0, 7*n*r.Interval)
+	case db.RecurrenceFrequencyMonthly:
+		return addMonths(r.Start, n*r.Interval)
+	case db.RecurrenceFrequencyYearly:
+		return addMonths(r.Start, 12*n*r.Interval)
+	}
+	return r.Start
+}
+
+// Next returns the first occurrence strictly after t. ok is false when the
+// schedule has no more occurrences.
+func (r Rule) Next(t time.Time) (next time.Time, ok bool) {
+	return r.from(Day(t).AddDate(0, 0, 1))
+}
+
+// OnOrAfter returns the first occurrence on or after t.
+func (r Rule) OnOrAfter(t time.Time) (time.Time, bool) {
+	return r.from(Day(t))
+}
+
+// Between returns the occurrences from from to to, inclusive.
+func (r Rule) Between(from, to time.Time) []time.Time {
+	var dates []time.Time
+	to = Day(to)
+	for d, ok := r.OnOrAfter(from); ok && !d.After(to); d, ok = r.Next(d) {
+		dates = append(dates, d)
+	}
+	return dates
+}
+
+func (r Rule) from(t time.Time) (time.Time, bool) {
+	if r.Frequency == db.RecurrenceFrequencyOnce {
+		return r.Start, !r.Start.Before(t) && r.within(r.Start)
+	}
+	for n := r.estimate(t); ; n++ {
+		d := r.Occurrence(n)
+		if !d.Before(t) {
+			return d, r.within(d)
+		}
+	}
+}
+
+// estimate is an occurrence index at or before the first one on or after t,
+// so from does not have to walk the whole schedule.
+func (r Rule) estimate(t time.Time) int {
+	var elapsed, step int
+	switch r.Frequency {
+	case db.RecurrenceFrequencyDaily:
+		elapsed, step = int(t.Sub(r.Start).Hours()/24), r.Interval
+	case db.RecurrenceFrequencyWeekly:
+		elapsed, step = int(t.Sub(r.Start).Hours()/24), 7*r.Interval
+	case db.RecurrenceFrequencyMonthly:
+		elapsed, step = monthsBetween(r.Start, t), r.Interval
+	case db.RecurrenceFrequencyYearly:
+		elapsed, step = monthsBetween(r.Start, t), 12*r.Interval
+	default:
+		return 0
+	}
+	return max(elapsed/step-1, 0)
+}
+
+func (r Rule) within(d time.Time) bool {
+	return r.End.IsZero() || !d.After(r.End)
+}
+
+// Day truncates t to its calendar date at midnight UTC.
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Date converts a calendar date to a DATE value.
+func Date(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: Day(t), Valid: true}
+}
+
+// addMonths adds months to t, clamping the day to the end of shorter months.
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
+
+func monthsBetween(a, b time.Time) int {
+	return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+}
@@ -0,0 +1,185 @@
+package recurrence
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nyunja/30budget/backend/internal/db"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func days(ts []time.Time) []string {
+	out := make([]string, len(ts))
+	for i, t := range ts {
+		out[i] = t.Format(time.DateOnly)
+	}
+	return out
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		from   string
+		months int
+		want   string
+	}{
+		{"2026-01-15", 1, "2026-02-15"},
+		{"2026-01-31", 1, "2026-02-28"},
+		{"2028-01-31", 1, "2028-02-29"},
+		{"2026-01-31", 2, "2026-03-31"},
+		{"2026-01-31", 3, "2026-04-30"},
+		{"2026-03-31", -1, "2026-02-28"},
+		{"2026-12-31", 2, "2027-02-28"},
+		{"2028-02-29", 12, "2029-02-28"},
+		{"2028-02-29", 48, "2032-02-29"},
+		{"2026-05-30", 9, "2027-02-28"},
+	}
+	for _, tt := range tests {
+		if got := addMonths(day(tt.from), tt.months).Format(time.DateOnly); got != tt.want {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", tt.from, tt.months, got, tt.want)
+		}
+	}
+}
+
+func TestOccurrence(t *testing.T) {
+	// Clamping does not drift: each occurrence is counted from Start, so
+	// the 31st comes back after February.
+	monthly := Rule{Frequency: db.RecurrenceFrequencyMonthly, Interval: 1, Start: day("2026-01-31")}
+	var got []time.Time
+	for n := range 4 {
+		got = append(got, monthly.Occurrence(n))
+	}
+	if want := []string{"2026-01-31", "2026-02-28", "2026-03-31", "2026-04-30"}; !slices.Equal(days(got), want) {
+		t.Errorf("monthly occurrences = %v, want %v", days(got), want)
+	}
+
+	tests := []struct {
+		rule Rule
+		n    int
+		want string
+	}{
+		{Rule{Frequency: db.RecurrenceFrequencyDaily, Interval: 3, Start: day("2026-02-27")}, 1, "2026-03-02"},
+		{Rule{Frequency: db.RecurrenceFrequencyWeekly, Interval: 2, Start: day("2026-12-25")}, 1, "2027-01-08"},
+		{Rule{Frequency: db.RecurrenceFrequencyYearly, Interval: 1, Start: day("2028-02-29")}, 1, "2029-02-28"},
+		{Rule{Frequency: db.RecurrenceFrequencyYearly, Interval: 1, Start: day("2028-02-29")}, 4, "2032-02-29"},
+		{Rule{Frequency: db.RecurrenceFrequencyOnce, Interval: 1, Start: day("2026-06-01")}, 5, "2026-06-01"},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Occurrence(tt.n).Format(time.DateOnly); got != tt.want {
+			t.Errorf("%s every %d from %s: occurrence %d = %s, want %s", tt.rule.Frequency, tt.rule.Interval, tt.rule.Start.Format(time.DateOnly), tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestNextAndOnOrAfter(t *testing.T) {
+	monthly := Rule{Frequency: db.RecurrenceFrequencyMonthly, Interval: 1, Start: day("2026-01-31"), End: day("2026-05-31")}
+	once := Rule{Frequency: db.RecurrenceFrequencyOnce, Interval: 1, Start: day("2026-03-10")}
+	tests := []struct {
+		name   string
+		rule   Rule
+		t      time.Time
+		next   string // "" for none
+		onOrAf string
+	}{
+		{"before the start", monthly, day("2025-12-01"), "2026-01-31", "2026-01-31"},
+		{"on an occurrence", monthly, day("2026-02-28"), "2026-03-31", "2026-02-28"},
+		{"between occurrences", monthly, day("2026-03-01"), "2026-03-31", "2026-03-31"},
+		{"time of day is ignored", monthly, time.Date(2026, 2, 28, 23, 59, 0, 0, time.UTC), "2026-03-31", "2026-02-28"},
+		{"last occurrence", monthly, day("2026-05-31"), "", "2026-05-31"},
+		{"after the end", monthly, day("2026-06-01"), "", ""},
+		{"once, before", once, day("2026-03-09"), "2026-03-10", "2026-03-10"},
+		{"once, on the day", once, day("2026-03-10"), "", "2026-03-10"},
+		{"once, after", once, day("2026-03-11"), "", ""},
+		{"years after the start", Rule{Frequency: db.RecurrenceFrequencyWeekly, Interval: 2, Start: day("2000-01-03")}, day("2026-10-19"), "2026-11-02", "2026-10-19"},
+		{"decades of daily occurrences", Rule{Frequency: db.RecurrenceFrequencyDaily, Interval: 1, Start: day("1990-01-01")}, day("2026-10-19"), "2026-10-20", "2026-10-19"},
+	}
+	show := func(d time.Time, ok bool) string {
+		if !ok {
+			return ""
+		}
+		return d.Format(time.DateOnly)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := show(tt.rule.Next(tt.t)); got != tt.next {
+				t.Errorf("Next = %q, want %q", got, tt.next)
+			}
+			if got := show(tt.rule.OnOrAfter(tt.t)); got != tt.onOrAf {
+				t.Errorf("OnOrAfter = %q, want %q", got, tt.onOrAf)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     Rule
+		from, to string
+		want     []string
+	}{
+		{
+			"monthly from the 31st",
+			Rule{Frequency: db.RecurrenceFrequencyMonthly, Interval: 1, Start: day("2026-01-31")},
+			"2026-01-01", "2026-06-30",
+			[]string{"2026-01-31", "2026-02-28", "2026-03-31", "2026-04-30", "2026-05-31", "2026-06-30"},
+		},
+		{
+			"bounds are inclusive",
+			Rule{Frequency: db.RecurrenceFrequencyWeekly, Interval: 1, Start: day("2026-03-02")},
+			"2026-03-09", "2026-03-23",
+			[]string{"2026-03-09", "2026-03-16", "2026-03-23"},
+		},
+		{
+			"stops at the end date",
+			Rule{Frequency: db.RecurrenceFrequencyDaily, Interval: 2, Start: day("2026-03-01"), End: day("2026-03-06")},
+			"2026-02-01", "2026-04-01",
+			[]string{"2026-03-01", "2026-03-03", "2026-03-05"},
+		},
+		{
+			"nothing in range",
+			Rule{Frequency: db.RecurrenceFrequencyYearly, Interval: 1, Start: day("2026-07-01")},
+			"2026-08-01", "2027-06-30",
+			nil,
+		},
+		{
+			"once",
+			Rule{Frequency: db.RecurrenceFrequencyOnce, Interval: 1, Start: day("2026-03-10")},
+			"2026-03-01", "2026-03-31",
+			[]string{"2026-03-10"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := days(tt.rule.Between(day(tt.from), day(tt.to)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Between = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Rule{Frequency: db.RecurrenceFrequencyMonthly, Interval: 1, Start: day("2026-01-01")}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid rule: %v", err)
+	}
+	invalid := map[string]Rule{
+		"unknown frequency": {Frequency: "hourly", Interval: 1, Start: day("2026-01-01")},
+		"zero interval":     {Frequency: db.RecurrenceFrequencyMonthly, Start: day("2026-01-01")},
+		"no start":          {Frequency: db.RecurrenceFrequencyMonthly, Interval: 1},
+		"end before start":  {Frequency: db.RecurrenceFrequencyMonthly, Interval: 1, Start: day("2026-01-01"), End: day("2025-12-31")},
+	}
+	for name, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("%s: Validate = nil, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Africa's Talking API endpoints. The sandbox is used when the username is
+// "sandbox", matching how their dashboard issues sandbox credentials.
+const (
+	DefaultAfricasTalkingURL = "https://api.africastalking.com/version1"
+	AfricasTalkingSandboxURL = "https://api.sandbox.africastalking.com/version1"
+)
+
+// AfricasTalkingOptions configures the Africa's Talking sender. SenderID,
+// BaseURL and Client are optional.
+type AfricasTalkingOptions struct {
+	Username string
+	APIKey   string
+	SenderID string
+	BaseURL  string
+	Client   *http.Client
+}
+
+// AfricasTalking sends messages through Africa's Talking's SMS API.
+type AfricasTalking struct {
+	opts   AfricasTalkingOptions
+	client *http.Client
+}
+
+// NewAfricasTalking validates opts and returns an Africa's Talking sender.
+func NewAfricasTalking(opts AfricasTalkingOptions) (*AfricasTalking, error) {
+	if opts.Username == "" || opts.APIKey == "" {
+		return nil, errors.New("AFRICASTALKING_USERNAME and AFRICASTALKING_API_KEY are required for africastalking sms")
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultAfricasTalkingURL
+		if opts.Username == "sandbox" {
+			opts.BaseURL = AfricasTalkingSandboxURL
+		}
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &AfricasTalking{opts: opts, client: client}, nil
+}
+
+type africasTalkingResponse struct {
+	SMSMessageData struct {
+		Message    string `json:"Message"`
+		Recipients []struct {
+			Number string `json:"number"`
+			Status string `json:"status"`
+		} `json:"Recipients"`
+	} `json:"SMSMessageData"`
+}
+
+func (a *AfricasTalking) Send(ctx context.Context, to, body string) error {
+	if !ValidNumber(to) {
+		return errInvalidNumber
+	}
+	form := url.Values{
+		"username": {a.opts.Username},
+		"to":       {to},
+		"message":  {truncate(body)},
+	}
+	if a.opts.SenderID != "" {
+		form.Set("from", a.opts.SenderID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.opts.BaseURL+"/messaging", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build africastalking request: %w", err)
+	}
+	req.Header.Set("apiKey", a.opts.APIKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("africastalking request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("africastalking: %s: %s", resp.Status, strings.TrimSpace(string(raw)))
+	}
+	// A 201 can still carry a per-recipient failure.
+	var result africasTalkingResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return fmt.Errorf("africastalking: unexpected response: %w", err)
+	}
+	for _, r := range result.SMSMessageData.Recipients {
+		if r.Status != "Success" {
+			return fmt.Errorf("africastalking: delivery to %s failed: %s", r.Number, r.Status)
+		}
+	}
+	if len(result.SMSMessageData.Recipients) == 0 {
+		return fmt.Errorf("africastalking: message not accepted: %s", result.SMSMessageData.Message)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package sms
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// mockHistory is how many of the latest messages Mock keeps.
+const mockHistory = 100
+
+// SentMessage is a message recorded by Mock.
+type SentMessage struct {
+	To   string
+	Body string
+}
+
+// Mock records messages instead of sending them. It is the default provider
+// so development never sends real texts. Only the latest mockHistory
+// messages are kept, in a ring, so a long-running server does not grow.
+type Mock struct {
+	mu     sync.Mutex
+	sent   [mockHistory]SentMessage
+	next   int // index the next message is written to
+	count  int
+	logger *zap.Logger
+}
+
+// NewMock returns a Mock that logs each message.
+func NewMock(logger *zap.Logger) *Mock {
+	return &Mock{logger: logger}
+}
+
+func (m *Mock) Send(ctx context.Context, to, body string) error {
+	if !ValidNumber(to) {
+		return errInvalidNumber
+	}
+	body = truncate(body)
+	m.mu.Lock()
+	m.sent[m.next] = SentMessage{To: to, Body: body}
+	m.next = (m.next + 1) % mockHistory
+	m.count = min(m.count+1, mockHistory)
+	m.mu.Unlock()
+	m.logger.Info("SMS not sent (mock provider)", zap.String("to", to), zap.String("body", body))
+	return nil
+}
+
+// Sent returns the latest messages recorded, oldest first.
+func (m *Mock) Sent() []SentMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sent := make([]SentMessage, 0, m.count)
+	start := (m.next - m.count + mockHistory) % mockHistory
+	for i := range m.count {
+		sent = append(sent, m.sent[(start+i)%mockHistory])
+	}
+	return sent
+}
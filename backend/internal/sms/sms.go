@@ -0,0 +1,62 @@
+// Package sms sends text messages through a configurable provider, selected
+// by SMS_PROVIDER: Twilio, Africa's Talking, or a mock that only logs.
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/nyunja/30budget/backend/internal/config"
+	"go.uber.org/zap"
+)
+
+// MaxLength caps outgoing messages at two concatenated SMS segments.
+const MaxLength = 306
+
+// e164Pattern matches phone numbers in E.164 format, e.g. +254712345678.
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+var errInvalidNumber = errors.New("phone number must be in E.164 format")
+
+// ValidNumber reports whether number is in E.164 format.
+func ValidNumber(number string) bool {
+	return e164Pattern.MatchString(number)
+}
+
+// Sender delivers a text message to a phone number in E.164 format.
+type Sender interface {
+	Send(ctx context.Context, to, body string) error
+}
+
+// New returns the sender configured in cfg.
+func New(cfg config.SMSConfig, logger *zap.Logger) (Sender, error) {
+	switch cfg.Provider {
+	case "twilio":
+		return NewTwilio(TwilioOptions{
+			AccountSID: cfg.TwilioAccountSID,
+			AuthToken:  cfg.TwilioAuthToken,
+			From:       cfg.TwilioFromNumber,
+		})
+	case "africastalking":
+		return NewAfricasTalking(AfricasTalkingOptions{
+			Username: cfg.AfricasTalkingUsername,
+			APIKey:   cfg.AfricasTalkingAPIKey,
+			SenderID: cfg.AfricasTalkingSenderID,
+		})
+	case "mock", "":
+		return NewMock(logger), nil
+	default:
+		return nil, fmt.Errorf("unknown sms provider %q", cfg.Provider)
+	}
+}
+
+// truncate shortens body to MaxLength runes.
+func truncate(body string) string {
+	runes := []rune(body)
+	if len(runes) <= MaxLength {
+		return body
+	}
+	return string(runes[:MaxLength-1]) + "…"
+}
@@ -0,0 +1,84 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTwilioURL is Twilio's REST API.
+const DefaultTwilioURL = "https://api.twilio.com/2010-04-01"
+
+// TwilioOptions configures the Twilio sender. BaseURL and Client are optional.
+type TwilioOptions struct {
+	AccountSID string
+	AuthToken  string
+	From       string
+	BaseURL    string
+	Client     *http.Client
+}
+
+// Twilio sends messages through Twilio's Messages API.
+type Twilio struct {
+	opts   TwilioOptions
+	client *http.Client
+}
+
+// NewTwilio validates opts and returns a Twilio sender.
+func NewTwilio(opts TwilioOptions) (*Twilio, error) {
+	if opts.AccountSID == "" || opts.AuthToken == "" || opts.From == "" {
+		return nil, errors.New("TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM_NUMBER are required for twilio sms")
+	}
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultTwilioURL
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Twilio{opts: opts, client: client}, nil
+}
+
+func (t *Twilio) Send(ctx context.Context, to, body string) error {
+	if !ValidNumber(to) {
+		return errInvalidNumber
+	}
+	form := url.Values{
+		"To":   {to},
+		"From": {t.opts.From},
+		"Body": {truncate(body)},
+	}
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", t.opts.BaseURL, url.PathEscape(t.opts.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build twilio request: %w", err)
+	}
+	req.SetBasicAuth(t.opts.AccountSID, t.opts.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("twilio request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(raw, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("twilio: %s: %d %s", resp.Status, apiErr.Code, apiErr.Message)
+		}
+		return fmt.Errorf("twilio: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalPathStaysInRoot(t *testing.T) {
+	root := t.TempDir()
+	l := NewLocal(root)
+
+	for _, key := range []string{"", "/", "..", "../etc/passwd", "a/../../b", "a/..", "a/../b", `..\evil`} {
+		if p, err := l.path(key); err == nil {
+			t.Errorf("path(%q) = %s, want error", key, p)
+		}
+	}
+
+	for key, want := range map[string]string{
+		"attachments/u1/receipt.pdf": "attachments/u1/receipt.pdf",
+		"/leading/slash.txt":         "leading/slash.txt",
+		"a//b/./c.txt":               "a/b/c.txt",
+	} {
+		p, err := l.path(key)
+		if err != nil {
+			t.Errorf("path(%q): %v", key, err)
+			continue
+		}
+		if p != filepath.Join(root, filepath.FromSlash(want)) {
+			t.Errorf("path(%q) = %s, want %s under %s", key, p, want, root)
+		}
+	}
+}
+
+func TestLocalPutGetDelete(t *testing.T) {
+	l := NewLocal(t.TempDir())
+	ctx := context.Background()
+	if err := l.Put(ctx, "a/b.txt", strings.NewReader("hello world"), 5, "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := l.Get(ctx, "a/b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(got) != "hello" {
+		t.Errorf("Get = %q, want the first size bytes", got)
+	}
+	if err := l.Delete(ctx, "a/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Get(ctx, "a/b.txt"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := l.Delete(ctx, "a/b.txt"); err != nil {
+		t.Errorf("Delete of missing object = %v", err)
+	}
+}
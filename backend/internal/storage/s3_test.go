@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+var testNow = time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC)
+
+// verifySignature recomputes the SigV4 signature of r as S3 would on receipt
+// and returns an error describing any mismatch.
+func verifySignature(r *http.Request, body []byte) error {
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	if got := r.Header.Get("X-Amz-Content-Sha256"); got != payloadHash {
+		return fmt.Errorf("x-amz-content-sha256 = %s, want %s", got, payloadHash)
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	if amzDate != "20261019T123000Z" {
+		return fmt.Errorf("x-amz-date = %s", amzDate)
+	}
+
+	canonical := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		"",
+		"host:" + r.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		"host;x-amz-content-sha256;x-amz-date",
+		payloadHash,
+	}, "\n")
+	canonicalSum := sha256.Sum256([]byte(canonical))
+	scope := "20261019/eu-west-1/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalSum[:])
+
+	key := []byte("AWS4" + testSecretKey)
+	for _, part := range []string{"20261019", "eu-west-1", "s3", "aws4_request", stringToSign} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	want := "AWS4-HMAC-SHA256 Credential=" + testAccessKey + "/" + scope +
+		", SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=" + hex.EncodeToString(key)
+	if got := r.Header.Get("Authorization"); got != want {
+		return fmt.Errorf("Authorization = %s\nwant %s", got, want)
+	}
+	return nil
+}
+
+func newTestS3(t *testing.T, handler http.HandlerFunc) *S3 {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	s, err := NewS3(S3Options{
+		Endpoint:  srv.URL + "/",
+		Region:    "eu-west-1",
+		Bucket:    "receipts",
+		AccessKey: testAccessKey,
+		SecretKey: testSecretKey,
+		Client:    srv.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return testNow }
+	return s
+}
+
+func TestS3PutGetDelete(t *testing.T) {
+	objects := make(map[string][]byte)
+	s := newTestS3(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := verifySignature(r, body); err != nil {
+			t.Error(err)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		path := r.URL.EscapedPath()
+		switch r.Method {
+		case http.MethodPut:
+			if ct := r.Header.Get("Content-Type"); ct != "application/pdf" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			objects[path] = body
+		case http.MethodGet:
+			b, ok := objects[path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(b)
+		case http.MethodDelete:
+			delete(objects, path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	ctx := context.Background()
+	key := "attachments/u1/a b+c(1)é.pdf"
+	if err := s.Put(ctx, key, strings.NewReader("%PDF-1.7 receipt"), 16, "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+	const wantPath = "/receipts/attachments/u1/a%20b%2Bc%281%29%C3%A9.pdf"
+	if _, ok := objects[wantPath]; !ok {
+		t.Fatalf("object stored under %v, want %s", objects, wantPath)
+	}
+
+	rc, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(got) != "%PDF-1.7 receipt" {
+		t.Errorf("Get = %q", got)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestS3Errors(t *testing.T) {
+	status := http.StatusNotFound
+	s := newTestS3(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		if status != http.StatusNotFound {
+			fmt.Fprint(w, "<Error><Code>AccessDenied</Code></Error>")
+		}
+	})
+	ctx := context.Background()
+
+	if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of missing object = %v, want ErrNotFound", err)
+	}
+	// Deleting what is already gone succeeds.
+	if err := s.Delete(ctx, "missing"); err != nil {
+		t.Errorf("Delete of missing object = %v", err)
+	}
+
+	status = http.StatusForbidden
+	check := func(op string, err error) {
+		t.Helper()
+		if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "AccessDenied") {
+			t.Errorf("%s on 403 = %v", op, err)
+		}
+	}
+	check("Put", s.Put(ctx, "k", strings.NewReader("x"), 1, ""))
+	_, err := s.Get(ctx, "k")
+	check("Get", err)
+	check("Delete", s.Delete(ctx, "k"))
+}
+
+func TestURIEncodePath(t *testing.T) {
+	tests := map[string]string{
+		"/bucket/a/b.pdf":   "/bucket/a/b.pdf",
+		"/a b":              "/a%20b",
+		"/a+b=c&d":          "/a%2Bb%3Dc%26d",
+		"/~user/-_.":        "/~user/-_.",
+		"/é":                "/%C3%A9",
+		"/100%":             "/100%25",
+		"/q?x#y":            "/q%3Fx%23y",
+		"/parens(1)*'!":     "/parens%281%29%2A%27%21",
+		"/colon:semi;at@$,": "/colon%3Asemi%3Bat%40%24%2C",
+	}
+	for in, want := range tests {
+		if got := uriEncodePath(in); got != want {
+			t.Errorf("uriEncodePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Package storage stores uploaded files such as receipts. Files live either on
+// the local disk or in an S3-compatible bucket, selected by STORAGE_PROVIDER.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nyunja/30budget/backend/internal/config"
+)
+
+// ErrNotFound is returned when an object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// Storage is a flat key/value store for file contents.
+type Storage interface {
+	// Put stores size bytes read from body under key, replacing any existing object.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the storage backend configured in cfg.
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Provider {
+	case "local", "":
+		return NewLocal(cfg.LocalPath), nil
+	case "s3":
+		return NewS3(S3Options{
+			Endpoint:  cfg.AWSEndpoint,
+			Region:    cfg.AWSRegion,
+			Bucket:    cfg.AWSBucket,
+			AccessKey: cfg.AWSAccessKey,
+			SecretKey: cfg.AWSSecretKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,155 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nyunja/30budget/backend/internal/config"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/money"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+	"github.com/nyunja/30budget/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// historyYears is how far back charges are scanned, enough for a yearly
+// subscription to have been charged twice.
+const historyYears = 2
+
+// Job periodically rescans every user's expenses for subscriptions.
+type Job struct {
+	dbPool  *pgxpool.Pool
+	queries *db.Queries
+	config  *config.Config
+	logger  *zap.Logger
+}
+
+func NewJob(dbPool *pgxpool.Pool, cfg *config.Config, logger *zap.Logger) *Job {
+	return &Job{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+		config:  cfg,
+		logger:  logger,
+	}
+}
+
+// Run scans every interval until ctx is cancelled.
+func (j *Job) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.scanAll(ctx)
+		}
+	}
+}
+
+func (j *Job) scanAll(ctx context.Context) {
+	now := time.Now()
+	userIDs, err := j.queries.ListSubscriptionScanUsers(ctx, pgtype.Timestamptz{Time: now.AddDate(-historyYears, 0, 0), Valid: true})
+	if err != nil {
+		j.logger.Error("Failed to list users to scan for subscriptions", zap.Error(err))
+		return
+	}
+	for _, userID := range userIDs {
+		err := pgx.BeginFunc(ctx, j.dbPool, func(tx pgx.Tx) error {
+			return Scan(ctx, j.queries.WithTx(tx), userID, now)
+		})
+		if err != nil {
+			j.logger.Error("Failed to scan for subscriptions", zap.String("user_id", userID.String()), zap.Error(err))
+		}
+	}
+}
+
+// Scan detects the subscriptions in a user's expenses at now. New ones are
+// proposed as pending, pending ones are refreshed or, when no longer seen,
+// withdrawn, and confirmed or dismissed ones are left alone. Payees the user
+// already has a recurring expense for are skipped.
+func Scan(ctx context.Context, q *db.Queries, userID pgtype.UUID, now time.Time) error {
+	user, err := q.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	expenses, err := q.ListPayeeExpenses(ctx, db.ListPayeeExpensesParams{
+		UserID: userID,
+		Since:  pgtype.Timestamptz{Time: now.AddDate(-historyYears, 0, 0), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list payee expenses: %w", err)
+	}
+	recurring, err := q.ListRecurringTransactionsByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list recurring transactions: %w", err)
+	}
+	scheduled := make(map[pgtype.UUID]bool)
+	for _, rt := range recurring {
+		if rt.Type == db.TransactionTypeExpense && rt.PayeeID.Valid {
+			scheduled[rt.PayeeID] = true
+		}
+	}
+
+	// Expenses arrive grouped by payee, in date order.
+	byPayee := make(map[pgtype.UUID][]Charge)
+	var payees []pgtype.UUID
+	for _, e := range expenses {
+		if _, ok := byPayee[e.PayeeID]; !ok {
+			payees = append(payees, e.PayeeID)
+		}
+		byPayee[e.PayeeID] = append(byPayee[e.PayeeID], Charge{
+			Date:       recurrence.Day(e.Date.Time.In(loc)),
+			Amount:     e.Amount.Rat(),
+			CategoryID: e.CategoryID,
+			AccountID:  e.AccountID,
+		})
+	}
+
+	today := now.In(loc)
+	payeeIDs := []pgtype.UUID{}
+	frequencies := []string{}
+	for _, payeeID := range payees {
+		if scheduled[payeeID] {
+			continue
+		}
+		d, ok := Detect(byPayee[payeeID], today)
+		if !ok {
+			continue
+		}
+		if err := q.UpsertDetectedSubscription(ctx, db.UpsertDetectedSubscriptionParams{
+			ID:               utils.NewUUID(),
+			UserID:           userID,
+			PayeeID:          payeeID,
+			Frequency:        d.Frequency,
+			Amount:           money.Amount(d.Latest.Amount),
+			CategoryID:       d.Latest.CategoryID,
+			AccountID:        d.Latest.AccountID,
+			ChargeCount:      int32(d.Count),
+			LastChargedOn:    recurrence.Date(d.Latest.Date),
+			NextExpectedDate: recurrence.Date(d.Next),
+			AnnualCost:       money.Amount(d.AnnualCost),
+		}); err != nil {
+			return fmt.Errorf("failed to save detected subscription: %w", err)
+		}
+		payeeIDs = append(payeeIDs, payeeID)
+		frequencies = append(frequencies, string(d.Frequency))
+	}
+
+	if err := q.DeleteStaleDetectedSubscriptions(ctx, db.DeleteStaleDetectedSubscriptionsParams{
+		UserID:      userID,
+		PayeeIds:    payeeIDs,
+		Frequencies: frequencies,
+	}); err != nil {
+		return fmt.Errorf("failed to withdraw stale subscriptions: %w", err)
+	}
+	return nil
+}
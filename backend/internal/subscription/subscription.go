@@ -0,0 +1,123 @@
+// Package subscription spots charges that repeat on a schedule, such as
+// streaming services and gym memberships, in a user's expenses.
+package subscription
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nyunja/30budget/backend/internal/db"
+	"github.com/nyunja/30budget/backend/internal/recurrence"
+)
+
+// amountTolerance is how far, as a fraction of the latest charge, another
+// charge's amount may be to count as the same subscription.
+var amountTolerance = big.NewRat(1, 10)
+
+// pattern is a schedule charges may repeat on.
+type pattern struct {
+	frequency db.RecurrenceFrequency
+	// minGap and maxGap bound the days between consecutive charges.
+	minGap, maxGap int
+	// minCharges is how many charges establish the pattern.
+	minCharges int
+	perYear    int64
+}
+
+// patterns are tried from the shortest period, so that a weekly charge is
+// not mistaken for a monthly one.
+var patterns = []pattern{
+	{db.RecurrenceFrequencyWeekly, 6, 8, 4, 52},
+	{db.RecurrenceFrequencyMonthly, 27, 34, 3, 12},
+	{db.RecurrenceFrequencyYearly, 358, 372, 2, 1},
+}
+
+// Charge is one expense to a payee, dated by calendar day.
+type Charge struct {
+	Date       time.Time
+	Amount     *big.Rat
+	CategoryID pgtype.UUID
+	AccountID  pgtype.UUID
+}
+
+// Detected is a subscription found in a payee's charges.
+type Detected struct {
+	Frequency db.RecurrenceFrequency
+	// Latest is the most recent charge, whose amount, category and account
+	// the subscription takes.
+	Latest Charge
+	// Count is how many charges in a row followed the schedule.
+	Count      int
+	Next       time.Time
+	AnnualCost *big.Rat
+}
+
+// Detect looks for a schedule that a payee's charges, in date order, have
+// kept to up to their latest one, and that is still current at today.
+//
+// Working back from the latest charge, each earlier charge of about the same
+// amount one period before extends the run. The run must be long enough for
+// the pattern and must not have other charges of that amount in between, so
+// that frequent purchases at one price are not taken for a subscription.
+func Detect(charges []Charge, today time.Time) (Detected, bool) {
+	if len(charges) == 0 {
+		return Detected{}, false
+	}
+	today = recurrence.Day(today)
+	latest := charges[len(charges)-1]
+	for _, p := range patterns {
+		run := []Charge{latest}
+		for i := len(charges) - 2; i >= 0; i-- {
+			c := charges[i]
+			gap := days(c.Date, run[len(run)-1].Date)
+			if gap < p.minGap {
+				continue
+			}
+			if gap > p.maxGap {
+				break
+			}
+			if similar(c.Amount, latest.Amount) {
+				run = append(run, c)
+			}
+		}
+		if len(run) < p.minCharges || days(latest.Date, today) > 2*p.maxGap {
+			continue
+		}
+		first := run[len(run)-1].Date
+		similarCharges := 0
+		for _, c := range charges {
+			if !c.Date.Before(first) && similar(c.Amount, latest.Amount) {
+				similarCharges++
+			}
+		}
+		if similarCharges > len(run) {
+			continue
+		}
+
+		rule := recurrence.Rule{Frequency: p.frequency, Interval: 1, Start: recurrence.Day(latest.Date)}
+		next, _ := rule.Next(latest.Date)
+		for next.Before(today) {
+			next, _ = rule.Next(next)
+		}
+		return Detected{
+			Frequency:  p.frequency,
+			Latest:     latest,
+			Count:      len(run),
+			Next:       next,
+			AnnualCost: new(big.Rat).Mul(latest.Amount, big.NewRat(p.perYear, 1)),
+		}, true
+	}
+	return Detected{}, false
+}
+
+// similar reports whether amount is within amountTolerance of ref.
+func similar(amount, ref *big.Rat) bool {
+	diff := new(big.Rat).Sub(amount, ref)
+	diff.Abs(diff)
+	return diff.Cmp(new(big.Rat).Mul(ref, amountTolerance)) <= 0
+}
+
+func days(from, to time.Time) int {
+	return int(recurrence.Day(to).Sub(recurrence.Day(from)).Hours() / 24)
+}
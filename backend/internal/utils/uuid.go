@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"crypto/rand"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// NewUUID returns a random (version 4) UUID ready to be used as a primary key.
+func NewUUID() pgtype.UUID {
+	var id pgtype.UUID
+	if _, err := rand.Read(id.Bytes[:]); err != nil {
+		panic(err)
+	}
+	id.Bytes[6] = (id.Bytes[6] & 0x0f) | 0x40
+	id.Bytes[8] = (id.Bytes[8] & 0x3f) | 0x80
+	id.Valid = true
+	return id
+}
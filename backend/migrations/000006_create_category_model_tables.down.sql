@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transactions_user_date;
+DROP TABLE IF EXISTS category_model_docs;
+DROP TABLE IF EXISTS category_model_tokens;
@@ -0,0 +1,20 @@
+-- Per-user naive Bayes model used to suggest categories for new transactions.
+CREATE TABLE category_model_tokens (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    token VARCHAR(64) NOT NULL,
+    count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, category_id, token)
+);
+
+CREATE TABLE category_model_docs (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    doc_count INTEGER NOT NULL DEFAULT 0,
+    token_count INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, category_id)
+);
+
+CREATE INDEX idx_category_model_tokens_user_token ON category_model_tokens (user_id, token);
+CREATE INDEX idx_transactions_user_date ON transactions (user_id, date DESC);
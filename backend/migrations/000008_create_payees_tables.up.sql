@@ -0,0 +1,25 @@
+CREATE TABLE payees (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, normalized_name)
+);
+
+-- Normalized spellings that identify a payee, e.g. "naivas westgate" for
+-- both "NAIVAS WESTGATE 0034" and "Naivas Westgate".
+CREATE TABLE payee_aliases (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL,
+    payee_id UUID NOT NULL REFERENCES payees(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, alias)
+);
+
+CREATE INDEX idx_payee_aliases_payee_id ON payee_aliases (payee_id);
+
+ALTER TABLE transactions ADD COLUMN payee_id UUID REFERENCES payees(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_payee_id ON transactions (payee_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transactions_category_date;
+DROP INDEX IF EXISTS idx_notifications_user_date;
+DROP TABLE IF EXISTS budget_alerts;
@@ -0,0 +1,14 @@
+-- One row per budget threshold crossed by a category in a budget period, so
+-- each threshold notifies at most once per period.
+CREATE TABLE budget_alerts (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    threshold INTEGER NOT NULL,
+    notification_id UUID REFERENCES notifications(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (category_id, period_start, threshold)
+);
+
+CREATE INDEX idx_notifications_user_date ON notifications (user_id, date DESC);
+CREATE INDEX idx_transactions_category_date ON transactions (category_id, date);
@@ -0,0 +1,14 @@
+-- A verified phone number lets alert notifications go out by SMS.
+ALTER TABLE users
+    ADD COLUMN phone_number VARCHAR(20),
+    ADD COLUMN phone_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Pending verification codes, one per user. Only a hash of the code is kept.
+CREATE TABLE phone_verifications (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    phone_number VARCHAR(20) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS notification_deliveries;
+DROP TABLE IF EXISTS notification_settings;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TYPE IF EXISTS digest_frequency;
+DROP TYPE IF EXISTS notification_channel;
+
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
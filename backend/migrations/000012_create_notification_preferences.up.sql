@@ -0,0 +1,38 @@
+-- The user's IANA time zone, used for quiet hours and digest periods.
+ALTER TABLE users ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+CREATE TYPE notification_channel AS ENUM ('in_app', 'email', 'sms');
+CREATE TYPE digest_frequency AS ENUM ('none', 'weekly', 'monthly');
+
+-- Per-user overrides of which channels each notification type uses. Missing
+-- rows fall back to the built-in defaults.
+CREATE TABLE notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type notification_type NOT NULL,
+    channel notification_channel NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, type, channel)
+);
+
+CREATE TABLE notification_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    quiet_hours_start TIME,
+    quiet_hours_end TIME,
+    digest_frequency digest_frequency NOT NULL DEFAULT 'weekly',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((quiet_hours_start IS NULL) = (quiet_hours_end IS NULL))
+);
+
+-- Email and SMS deliveries held back until the user's quiet hours end.
+CREATE TABLE notification_deliveries (
+    id UUID PRIMARY KEY,
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel notification_channel NOT NULL,
+    deliver_after TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (notification_id, channel)
+);
+
+CREATE INDEX idx_notification_deliveries_deliver_after ON notification_deliveries (deliver_after);
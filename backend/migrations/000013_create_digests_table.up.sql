@@ -0,0 +1,10 @@
+-- Spending digests already generated, one per user, frequency and period, so
+-- the digest job never sends the same period twice.
+CREATE TABLE digests (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    frequency digest_frequency NOT NULL,
+    period_start DATE NOT NULL,
+    notification_id UUID REFERENCES notifications(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, frequency, period_start)
+);
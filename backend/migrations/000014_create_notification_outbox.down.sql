@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id UUID PRIMARY KEY,
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel notification_channel NOT NULL,
+    deliver_after TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (notification_id, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_deliver_after ON notification_deliveries (deliver_after);
+
+INSERT INTO notification_deliveries (id, notification_id, user_id, channel, deliver_after, created_at)
+SELECT id, notification_id, user_id, channel, next_attempt_at, created_at
+FROM notification_outbox
+WHERE status = 'pending';
+
+DROP TABLE IF EXISTS notification_outbox;
+DROP TYPE IF EXISTS outbox_status;
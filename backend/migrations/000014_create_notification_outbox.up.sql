@@ -0,0 +1,33 @@
+CREATE TYPE outbox_status AS ENUM ('pending', 'sent', 'dead', 'cancelled');
+
+-- Email and SMS waiting to be sent. Rows are written in the same transaction
+-- as the notification they deliver, so nothing is lost if the process dies
+-- before sending; the outbox worker retries failures with backoff and moves
+-- rows that keep failing to 'dead' for an admin to inspect and replay.
+CREATE TABLE notification_outbox (
+    id UUID PRIMARY KEY,
+    notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel notification_channel NOT NULL,
+    -- A pre-rendered email (e.g. a digest) to send instead of the standard
+    -- alert email for the notification.
+    payload JSONB,
+    status outbox_status NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (notification_id, channel)
+);
+
+CREATE INDEX idx_notification_outbox_due ON notification_outbox (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_notification_outbox_status ON notification_outbox (status, updated_at);
+
+-- Deliveries held back by quiet hours move to the outbox.
+INSERT INTO notification_outbox (id, notification_id, user_id, channel, next_attempt_at, created_at)
+SELECT id, notification_id, user_id, channel, deliver_after, created_at
+FROM notification_deliveries;
+
+DROP TABLE notification_deliveries;
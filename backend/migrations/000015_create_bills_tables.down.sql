@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bill_payments;
+DROP TABLE IF EXISTS bills;
+DROP TYPE IF EXISTS recurrence_frequency;
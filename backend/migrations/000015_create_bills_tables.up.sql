@@ -0,0 +1,37 @@
+CREATE TYPE recurrence_frequency AS ENUM ('once', 'daily', 'weekly', 'monthly', 'yearly');
+
+-- Bills repeat every interval_count units of frequency from start_date until
+-- end_date. next_due_date is the earliest unpaid occurrence, or NULL once
+-- every occurrence has been paid.
+CREATE TABLE bills (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    amount NUMERIC(10, 2) NOT NULL,
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    payee_id UUID REFERENCES payees(id) ON DELETE SET NULL,
+    frequency recurrence_frequency NOT NULL DEFAULT 'monthly',
+    interval_count INTEGER NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    next_due_date DATE,
+    remind_days_before INTEGER NOT NULL DEFAULT 3 CHECK (remind_days_before >= 0),
+    -- The due date the last reminder was sent for, so each is sent once.
+    reminded_due_date DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_bills_user_next_due ON bills (user_id, next_due_date);
+
+-- One row per paid occurrence, linked to the transaction that settled it
+-- when there is one.
+CREATE TABLE bill_payments (
+    id UUID PRIMARY KEY,
+    bill_id UUID NOT NULL REFERENCES bills(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    due_date DATE NOT NULL,
+    transaction_id UUID UNIQUE REFERENCES transactions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (bill_id, due_date)
+);
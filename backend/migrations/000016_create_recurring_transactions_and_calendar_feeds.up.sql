@@ -0,0 +1,28 @@
+-- Income and expenses that repeat on a schedule, such as a salary or rent,
+-- following the same rules as bills.
+CREATE TABLE recurring_transactions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    description VARCHAR(255) NOT NULL,
+    amount NUMERIC(10, 2) NOT NULL,
+    type transaction_type NOT NULL,
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    payee_id UUID REFERENCES payees(id) ON DELETE SET NULL,
+    frequency recurrence_frequency NOT NULL DEFAULT 'monthly',
+    interval_count INTEGER NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    start_date DATE NOT NULL,
+    end_date DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_recurring_transactions_user_id ON recurring_transactions (user_id);
+
+-- One calendar feed per user, addressed by a secret token. Only a hash of
+-- the token is kept; rotating it replaces the row.
+CREATE TABLE calendar_feeds (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    last_accessed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
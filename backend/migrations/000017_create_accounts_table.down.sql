@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_transactions_account_id;
+ALTER TABLE recurring_transactions DROP COLUMN IF EXISTS account_id;
+ALTER TABLE bills DROP COLUMN IF EXISTS account_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS account_id;
+DROP TABLE IF EXISTS accounts;
+DROP TYPE IF EXISTS account_type;
@@ -0,0 +1,24 @@
+CREATE TYPE account_type AS ENUM ('cash', 'bank', 'mobile_money', 'savings', 'credit_card', 'other');
+
+-- Where money is held. An account's balance is its opening balance plus
+-- the income and less the expenses recorded against it.
+CREATE TABLE accounts (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    type account_type NOT NULL DEFAULT 'bank',
+    opening_balance NUMERIC(10, 2) NOT NULL DEFAULT 0,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+ALTER TABLE transactions
+    ADD COLUMN account_id UUID REFERENCES accounts(id) ON DELETE SET NULL;
+ALTER TABLE bills
+    ADD COLUMN account_id UUID REFERENCES accounts(id) ON DELETE SET NULL;
+ALTER TABLE recurring_transactions
+    ADD COLUMN account_id UUID REFERENCES accounts(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_account_id ON transactions (account_id);
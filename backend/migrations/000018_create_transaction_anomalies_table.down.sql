@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS transaction_anomalies;
+DROP TYPE IF EXISTS anomaly_kind;
@@ -0,0 +1,15 @@
+CREATE TYPE anomaly_kind AS ENUM ('category_outlier', 'payee_outlier', 'new_payee', 'duplicate');
+
+-- Why a transaction looked unusual, one row per reason, so each reason
+-- notifies at most once per transaction.
+CREATE TABLE transaction_anomalies (
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    kind anomaly_kind NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    explanation TEXT NOT NULL,
+    notification_id UUID REFERENCES notifications(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (transaction_id, kind)
+);
+
+CREATE INDEX idx_transaction_anomalies_user_created ON transaction_anomalies (user_id, created_at DESC);
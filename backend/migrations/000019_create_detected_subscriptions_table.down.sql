@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS detected_subscriptions;
+DROP TYPE IF EXISTS subscription_status;
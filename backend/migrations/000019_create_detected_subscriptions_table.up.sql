@@ -0,0 +1,22 @@
+CREATE TYPE subscription_status AS ENUM ('pending', 'confirmed', 'dismissed');
+
+-- Periodic charges spotted in a user's expenses, proposed until the user
+-- confirms them into a recurring transaction or dismisses them.
+CREATE TABLE detected_subscriptions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    payee_id UUID NOT NULL REFERENCES payees(id) ON DELETE CASCADE,
+    frequency recurrence_frequency NOT NULL,
+    amount NUMERIC(10, 2) NOT NULL,
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    account_id UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    charge_count INTEGER NOT NULL,
+    last_charged_on DATE NOT NULL,
+    next_expected_date DATE NOT NULL,
+    annual_cost NUMERIC(10, 2) NOT NULL,
+    status subscription_status NOT NULL DEFAULT 'pending',
+    recurring_transaction_id UUID REFERENCES recurring_transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, payee_id, frequency)
+);
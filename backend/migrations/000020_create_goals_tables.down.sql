@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_goal_contributions_transaction_id;
+DROP INDEX IF EXISTS idx_goal_contributions_goal_date;
+DROP INDEX IF EXISTS idx_goals_user_id;
+DROP TABLE IF EXISTS goal_milestones;
+DROP TABLE IF EXISTS goal_contributions;
+DROP TABLE IF EXISTS goals;
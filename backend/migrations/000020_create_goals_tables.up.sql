@@ -0,0 +1,43 @@
+-- Savings goals. A goal linked to an account or category is credited
+-- automatically by the transactions recorded against it.
+CREATE TABLE goals (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    target_amount NUMERIC(10, 2) NOT NULL,
+    target_date DATE,
+    account_id UUID REFERENCES accounts(id) ON DELETE SET NULL,
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    achieved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (account_id IS NULL OR category_id IS NULL)
+);
+
+-- Money put towards (or, when negative, taken from) a goal, either by a
+-- transaction or as a transfer recorded directly.
+CREATE TABLE goal_contributions (
+    id UUID PRIMARY KEY,
+    goal_id UUID NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(10, 2) NOT NULL,
+    date TIMESTAMP WITH TIME ZONE NOT NULL,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE CASCADE,
+    note VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (goal_id, transaction_id)
+);
+
+-- Progress milestones (percentages of the target) a goal has reached, so
+-- each notifies once.
+CREATE TABLE goal_milestones (
+    goal_id UUID NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+    percent INTEGER NOT NULL,
+    notification_id UUID REFERENCES notifications(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (goal_id, percent)
+);
+
+CREATE INDEX idx_goals_user_id ON goals (user_id);
+CREATE INDEX idx_goal_contributions_goal_date ON goal_contributions (goal_id, date);
+CREATE INDEX idx_goal_contributions_transaction_id ON goal_contributions (transaction_id);
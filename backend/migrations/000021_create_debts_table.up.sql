@@ -0,0 +1,14 @@
+-- Loans and credit owed, e.g. Fuliza, M-Shwari, bank loans and credit cards.
+-- apr is the annual percentage rate, e.g. 18.5.
+CREATE TABLE debts (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    balance NUMERIC(10, 2) NOT NULL CHECK (balance >= 0),
+    apr NUMERIC(6, 3) NOT NULL DEFAULT 0 CHECK (apr >= 0),
+    minimum_payment NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (minimum_payment >= 0),
+    due_day INTEGER NOT NULL CHECK (due_day BETWEEN 1 AND 31),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
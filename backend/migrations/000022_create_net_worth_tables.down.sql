@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS net_worth_snapshots;
+DROP TABLE IF EXISTS net_worth_valuations;
+DROP TABLE IF EXISTS net_worth_items;
+DROP TYPE IF EXISTS net_worth_kind;
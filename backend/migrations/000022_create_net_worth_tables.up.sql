@@ -0,0 +1,41 @@
+CREATE TYPE net_worth_kind AS ENUM ('asset', 'liability');
+
+-- Things owned or owed outside accounts and debts, such as property,
+-- vehicles or money lent to family, valued by hand.
+CREATE TABLE net_worth_items (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    kind net_worth_kind NOT NULL DEFAULT 'asset',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+-- What an item was worth from a date on, until its next valuation.
+CREATE TABLE net_worth_valuations (
+    id UUID PRIMARY KEY,
+    item_id UUID NOT NULL REFERENCES net_worth_items(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    value NUMERIC(14, 2) NOT NULL CHECK (value >= 0),
+    valued_on DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (item_id, valued_on)
+);
+
+-- Net worth at the end of each day, split by where it comes from.
+-- Liabilities are positive amounts owed.
+CREATE TABLE net_worth_snapshots (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    snapshot_date DATE NOT NULL,
+    account_assets NUMERIC(14, 2) NOT NULL,
+    item_assets NUMERIC(14, 2) NOT NULL,
+    total_assets NUMERIC(14, 2) NOT NULL,
+    account_liabilities NUMERIC(14, 2) NOT NULL,
+    debt_liabilities NUMERIC(14, 2) NOT NULL,
+    item_liabilities NUMERIC(14, 2) NOT NULL,
+    total_liabilities NUMERIC(14, 2) NOT NULL,
+    net_worth NUMERIC(14, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, snapshot_date)
+);
@@ -0,0 +1,6 @@
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS base_amount,
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS currency;
+ALTER TABLE accounts DROP COLUMN IF EXISTS currency;
+DROP TABLE IF EXISTS exchange_rates;
@@ -0,0 +1,28 @@
+-- Exchange rates by day: one unit of base_currency bought rate units of
+-- quote_currency on rate_date. Rates are cached here from the configured
+-- provider or imported by an administrator.
+CREATE TABLE exchange_rates (
+    base_currency VARCHAR(3) NOT NULL,
+    quote_currency VARCHAR(3) NOT NULL,
+    rate_date DATE NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    source VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (base_currency, quote_currency, rate_date)
+);
+
+-- Accounts and transactions carry their own currency, the user's by
+-- default. A transaction keeps the rate to the user's currency on its date
+-- and the amount converted at it, which summaries add up.
+ALTER TABLE accounts ADD COLUMN currency VARCHAR(3);
+UPDATE accounts a SET currency = u.currency FROM users u WHERE u.id = a.user_id;
+ALTER TABLE accounts ALTER COLUMN currency SET NOT NULL;
+
+ALTER TABLE transactions
+    ADD COLUMN currency VARCHAR(3),
+    ADD COLUMN exchange_rate NUMERIC(20, 10) NOT NULL DEFAULT 1 CHECK (exchange_rate > 0),
+    ADD COLUMN base_amount NUMERIC(14, 2);
+UPDATE transactions t SET currency = u.currency, base_amount = t.amount FROM users u WHERE u.id = t.user_id;
+ALTER TABLE transactions
+    ALTER COLUMN currency SET NOT NULL,
+    ALTER COLUMN base_amount SET NOT NULL;
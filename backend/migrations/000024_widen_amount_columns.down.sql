@@ -0,0 +1,29 @@
+-- Fails if any amount no longer fits the narrower columns; a third decimal
+-- place is rounded away.
+ALTER TABLE net_worth_snapshots
+    ALTER COLUMN account_assets TYPE NUMERIC(14, 2),
+    ALTER COLUMN item_assets TYPE NUMERIC(14, 2),
+    ALTER COLUMN total_assets TYPE NUMERIC(14, 2),
+    ALTER COLUMN account_liabilities TYPE NUMERIC(14, 2),
+    ALTER COLUMN debt_liabilities TYPE NUMERIC(14, 2),
+    ALTER COLUMN item_liabilities TYPE NUMERIC(14, 2),
+    ALTER COLUMN total_liabilities TYPE NUMERIC(14, 2),
+    ALTER COLUMN net_worth TYPE NUMERIC(14, 2);
+ALTER TABLE net_worth_valuations ALTER COLUMN value TYPE NUMERIC(14, 2);
+ALTER TABLE debts
+    ALTER COLUMN balance TYPE NUMERIC(10, 2),
+    ALTER COLUMN minimum_payment TYPE NUMERIC(10, 2);
+ALTER TABLE goal_contributions ALTER COLUMN amount TYPE NUMERIC(10, 2);
+ALTER TABLE goals ALTER COLUMN target_amount TYPE NUMERIC(10, 2);
+ALTER TABLE detected_subscriptions
+    ALTER COLUMN amount TYPE NUMERIC(10, 2),
+    ALTER COLUMN annual_cost TYPE NUMERIC(10, 2);
+ALTER TABLE accounts ALTER COLUMN opening_balance TYPE NUMERIC(10, 2);
+ALTER TABLE recurring_transactions ALTER COLUMN amount TYPE NUMERIC(10, 2);
+ALTER TABLE bills ALTER COLUMN amount TYPE NUMERIC(10, 2);
+ALTER TABLE budget_templates ALTER COLUMN global_budget TYPE NUMERIC(10, 2);
+ALTER TABLE transactions
+    ALTER COLUMN amount TYPE NUMERIC(10, 2),
+    ALTER COLUMN base_amount TYPE NUMERIC(14, 2);
+ALTER TABLE categories ALTER COLUMN budget_limit TYPE NUMERIC(10, 2);
+ALTER TABLE users ALTER COLUMN monthly_income TYPE NUMERIC(10, 2);
@@ -0,0 +1,30 @@
+-- NUMERIC(10, 2) tops out at 99,999,999.99, which a salary or land value in
+-- KES, UGX or NGN can pass. NUMERIC(19, 3) allows 16 digits before the point
+-- and the third decimal place of currencies such as KWD and BHD.
+ALTER TABLE users ALTER COLUMN monthly_income TYPE NUMERIC(19, 3);
+ALTER TABLE categories ALTER COLUMN budget_limit TYPE NUMERIC(19, 3);
+ALTER TABLE transactions
+    ALTER COLUMN amount TYPE NUMERIC(19, 3),
+    ALTER COLUMN base_amount TYPE NUMERIC(19, 3);
+ALTER TABLE budget_templates ALTER COLUMN global_budget TYPE NUMERIC(19, 3);
+ALTER TABLE bills ALTER COLUMN amount TYPE NUMERIC(19, 3);
+ALTER TABLE recurring_transactions ALTER COLUMN amount TYPE NUMERIC(19, 3);
+ALTER TABLE accounts ALTER COLUMN opening_balance TYPE NUMERIC(19, 3);
+ALTER TABLE detected_subscriptions
+    ALTER COLUMN amount TYPE NUMERIC(19, 3),
+    ALTER COLUMN annual_cost TYPE NUMERIC(19, 3);
+ALTER TABLE goals ALTER COLUMN target_amount TYPE NUMERIC(19, 3);
+ALTER TABLE goal_contributions ALTER COLUMN amount TYPE NUMERIC(19, 3);
+ALTER TABLE debts
+    ALTER COLUMN balance TYPE NUMERIC(19, 3),
+    ALTER COLUMN minimum_payment TYPE NUMERIC(19, 3);
+ALTER TABLE net_worth_valuations ALTER COLUMN value TYPE NUMERIC(19, 3);
+ALTER TABLE net_worth_snapshots
+    ALTER COLUMN account_assets TYPE NUMERIC(19, 3),
+    ALTER COLUMN item_assets TYPE NUMERIC(19, 3),
+    ALTER COLUMN total_assets TYPE NUMERIC(19, 3),
+    ALTER COLUMN account_liabilities TYPE NUMERIC(19, 3),
+    ALTER COLUMN debt_liabilities TYPE NUMERIC(19, 3),
+    ALTER COLUMN item_liabilities TYPE NUMERIC(19, 3),
+    ALTER COLUMN total_liabilities TYPE NUMERIC(19, 3),
+    ALTER COLUMN net_worth TYPE NUMERIC(19, 3);